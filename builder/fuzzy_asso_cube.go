@@ -0,0 +1,101 @@
+package builder
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/sbiemont/fugologic/fuzzy"
+	"github.com/sbiemont/fugologic/id"
+)
+
+// FamCube is a hypercube of consequents, given as nested maps
+// Each level is indexed by the terms of an input value (in the order of the inputs),
+// the leaves are the output terms (id.ID or string, empty meaning no rule, see. FamThen for several terms)
+// Eg.: FamCube{"a1": FamCube{"b1": FamCube{"c1": "out1"}}}
+type FamCube map[id.ID]any
+
+type FamAssoN interface {
+	Cube(cube FamCube) error
+	Tuples(tuples ...[]id.ID) error
+}
+
+type famAssoN struct {
+	pattern famPattern
+}
+
+// AssoN defines the rules pattern of any number of fuzzy values
+// if <a> and <b> and <c> ... then <out>
+// The options set the connective, the negated headers and additional output values (see. WithConnective, WithNegation, WithOutputs)
+func (fam *FuzzyAssoMatrix) AssoN(ifVals []*fuzzy.IDVal, thenVal *fuzzy.IDVal, opts ...FamOption) FamAssoN {
+	return famAssoN{
+		pattern: newFamPattern(fam, ifVals, thenVal, opts),
+	}
+}
+
+// Cube creates all rules of the hypercube, one rule for each non empty leaf
+// The algo is:
+//
+// - For i: values of a
+//   - For j: values of b
+//   - ...
+//     Rule = if (a[i]) and (b[j]) and ... then (cube[a[i]][b[j]]...)
+//
+// The terms of each level are processed sorted by identifier
+func (fv famAssoN) Cube(cube FamCube) error {
+	var rows [][]id.ID
+	var walk func(level FamCube, prefix []id.ID) error
+	walk = func(level FamCube, prefix []id.ID) error {
+		keys := make([]id.ID, 0, len(level))
+		for key := range level {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			return keys[i] < keys[j]
+		})
+
+		for _, key := range keys {
+			path := append(append([]id.ID{}, prefix...), key)
+			switch cell := level[key].(type) {
+			case FamCube:
+				if err := walk(cell, path); err != nil {
+					return err
+				}
+			case map[id.ID]any:
+				if err := walk(cell, path); err != nil {
+					return err
+				}
+			case id.ID:
+				rows = append(rows, append(path, cell))
+			case string:
+				rows = append(rows, append(path, id.ID(cell)))
+			default:
+				return fmt.Errorf("rule, unexpected cell %s of type %T", describePath(path), cell)
+			}
+		}
+		return nil
+	}
+	if err := walk(cube, nil); err != nil {
+		return err
+	}
+	return fv.Tuples(rows...)
+}
+
+// Tuples creates one rule for each tuple: the terms of the input values (in the order of the inputs),
+// then the output terms (empty meaning no rule, see. FamThen for several terms)
+// Eg.: []id.ID{"a1", "b1", "c1", "out1"}
+func (fv famAssoN) Tuples(tuples ...[]id.ID) error {
+	if len(fv.pattern.ifVals) == 0 {
+		return fmt.Errorf("'if' statement, no input value")
+	}
+	return fv.pattern.add(tuples)
+}
+
+// describePath describes a list of terms. Eg.: [a1 b2]
+func describePath(path []id.ID) string {
+	texts := make([]string, len(path))
+	for i, uuid := range path {
+		texts[i] = string(uuid)
+	}
+	return "[" + strings.Join(texts, " ") + "]"
+}
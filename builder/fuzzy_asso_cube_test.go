@@ -0,0 +1,123 @@
+package builder
+
+import (
+	"testing"
+
+	"github.com/sbiemont/fugologic/fuzzy"
+	"github.com/sbiemont/fugologic/id"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestFuzzyAssoCube(t *testing.T) {
+	Convey("fam n-dimensional", t, func() {
+		fvA := newTestVals("a", "a1", "a2")
+		fvB := newTestVals("b", "b1", "b2")
+		fvC := newTestVals("c", "c1", "c2", "c3")
+		fvD := newTestVals("d", "d1", "d2", "d3")
+
+		Convey("when cube", func() {
+			bld := newTestFAM()
+			err := bld.
+				AssoN([]*fuzzy.IDVal{fvA, fvB, fvC}, fvD).
+				Cube(FamCube{
+					"a1": FamCube{
+						"b1": FamCube{"c1": "d1", "c2": "d1", "c3": "d2"},
+						"b2": FamCube{"c1": "d1", "c2": "d2", "c3": ""},
+					},
+					"a2": FamCube{
+						"b1": FamCube{"c1": id.ID("d2"), "c2": "d3"},
+					},
+				})
+			So(err, ShouldBeNil)
+
+			// Sorted by terms
+			So(describe(bld.rules), ShouldResemble, []string{
+				"IF a1 AND b1 AND c1 THEN d1",
+				"IF a1 AND b1 AND c2 THEN d1",
+				"IF a1 AND b1 AND c3 THEN d2",
+				"IF a1 AND b2 AND c1 THEN d1",
+				"IF a1 AND b2 AND c2 THEN d2",
+				"IF a2 AND b1 AND c1 THEN d2",
+				"IF a2 AND b1 AND c2 THEN d3",
+			})
+			So(bld.rules[0].String(), ShouldEqual, "IF a1 AND b1 AND c1 THEN d1")
+
+			eng, err := bld.Engine()
+			So(err, ShouldBeNil)
+			So(eng.Rules(), ShouldHaveLength, 7)
+		})
+
+		Convey("when tuples", func() {
+			bld := newTestFAM()
+			err := bld.
+				AssoN([]*fuzzy.IDVal{fvA, fvB, fvC}, fvD).
+				Tuples(
+					[]id.ID{"a2", "b2", "c3", "d3"},
+					[]id.ID{"a1", "b1", "c1", "d1"},
+					[]id.ID{"a1", "b1", "c2", ""},
+				)
+			So(err, ShouldBeNil)
+
+			// In the order of the tuples
+			So(bld.rules, ShouldHaveLength, 2)
+			So(bld.rules[0].String(), ShouldEqual, "IF a2 AND b2 AND c3 THEN d3")
+			So(bld.rules[1].String(), ShouldEqual, "IF a1 AND b1 AND c1 THEN d1")
+		})
+
+		Convey("when ko", func() {
+			asso := func() FamAssoN {
+				bld := newTestFAM()
+				return bld.AssoN([]*fuzzy.IDVal{fvA, fvB, fvC}, fvD)
+			}
+
+			Convey("when sizes differ", func() {
+				err := asso().Tuples([]id.ID{"a1", "b1", "d1"})
+				So(err, ShouldBeError, "rule, sizes should be the same (found: 3, expected: 4)")
+
+				err = asso().Cube(FamCube{"a1": FamCube{"b1": "d1"}})
+				So(err, ShouldBeError, "rule, sizes should be the same (found: 3, expected: 4)")
+
+				err = asso().Cube(FamCube{"a1": FamCube{"b1": FamCube{"c1": FamCube{"d1": "d1"}}}})
+				So(err, ShouldBeError, "rule, sizes should be the same (found: 5, expected: 4)")
+			})
+
+			Convey("when duplicated headers", func() {
+				err := asso().Tuples(
+					[]id.ID{"a1", "b1", "c1", "d1"},
+					[]id.ID{"a1", "b1", "c1", "d2"},
+				)
+				So(err, ShouldBeError, "'if' statement, duplicated headers found ([a1 b1 c1])")
+			})
+
+			Convey("when unknown terms", func() {
+				err := asso().Tuples([]id.ID{"a1", "b0", "c1", "d1"})
+				So(err, ShouldBeError, "'and' statement, cannot find b0 from b")
+
+				err = asso().Cube(FamCube{"a1": FamCube{"b1": FamCube{"c1": "d7"}}})
+				So(err, ShouldBeError, "'then' statement, cannot find d7 from d")
+			})
+
+			Convey("when wrong cell", func() {
+				err := asso().Cube(FamCube{"a1": FamCube{"b1": 3}})
+				So(err, ShouldBeError, "rule, unexpected cell [a1 b1] of type int")
+			})
+
+			Convey("when no input", func() {
+				bld := newTestFAM()
+				err := bld.AssoN(nil, fvD).Tuples()
+				So(err, ShouldBeError, "'if' statement, no input value")
+			})
+
+			Convey("when error, no rule added", func() {
+				bld := newTestFAM()
+				err := bld.AssoN([]*fuzzy.IDVal{fvA, fvB, fvC}, fvD).Tuples(
+					[]id.ID{"a1", "b1", "c1", "d1"},
+					[]id.ID{"a1", "b1", "c9", "d1"},
+				)
+				So(err, ShouldNotBeNil)
+				So(bld.rules, ShouldBeEmpty)
+			})
+		})
+	})
+}
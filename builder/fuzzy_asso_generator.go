@@ -0,0 +1,88 @@
+package builder
+
+import (
+	"fmt"
+
+	"github.com/sbiemont/fugologic/fuzzy"
+	"github.com/sbiemont/fugologic/id"
+)
+
+// FamGenerator generates rules over the cartesian product of the terms of input values
+// Terms are identified by their index in the declared order of the value (see. fuzzy.NewIDValOrdered and fuzzy.IDVal.Terms),
+// values without declared order are rejected
+type FamGenerator struct {
+	fam    *FuzzyAssoMatrix
+	ifVals []*fuzzy.IDVal
+}
+
+// ForAll starts a generator over all combinations of the terms of the input values
+// Eg.: ForAll(fvErr, fvDErr).Then(fvFrc, func(i, j int) int { return i + j - 3 })
+func (fam *FuzzyAssoMatrix) ForAll(ifVals ...*fuzzy.IDVal) FamGenerator {
+	return FamGenerator{
+		fam:    fam,
+		ifVals: ifVals,
+	}
+}
+
+// Then generates one rule for each couple of terms of the 2 input values:
+// if a[i] and b[j] then c[fct(i, j)]
+// An index of output out of range means no rule
+// The options set the connective of the inputs (see. WithConnective)
+func (gen FamGenerator) Then(thenVal *fuzzy.IDVal, fct func(i, j int) int, opts ...FamOption) error {
+	if len(gen.ifVals) != 2 {
+		return fmt.Errorf("generator: 2 input values expected (found: %d)", len(gen.ifVals))
+	}
+	return gen.ThenN(thenVal, func(indices ...int) int {
+		return fct(indices[0], indices[1])
+	}, opts...)
+}
+
+// ThenN generates one rule for each combination of terms of the input values:
+// if a[i] and b[j] and ... then out[fct(i, j, ...)]
+// Combinations are processed in lexicographic order of the indices, an index of output out of range means no rule
+// The options set the connective of the inputs (see. WithConnective)
+func (gen FamGenerator) ThenN(thenVal *fuzzy.IDVal, fct func(indices ...int) int, opts ...FamOption) error {
+	if len(gen.ifVals) == 0 {
+		return fmt.Errorf("generator: no input value")
+	}
+
+	for _, idVal := range append(append([]*fuzzy.IDVal{}, gen.ifVals...), thenVal) {
+		if !idVal.Ordered() {
+			return fmt.Errorf("generator: value %s has no declared order of terms (see. fuzzy.NewIDValOrdered)", idVal.ID())
+		}
+	}
+
+	terms := make([][]fuzzy.IDSet, len(gen.ifVals))
+	for i, ifVal := range gen.ifVals {
+		terms[i] = ifVal.Terms()
+		if len(terms[i]) == 0 {
+			return fmt.Errorf("generator: value %s has no term", ifVal.ID())
+		}
+	}
+	thenTerms := thenVal.Terms()
+
+	// Iterate over all combinations, the last index changing first
+	var tuples [][]id.ID
+	indices := make([]int, len(terms))
+	for {
+		if k := fct(indices...); k >= 0 && k < len(thenTerms) {
+			tuple := make([]id.ID, 0, len(indices)+1)
+			for i, index := range indices {
+				tuple = append(tuple, terms[i][index].ID())
+			}
+			tuples = append(tuples, append(tuple, thenTerms[k].ID()))
+		}
+
+		pos := len(indices) - 1
+		for pos >= 0 && indices[pos] == len(terms[pos])-1 {
+			indices[pos] = 0
+			pos--
+		}
+		if pos < 0 {
+			break
+		}
+		indices[pos]++
+	}
+
+	return newFamPattern(gen.fam, gen.ifVals, thenVal, opts).add(tuples)
+}
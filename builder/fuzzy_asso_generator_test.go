@@ -0,0 +1,110 @@
+package builder
+
+import (
+	"testing"
+
+	"github.com/sbiemont/fugologic/crisp"
+	"github.com/sbiemont/fugologic/fuzzy"
+	"github.com/sbiemont/fugologic/id"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestFamGenerator(t *testing.T) {
+	Convey("generator", t, func() {
+		fvA := newTestVals("a", "a0", "a1", "a2")
+		fvB := newTestVals("b", "b0", "b1")
+		fvC := newTestVals("c", "c0", "c1", "c2")
+		fvD := newTestVals("d", "d0", "d1", "d2", "d3")
+
+		rules := func(bld FuzzyAssoMatrix) []string {
+			result := make([]string, len(bld.rules))
+			for i, rule := range bld.rules {
+				result[i] = rule.String()
+			}
+			return result
+		}
+
+		Convey("when 2 inputs", func() {
+			bld := newTestFAM()
+			err := bld.ForAll(fvA, fvB).Then(fvC, func(i, j int) int { return i - j })
+			So(err, ShouldBeNil)
+
+			// Lexicographic order, out of range means no rule
+			So(rules(bld), ShouldResemble, []string{
+				"IF a0 AND b0 THEN c0",
+				"IF a1 AND b0 THEN c1",
+				"IF a1 AND b1 THEN c0",
+				"IF a2 AND b0 THEN c2",
+				"IF a2 AND b1 THEN c1",
+			})
+		})
+
+		Convey("when terms not sorted by identifier", func() {
+			// Output = -(err + 2*derr) around ZE, saturated
+			fvErr := newTestVals("err", "NB", "NS", "ZE", "PS", "PB")
+			fvDErr := newTestVals("derr", "NB", "ZE", "PB")
+			fvFrc := newTestVals("frc", "NB", "NS", "ZE", "PS", "PB")
+			bld := newTestFAM()
+			err := bld.ForAll(fvErr, fvDErr).Then(fvFrc, func(i, j int) int {
+				return min(max(2-((i-2)+2*(j-1)), 0), 4)
+			})
+			So(err, ShouldBeNil)
+			So(rules(bld), ShouldResemble, []string{
+				"IF NB AND NB THEN PB",
+				"IF NB AND ZE THEN PB",
+				"IF NB AND PB THEN ZE",
+				"IF NS AND NB THEN PB",
+				"IF NS AND ZE THEN PS",
+				"IF NS AND PB THEN NS",
+				"IF ZE AND NB THEN PB",
+				"IF ZE AND ZE THEN ZE",
+				"IF ZE AND PB THEN NB",
+				"IF PS AND NB THEN PS",
+				"IF PS AND ZE THEN NS",
+				"IF PS AND PB THEN NB",
+				"IF PB AND NB THEN ZE",
+				"IF PB AND ZE THEN NB",
+				"IF PB AND PB THEN NB",
+			})
+		})
+
+		Convey("when n inputs and options", func() {
+			bld := newTestFAM()
+			err := bld.ForAll(fvA, fvB, fvC).ThenN(fvD, func(indices ...int) int {
+				if indices[0] != 2 {
+					return -1
+				}
+				return indices[0] + indices[1] - indices[2]
+			}, WithConnective(fuzzy.ConnectiveOr))
+			So(err, ShouldBeNil)
+			So(rules(bld), ShouldResemble, []string{
+				"IF a2 OR b0 OR c0 THEN d2",
+				"IF a2 OR b0 OR c1 THEN d1",
+				"IF a2 OR b0 OR c2 THEN d0",
+				"IF a2 OR b1 OR c0 THEN d3",
+				"IF a2 OR b1 OR c1 THEN d2",
+				"IF a2 OR b1 OR c2 THEN d1",
+			})
+		})
+
+		Convey("when error", func() {
+			bld := newTestFAM()
+			err := bld.ForAll(fvA).Then(fvC, func(i, j int) int { return 0 })
+			So(err, ShouldBeError, "generator: 2 input values expected (found: 1)")
+
+			err = bld.ForAll().ThenN(fvC, func(indices ...int) int { return 0 })
+			So(err, ShouldBeError, "generator: no input value")
+
+			err = bld.ForAll(fvA, newTestVals("e")).Then(fvC, func(i, j int) int { return 0 })
+			So(err, ShouldBeError, "generator: value e has no term")
+
+			// Order of the terms not declared
+			fvX, errX := fuzzy.NewIDVal("x", crisp.Set{}, map[id.ID]fuzzy.Set{"x1": nil})
+			So(errX, ShouldBeNil)
+			err = bld.ForAll(fvA, fvB).Then(fvX, func(i, j int) int { return 0 })
+			So(err, ShouldBeError, "generator: value x has no declared order of terms (see. fuzzy.NewIDValOrdered)")
+			So(bld.rules, ShouldBeEmpty)
+		})
+	})
+}
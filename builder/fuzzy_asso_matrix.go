@@ -2,6 +2,9 @@ package builder
 
 import (
 	"fmt"
+	"slices"
+	"sort"
+	"strings"
 
 	"github.com/sbiemont/fugologic/fuzzy"
 	"github.com/sbiemont/fugologic/id"
@@ -47,29 +50,32 @@ func (fam *FuzzyAssoMatrix) add(rule fuzzy.Rule) {
 }
 
 // Engine builds an engine from the configuration
-func (fam FuzzyAssoMatrix) Engine() (fuzzy.Engine, error) {
-	return fuzzy.NewEngine(fam.rules, fam.cfg.agg, fam.cfg.defuzz)
+// The options set the identifier and the description of the engine (see. fuzzy.WithID, fuzzy.WithDescription)
+func (fam FuzzyAssoMatrix) Engine(opts ...fuzzy.EngineOption) (fuzzy.Engine, error) {
+	return fuzzy.NewEngine(fam.rules, fam.cfg.agg, fam.cfg.defuzz, opts...)
 }
 
 type FamAsso interface {
 	Matrix(ifSets []id.ID, andThenSets map[id.ID][]id.ID) error
+	Rows(ifSets []id.ID, rows ...FamRow) error
+}
+
+// FamRow is a row of a matrix: a term of the second value, and one cell for each term of the first value
+type FamRow struct {
+	Header id.ID
+	Cells  []id.ID
 }
 
 type famAsso struct {
-	fam     *FuzzyAssoMatrix
-	ifVal   *fuzzy.IDVal
-	andVal  *fuzzy.IDVal
-	thenVal *fuzzy.IDVal
+	pattern famPattern
 }
 
 // Asso defines the rules pattern of fuzzy values
 // if <a> and <b> then <c>
-func (fam *FuzzyAssoMatrix) Asso(ifVal, andVal, thenVal *fuzzy.IDVal) FamAsso {
+// The options set the connective, the negated headers and additional output values (see. WithConnective, WithNegation, WithOutputs)
+func (fam *FuzzyAssoMatrix) Asso(ifVal, andVal, thenVal *fuzzy.IDVal, opts ...FamOption) FamAsso {
 	return famAsso{
-		fam:     fam,
-		ifVal:   ifVal,
-		andVal:  andVal,
-		thenVal: thenVal,
+		pattern: newFamPattern(fam, []*fuzzy.IDVal{ifVal, andVal}, thenVal, opts),
 	}
 }
 
@@ -79,74 +85,264 @@ func (fam *FuzzyAssoMatrix) Asso(ifVal, andVal, thenVal *fuzzy.IDVal) FamAsso {
 // - For i: values of a
 //   - For j: values of b
 //     Rule = if (a[i]) and (b[j]) then (c[i][j])
+//
+// The rows are processed sorted by header (see. Rows to keep a given order)
 func (fv famAsso) Matrix(ifSets []id.ID, andThenSets map[id.ID][]id.ID) error {
-	// Fetch for id-set within an id-val
-	// Use a map to fetch data only once
-	type mapID map[id.ID]fuzzy.IDSet
-	fetch := func(info string, v *fuzzy.IDVal, uuid id.ID, mp mapID) (fuzzy.IDSet, error) {
-		// Fetch data from map
-		premise, exists := mp[uuid]
-		if exists {
-			return premise, nil
-		}
-		// Fetch data from value
-		premise, ok := v.Fetch(uuid)
-		if !ok {
-			return fuzzy.IDSet{}, fmt.Errorf("'%s' statement, cannot find %s from %s", info, uuid, v.ID())
-		}
-		// Store and return data
-		mp[uuid] = premise
-		return premise, nil
+	rows := make([]FamRow, 0, len(andThenSets))
+	for andID, thenSets := range andThenSets {
+		rows = append(rows, FamRow{Header: andID, Cells: thenSets})
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		return rows[i].Header < rows[j].Header
+	})
+	return fv.Rows(ifSets, rows...)
+}
+
+// Rows creates all rules like Matrix, in the order of the columns, then of the rows
+//
+// - For i: values of a
+//   - For j: rows
+//     Rule = if (a[i]) and (row[j].Header) then (row[j].Cells[i])
+func (fv famAsso) Rows(ifSets []id.ID, rows ...FamRow) error {
+	n := len(ifSets)
+	found := make(map[id.ID]struct{})
+	for _, ifID := range ifSets {
+		found[ifID] = struct{}{}
+	}
+	if len(found) != n {
+		return fmt.Errorf("'if' statement, duplicated headers found")
 	}
 
 	// Control sizes
-	checkSize := func(actual, expected int) error {
-		if actual != expected {
-			return fmt.Errorf("rule, sizes should be the same (found: %d, expected: %d)", actual, expected)
+	for _, row := range rows {
+		if len(row.Cells) != n {
+			return fmt.Errorf("rule, sizes should be the same (found: %d, expected: %d)", len(row.Cells), n)
 		}
-		return nil
 	}
 
-	ifMap := make(mapID)
-	andMap := make(mapID)
-	thenMap := make(mapID)
-
-	n := len(ifSets)
+	var tuples [][]id.ID
 	for i, ifID := range ifSets {
-		ifSet, errIf := fetch("if", fv.ifVal, ifID, ifMap)
-		if errIf != nil {
-			return errIf
+		for _, row := range rows {
+			tuples = append(tuples, []id.ID{ifID, row.Header, row.Cells[i]})
+		}
+	}
+	return fv.pattern.add(tuples)
+}
+
+// FamOption sets an optional behaviour of an association
+type FamOption func(pattern *famPattern)
+
+// WithConnective sets the connective of the input values (default: fuzzy.ConnectiveAnd)
+func WithConnective(connective fuzzy.Connective) FamOption {
+	return func(pattern *famPattern) {
+		pattern.connective = connective
+	}
+}
+
+// WithNegation allows negated headers (see. FamNot)
+// Without this option, a header is a plain term identifier
+func WithNegation() FamOption {
+	return func(pattern *famPattern) {
+		pattern.negation = true
+	}
+}
+
+// WithOutputs adds output values: a cell can then list terms of several outputs (see. FamThen)
+// Without this option, a cell is a plain term identifier of the output value
+func WithOutputs(thenVals ...*fuzzy.IDVal) FamOption {
+	return func(pattern *famPattern) {
+		pattern.thenVals = append(pattern.thenVals, thenVals...)
+		pattern.multiple = true
+	}
+}
+
+// FamNot negates a header term. Eg.: FamNot("a1") is written "!a1"
+// Requires the option WithNegation
+func FamNot(term id.ID) id.ID {
+	return "!" + term
+}
+
+// FamThen lists several output terms in a cell. Eg.: FamThen("c1", "d2") is written "c1, d2"
+// A term can be prefixed by its value to remove any ambiguity. Eg.: "d:low"
+// Requires the option WithOutputs
+func FamThen(terms ...id.ID) id.ID {
+	texts := make([]string, len(terms))
+	for i, term := range terms {
+		texts[i] = string(term)
+	}
+	return id.ID(strings.Join(texts, ", "))
+}
+
+// famPattern describes the rules of an association: input values connected together, and output values
+type famPattern struct {
+	fam        *FuzzyAssoMatrix
+	ifVals     []*fuzzy.IDVal
+	thenVals   []*fuzzy.IDVal
+	connective fuzzy.Connective
+	negation   bool // headers can be negated (eg.: "!a1")
+	multiple   bool // cells can list several output terms (eg.: "c1, d:low")
+}
+
+// newFamPattern creates a pattern using the options
+func newFamPattern(fam *FuzzyAssoMatrix, ifVals []*fuzzy.IDVal, thenVal *fuzzy.IDVal, opts []FamOption) famPattern {
+	pattern := famPattern{
+		fam:        fam,
+		ifVals:     ifVals,
+		thenVals:   []*fuzzy.IDVal{thenVal},
+		connective: fuzzy.ConnectiveAnd,
+	}
+	for _, opt := range opts {
+		opt(&pattern)
+	}
+	return pattern
+}
+
+// add one rule for each tuple: the terms of the input values, then the cell of output terms
+// All tuples are checked before adding the rules
+func (pt famPattern) add(tuples [][]id.ID) error {
+	ifMaps := make([]termCache, len(pt.ifVals))
+	for i := range ifMaps {
+		ifMaps[i] = make(termCache)
+	}
+	thenMaps := make([]termCache, len(pt.thenVals))
+	for i := range thenMaps {
+		thenMaps[i] = make(termCache)
+	}
+
+	var rules []fuzzy.Rule
+	found := make(map[string]struct{})
+	n := len(pt.ifVals) + 1
+	for _, tuple := range tuples {
+		if len(tuple) != n {
+			return fmt.Errorf("rule, sizes should be the same (found: %d, expected: %d)", len(tuple), n)
 		}
 
-		for andID, thenSets := range andThenSets {
-			errSize := checkSize(len(thenSets), n)
-			if errSize != nil {
-				return errSize
+		key := describePath(tuple[:n-1])
+		if _, exists := found[key]; exists {
+			return fmt.Errorf("'if' statement, duplicated headers found (%s)", key)
+		}
+		found[key] = struct{}{}
+
+		premises := make([]fuzzy.Premise, n-1)
+		for i, ifID := range tuple[:n-1] {
+			info := "if"
+			if i > 0 {
+				info = string(pt.connective)
 			}
-			andSet, errAnd := fetch("and", fv.andVal, andID, andMap)
-			if errAnd != nil {
-				return errAnd
+			negated := pt.negation && strings.HasPrefix(string(ifID), "!")
+			if negated {
+				ifID = ifID[1:]
 			}
-			if thenSets[i].Empty() {
-				// No rule is the "then" statement is empty
-				continue
+			ifSet, err := ifMaps[i].fetch(info, pt.ifVals[i], ifID)
+			if err != nil {
+				return err
 			}
-			thenSet, errThen := fetch("then", fv.thenVal, thenSets[i], thenMap)
-			if errThen != nil {
-				return errThen
+			premises[i] = ifSet
+			if negated {
+				premises[i] = fuzzy.NewExpression([]fuzzy.Premise{ifSet}, nil).Not()
 			}
+		}
 
-			fv.fam.add(fuzzy.NewRule(
-				fuzzy.NewExpression([]fuzzy.Premise{ifSet, andSet}, fv.fam.cfg.optr.And),
-				fv.fam.cfg.impl,
-				[]fuzzy.IDSet{thenSet},
-			))
+		thenSets, err := pt.consequents(tuple[n-1], thenMaps)
+		if err != nil {
+			return err
+		}
+		if len(thenSets) == 0 {
+			// No rule if the "then" statement is empty
+			continue
 		}
-	}
 
-	if len(ifMap) != n {
-		return fmt.Errorf("'if' statement, duplicated headers found")
+		rules = append(rules, fuzzy.NewRule(
+			fuzzy.NewOperatorExpression(premises, pt.fam.cfg.optr, pt.connective),
+			pt.fam.cfg.impl,
+			thenSets,
+		))
 	}
 
+	for _, rule := range rules {
+		pt.fam.add(rule)
+	}
 	return nil
 }
+
+// consequents fetches the output terms of a cell, an empty cell meaning no rule
+// With several outputs, the terms are separated by commas and searched in all output values,
+// unless prefixed by their value (eg.: "d:low")
+func (pt famPattern) consequents(cell id.ID, thenMaps []termCache) ([]fuzzy.IDSet, error) {
+	if !pt.multiple {
+		if cell.Empty() {
+			return nil, nil
+		}
+		thenSet, err := thenMaps[0].fetch("then", pt.thenVals[0], cell)
+		if err != nil {
+			return nil, err
+		}
+		return []fuzzy.IDSet{thenSet}, nil
+	}
+
+	var result []fuzzy.IDSet
+	for _, text := range strings.Split(string(cell), ",") {
+		term := id.ID(strings.TrimSpace(text))
+		if term.Empty() {
+			continue
+		}
+
+		// Qualified term
+		if valID, setID, ok := strings.Cut(string(term), ":"); ok {
+			i := slices.IndexFunc(pt.thenVals, func(v *fuzzy.IDVal) bool { return v.ID() == id.ID(valID) })
+			if i < 0 {
+				return nil, fmt.Errorf("'then' statement, cannot find value %s", valID)
+			}
+			thenSet, err := thenMaps[i].fetch("then", pt.thenVals[i], id.ID(setID))
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, thenSet)
+			continue
+		}
+
+		// Search in all values
+		var matches []int
+		for i, thenVal := range pt.thenVals {
+			if _, ok := thenVal.Fetch(term); ok {
+				matches = append(matches, i)
+			}
+		}
+		switch len(matches) {
+		case 0:
+			ids := make([]string, len(pt.thenVals))
+			for i, thenVal := range pt.thenVals {
+				ids[i] = string(thenVal.ID())
+			}
+			return nil, fmt.Errorf("'then' statement, cannot find %s from %s", term, strings.Join(ids, ", "))
+		case 1:
+			thenSet, _ := thenMaps[matches[0]].fetch("then", pt.thenVals[matches[0]], term)
+			result = append(result, thenSet)
+		default:
+			return nil, fmt.Errorf("'then' statement, ambiguous term %s (found in %s and %s), use <value>:<term>",
+				term, pt.thenVals[matches[0]].ID(), pt.thenVals[matches[1]].ID())
+		}
+	}
+	return result, nil
+}
+
+// termCache stores the id-sets already fetched from an id-val
+// Use a map to fetch data only once
+type termCache map[id.ID]fuzzy.IDSet
+
+// fetch an id-set within an id-val
+func (cache termCache) fetch(info string, v *fuzzy.IDVal, uuid id.ID) (fuzzy.IDSet, error) {
+	// Fetch data from map
+	premise, exists := cache[uuid]
+	if exists {
+		return premise, nil
+	}
+	// Fetch data from value
+	premise, ok := v.Fetch(uuid)
+	if !ok {
+		return fuzzy.IDSet{}, fmt.Errorf("'%s' statement, cannot find %s from %s", info, uuid, v.ID())
+	}
+	// Store and return data
+	cache[uuid] = premise
+	return premise, nil
+}
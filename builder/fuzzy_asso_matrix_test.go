@@ -1,9 +1,7 @@
 package builder
 
 import (
-	"fmt"
 	"sort"
-	"strings"
 	"testing"
 
 	"github.com/sbiemont/fugologic/crisp"
@@ -22,7 +20,7 @@ func newTestFAM() FuzzyAssoMatrix {
 	)
 }
 
-// Create a fuzzy value, a list of fuzzy sets and link both
+// Create a fuzzy value, a list of fuzzy sets (in this order) and link both
 func newTestVals(val id.ID, sets ...id.ID) *fuzzy.IDVal {
 	// Prepare input
 	fuzzySet := func(x float64) float64 { return x }
@@ -32,25 +30,16 @@ func newTestVals(val id.ID, sets ...id.ID) *fuzzy.IDVal {
 	}
 
 	// New value
-	fv, err := fuzzy.NewIDVal(val, crisp.Set{}, idSets)
+	fv, err := fuzzy.NewIDValOrdered(val, crisp.Set{}, sets, idSets)
 	So(err, ShouldBeNil)
 	return fv
 }
 
-func ids(sets []fuzzy.IDSet) []string {
-	ids := make([]string, len(sets))
-	for i, set := range sets {
-		ids[i] = string(set.ID())
-	}
-	return ids
-}
-
-// Compact id from id-sets into string "id1.id2.id3"
-func compactIDs(rules []fuzzy.Rule) []string {
+// Describe the rules, sorted
+func describe(rules []fuzzy.Rule) []string {
 	result := make([]string, len(rules))
 	for i, rule := range rules {
-		inputs, outputs := rule.IO()
-		result[i] = fmt.Sprintf("%s=>%s", strings.Join(ids(inputs), "."), strings.Join(ids(outputs), "."))
+		result[i] = rule.String()
 	}
 	sort.Strings(result)
 	return result
@@ -78,13 +67,13 @@ func TestFuzzyAssoMatrix(t *testing.T) {
 				So(bld.rules, ShouldHaveLength, 6)
 
 				// Sort result because of rules random order
-				So(compactIDs(bld.rules), ShouldResemble, []string{
-					"a1.b1=>c1",
-					"a1.b2=>c3",
-					"a1.b3=>c3",
-					"a2.b1=>c2",
-					"a2.b2=>c4",
-					"a2.b3=>c2",
+				So(describe(bld.rules), ShouldResemble, []string{
+					"IF a1 AND b1 THEN c1",
+					"IF a1 AND b2 THEN c3",
+					"IF a1 AND b3 THEN c3",
+					"IF a2 AND b1 THEN c2",
+					"IF a2 AND b2 THEN c4",
+					"IF a2 AND b3 THEN c2",
 				})
 			})
 
@@ -103,12 +92,168 @@ func TestFuzzyAssoMatrix(t *testing.T) {
 				So(bld.rules, ShouldHaveLength, 4)
 
 				// Sort result because of rules random order
-				So(compactIDs(bld.rules), ShouldResemble, []string{
-					"a1.b1=>c1",
-					"a1.b2=>c3",
-					"a2.b1=>c2",
-					"a2.b3=>c2",
+				So(describe(bld.rules), ShouldResemble, []string{
+					"IF a1 AND b1 THEN c1",
+					"IF a1 AND b2 THEN c3",
+					"IF a2 AND b1 THEN c2",
+					"IF a2 AND b3 THEN c2",
+				})
+			})
+		})
+
+		Convey("when ordered", func() {
+			Convey("when matrix", func() {
+				bld := newTestFAM()
+				err := bld.
+					Asso(fvA, fvB, fvC).
+					Matrix(
+						[]id.ID{"a2", "a1"},
+						map[id.ID][]id.ID{
+							"b3": {"c1", "c2"},
+							"b1": {"c3", "c4"},
+						})
+				So(err, ShouldBeNil)
+
+				// Columns, then rows sorted by header
+				rules := make([]string, len(bld.rules))
+				for i, rule := range bld.rules {
+					rules[i] = rule.String()
+				}
+				So(rules, ShouldResemble, []string{
+					"IF a2 AND b1 THEN c3",
+					"IF a2 AND b3 THEN c1",
+					"IF a1 AND b1 THEN c4",
+					"IF a1 AND b3 THEN c2",
+				})
+			})
+
+			Convey("when rows", func() {
+				bld := newTestFAM()
+				err := bld.
+					Asso(fvA, fvB, fvC).
+					Rows(
+						[]id.ID{"a1", "a2"},
+						FamRow{Header: "b3", Cells: []id.ID{"c1", ""}},
+						FamRow{Header: "b1", Cells: []id.ID{"c3", "c4"}},
+					)
+				So(err, ShouldBeNil)
+
+				rules := make([]string, len(bld.rules))
+				for i, rule := range bld.rules {
+					rules[i] = rule.String()
+				}
+				So(rules, ShouldResemble, []string{
+					"IF a1 AND b3 THEN c1",
+					"IF a1 AND b1 THEN c3",
+					"IF a2 AND b1 THEN c4",
+				})
+			})
+		})
+
+		Convey("when options", func() {
+			fvD := newTestVals("d", "d1", "d2")
+			fvE := newTestVals("e", "c1", "e1")
+
+			Convey("when connective and negated headers", func() {
+				bld := newTestFAM()
+				err := bld.
+					Asso(fvA, fvB, fvC, WithConnective(fuzzy.ConnectiveOr), WithNegation()).
+					Rows(
+						[]id.ID{FamNot("a1"), "a2"},
+						FamRow{Header: "b1", Cells: []id.ID{"c1", "c2"}},
+						FamRow{Header: FamNot("b2"), Cells: []id.ID{"c3", ""}},
+					)
+				So(err, ShouldBeNil)
+
+				rules := make([]string, len(bld.rules))
+				for i, rule := range bld.rules {
+					rules[i] = rule.String()
+				}
+				So(rules, ShouldResemble, []string{
+					"IF NOT a1 OR b1 THEN c1",
+					"IF NOT a1 OR NOT b2 THEN c3",
+					"IF a2 OR b1 THEN c2",
+				})
+			})
+
+			Convey("when multiple consequents", func() {
+				bld := newTestFAM()
+				err := bld.
+					Asso(fvA, fvB, fvC, WithOutputs(fvD, fvE)).
+					Rows(
+						[]id.ID{"a1", "a2"},
+						FamRow{Header: "b1", Cells: []id.ID{FamThen("c2", "d1", "e1"), "d2"}},
+						FamRow{Header: "b2", Cells: []id.ID{"e:c1, c:c1", "c3,c4"}},
+					)
+				So(err, ShouldBeNil)
+
+				rules := make([]string, len(bld.rules))
+				for i, rule := range bld.rules {
+					rules[i] = rule.String()
+				}
+				So(rules, ShouldResemble, []string{
+					"IF a1 AND b1 THEN c2, d1, e1",
+					"IF a1 AND b2 THEN c1, c1",
+					"IF a2 AND b1 THEN d2",
+					"IF a2 AND b2 THEN c3, c4",
+				})
+				_, outputs := bld.rules[1].IO()
+				So(outputs[0].Parent(), ShouldEqual, fvE)
+				So(outputs[1].Parent(), ShouldEqual, fvC)
+			})
+
+			Convey("when plain identifiers", func() {
+				// Without options, headers and cells are not interpreted
+				fvX := newTestVals("x", "!x1", "x,2")
+				fvY := newTestVals("y", "y1")
+				fvZ := newTestVals("z", "t:1", "z, 2")
+				bld := newTestFAM()
+				err := bld.
+					Asso(fvX, fvY, fvZ).
+					Rows(
+						[]id.ID{"!x1", "x,2"},
+						FamRow{Header: "y1", Cells: []id.ID{"t:1", "z, 2"}},
+					)
+				So(err, ShouldBeNil)
+
+				rules := make([]string, len(bld.rules))
+				for i, rule := range bld.rules {
+					rules[i] = rule.String()
+				}
+				So(rules, ShouldResemble, []string{
+					"IF !x1 AND y1 THEN t:1",
+					"IF x,2 AND y1 THEN z, 2",
 				})
+
+				err = bld.Asso(fvA, fvB, fvC).Rows([]id.ID{FamNot("a1")}, FamRow{Header: "b1", Cells: []id.ID{"c1"}})
+				So(err, ShouldBeError, "'if' statement, cannot find !a1 from a")
+				err = bld.Asso(fvA, fvB, fvC).Rows([]id.ID{"a1"}, FamRow{Header: "b1", Cells: []id.ID{FamThen("c1", "c2")}})
+				So(err, ShouldBeError, "'then' statement, cannot find c1, c2 from c")
+			})
+
+			Convey("when wrong consequents", func() {
+				asso := func() FamAsso {
+					bld := newTestFAM()
+					return bld.Asso(fvA, fvB, fvC, WithOutputs(fvD, fvE))
+				}
+
+				err := asso().Rows([]id.ID{"a1"}, FamRow{Header: "b1", Cells: []id.ID{"c1"}})
+				So(err, ShouldBeError, "'then' statement, ambiguous term c1 (found in c and e), use <value>:<term>")
+
+				err = asso().Rows([]id.ID{"a1"}, FamRow{Header: "b1", Cells: []id.ID{"d3"}})
+				So(err, ShouldBeError, "'then' statement, cannot find d3 from c, d, e")
+
+				err = asso().Rows([]id.ID{"a1"}, FamRow{Header: "b1", Cells: []id.ID{"f:d1"}})
+				So(err, ShouldBeError, "'then' statement, cannot find value f")
+
+				err = asso().Rows([]id.ID{"a1"}, FamRow{Header: "b1", Cells: []id.ID{"d:c1"}})
+				So(err, ShouldBeError, "'then' statement, cannot find c1 from d")
+
+				err = asso().Rows([]id.ID{"a1"},
+					FamRow{Header: "b1", Cells: []id.ID{"c2"}},
+					FamRow{Header: "b1", Cells: []id.ID{"c3"}},
+				)
+				So(err, ShouldBeError, "'if' statement, duplicated headers found ([a1 b1])")
 			})
 		})
 
@@ -0,0 +1,298 @@
+package builder
+
+import (
+	"bufio"
+	"cmp"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/sbiemont/fugologic/fuzzy"
+	"github.com/sbiemont/fugologic/id"
+)
+
+// FamTable is the tabular form of a fuzzy associative matrix
+// The header row lists the terms of the first input value, the first column lists the terms of the second input value,
+// and each cell lists the output terms (empty meaning no rule)
+type FamTable struct {
+	Title   string  // top-left cell, not interpreted (eg.: "a/b => c")
+	Columns []id.ID // terms of the first input value
+	Rows    []FamRow
+}
+
+// Matrix returns the rows indexed by header (see. FamAsso.Matrix)
+func (table FamTable) Matrix() map[id.ID][]id.ID {
+	result := make(map[id.ID][]id.ID, len(table.Rows))
+	for _, row := range table.Rows {
+		result[row.Header] = row.Cells
+	}
+	return result
+}
+
+// ReadFamCSV reads a table from CSV
+func ReadFamCSV(r io.Reader) (FamTable, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1 // sizes checked later
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return FamTable{}, fmt.Errorf("fam: %w", err)
+	}
+	return newFamTable(records)
+}
+
+// mdSeparator matches a cell of the separator line of a Markdown table. Eg.: "---", ":--:"
+var mdSeparator = regexp.MustCompile(`^:?-+:?$`)
+
+// ReadFamMarkdown reads a table from a Markdown table
+// Empty lines are ignored, as well as the separator line; headers can be emphasized (eg.: **b1**)
+func ReadFamMarkdown(r io.Reader) (FamTable, error) {
+	var records [][]string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, "|") {
+			return FamTable{}, fmt.Errorf("fam: line %d: table row expected", len(records)+1)
+		}
+		cells := strings.Split(strings.TrimSuffix(strings.TrimPrefix(line, "|"), "|"), "|")
+		separator := true
+		for i, cell := range cells {
+			cells[i] = strings.TrimSpace(cell)
+			separator = separator && mdSeparator.MatchString(cells[i])
+		}
+		if separator {
+			continue
+		}
+		for _, emphasis := range []string{"**", "__", "*", "_"} {
+			if strings.HasPrefix(cells[0], emphasis) && strings.HasSuffix(cells[0], emphasis) && len(cells[0]) > 2*len(emphasis) {
+				cells[0] = strings.TrimSuffix(strings.TrimPrefix(cells[0], emphasis), emphasis)
+				break
+			}
+		}
+		records = append(records, cells)
+	}
+	if err := scanner.Err(); err != nil {
+		return FamTable{}, fmt.Errorf("fam: %w", err)
+	}
+	return newFamTable(records)
+}
+
+// newFamTable checks the records and creates a table
+func newFamTable(records [][]string) (FamTable, error) {
+	if len(records) == 0 {
+		return FamTable{}, fmt.Errorf("fam: empty table")
+	}
+
+	table := FamTable{Title: strings.TrimSpace(records[0][0])}
+	for _, column := range records[0][1:] {
+		uuid := id.ID(strings.TrimSpace(column))
+		if uuid.Empty() {
+			return FamTable{}, fmt.Errorf("fam: line 1: empty column header")
+		}
+		if slices.Contains(table.Columns, uuid) {
+			return FamTable{}, fmt.Errorf("fam: line 1: duplicated column %s", uuid)
+		}
+		table.Columns = append(table.Columns, uuid)
+	}
+
+	found := make(map[id.ID]struct{})
+	for i, record := range records[1:] {
+		if len(record) != len(table.Columns)+1 {
+			return FamTable{}, fmt.Errorf("fam: line %d: sizes should be the same (found: %d, expected: %d)", i+2, len(record)-1, len(table.Columns))
+		}
+		header := id.ID(strings.TrimSpace(record[0]))
+		if header.Empty() {
+			return FamTable{}, fmt.Errorf("fam: line %d: empty row header", i+2)
+		}
+		if _, exists := found[header]; exists {
+			return FamTable{}, fmt.Errorf("fam: line %d: duplicated row %s", i+2, header)
+		}
+		found[header] = struct{}{}
+
+		row := FamRow{Header: header, Cells: make([]id.ID, len(table.Columns))}
+		for j, cell := range record[1:] {
+			row.Cells[j] = id.ID(strings.TrimSpace(cell))
+		}
+		table.Rows = append(table.Rows, row)
+	}
+	return table, nil
+}
+
+// records returns the cells of the table, header row included
+func (table FamTable) records() [][]string {
+	header := []string{table.Title}
+	for _, column := range table.Columns {
+		header = append(header, string(column))
+	}
+	result := [][]string{header}
+	for _, row := range table.Rows {
+		record := []string{string(row.Header)}
+		for _, cell := range row.Cells {
+			record = append(record, string(cell))
+		}
+		result = append(result, record)
+	}
+	return result
+}
+
+// WriteCSV writes the table using the CSV format
+func (table FamTable) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.WriteAll(table.records()); err != nil {
+		return fmt.Errorf("fam: %w", err)
+	}
+	return nil
+}
+
+// WriteMarkdown writes the table as a Markdown table, row headers being emphasized
+func (table FamTable) WriteMarkdown(w io.Writer) error {
+	records := table.records()
+	line := func(cells []string) string {
+		return "| " + strings.Join(cells, " | ") + " |\n"
+	}
+
+	separator := make([]string, len(records[0]))
+	for i := range separator {
+		separator[i] = "---"
+	}
+	text := line(records[0]) + line(separator)
+	for _, record := range records[1:] {
+		record[0] = "**" + record[0] + "**"
+		text += line(record)
+	}
+	if _, err := io.WriteString(w, text); err != nil {
+		return fmt.Errorf("fam: %w", err)
+	}
+	return nil
+}
+
+// NewFamTable renders the 2-input rule base of an engine as a table
+// Each rule shall be like "if <a> and <b> then <c>" (the table cannot keep another connective),
+// premises can be negated (eg.: "!a1"), several output terms are listed in a cell (see. FamThen):
+// the options WithNegation and WithOutputs are then required to read the table back.
+// Columns and rows are listed in the order of the terms of their value (see. fuzzy.IDVal.Terms), a term before its negation
+func NewFamTable(eng fuzzy.Engine, ifVal, andVal *fuzzy.IDVal) (FamTable, error) {
+	// Output values, sorted by id
+	var thenVals []*fuzzy.IDVal
+	for _, rule := range eng.Rules() {
+		for _, idSet := range rule.Outputs() {
+			if !slices.Contains(thenVals, idSet.Parent()) {
+				thenVals = append(thenVals, idSet.Parent())
+			}
+		}
+	}
+	slices.SortFunc(thenVals, func(v1, v2 *fuzzy.IDVal) int {
+		return cmp.Compare(v1.ID(), v2.ID())
+	})
+	thenIDs := make([]string, len(thenVals))
+	for i, thenVal := range thenVals {
+		thenIDs[i] = string(thenVal.ID())
+	}
+
+	table := FamTable{Title: fmt.Sprintf("%s/%s => %s", ifVal.ID(), andVal.ID(), strings.Join(thenIDs, ", "))}
+	type cell struct {
+		column, row id.ID
+	}
+	cells := make(map[cell]id.ID)
+	defined := make(map[cell]int)
+	var rows []id.ID
+	for i, rule := range eng.Rules() {
+		if rule.Weight() != 1 {
+			return FamTable{}, fmt.Errorf("fam: rule #%d: weight not supported", i)
+		}
+		exp, ok := rule.Premise().(fuzzy.Expression)
+		if !ok || len(exp.Premises()) != 2 || exp.Complement() || exp.Hedge() != nil {
+			return FamTable{}, fmt.Errorf("fam: rule #%d: 2 connected premises expected", i)
+		}
+		if exp.Connective() != fuzzy.ConnectiveAnd {
+			return FamTable{}, fmt.Errorf("fam: rule #%d: connective %s expected (found: %s)", i, fuzzy.ConnectiveAnd, exp.Connective())
+		}
+
+		// Headers
+		var key cell
+		for _, premise := range exp.Premises() {
+			parent, header, err := famHeader(premise)
+			if err != nil {
+				return FamTable{}, fmt.Errorf("fam: rule #%d: %w", i, err)
+			}
+			switch parent {
+			case ifVal:
+				key.column = header
+			case andVal:
+				key.row = header
+			default:
+				return FamTable{}, fmt.Errorf("fam: rule #%d: unexpected value %s", i, parent.ID())
+			}
+		}
+		if key.column.Empty() || key.row.Empty() {
+			return FamTable{}, fmt.Errorf("fam: rule #%d: premises of %s and %s expected", i, ifVal.ID(), andVal.ID())
+		}
+		if j, exists := defined[key]; exists {
+			return FamTable{}, fmt.Errorf("fam: rules #%d and #%d share the same headers (%s, %s)", j, i, key.column, key.row)
+		}
+		defined[key] = i
+		if !slices.Contains(table.Columns, key.column) {
+			table.Columns = append(table.Columns, key.column)
+		}
+		if !slices.Contains(rows, key.row) {
+			rows = append(rows, key.row)
+		}
+
+		// Output terms, qualified if several output values
+		terms := make([]id.ID, len(rule.Outputs()))
+		for j, idSet := range rule.Outputs() {
+			terms[j] = idSet.ID()
+			if len(thenVals) > 1 {
+				terms[j] = idSet.Parent().ID() + ":" + idSet.ID()
+			}
+		}
+		cells[key] = FamThen(terms...)
+	}
+
+	sortFamHeaders(ifVal, table.Columns)
+	sortFamHeaders(andVal, rows)
+	for _, header := range rows {
+		row := FamRow{Header: header, Cells: make([]id.ID, len(table.Columns))}
+		for j, column := range table.Columns {
+			row.Cells[j] = cells[cell{column: column, row: header}]
+		}
+		table.Rows = append(table.Rows, row)
+	}
+	return table, nil
+}
+
+// sortFamHeaders sorts the headers in the order of the terms of the value, a term before its negation
+func sortFamHeaders(iv *fuzzy.IDVal, headers []id.ID) {
+	rank := func(header id.ID) int {
+		term, negated := strings.CutPrefix(string(header), "!")
+		result := 2 * iv.Index(id.ID(term))
+		if negated {
+			result++
+		}
+		return result
+	}
+	slices.SortFunc(headers, func(h1, h2 id.ID) int {
+		return cmp.Compare(rank(h1), rank(h2))
+	})
+}
+
+// famHeader returns the value and the header of a premise: a term, possibly negated (eg.: "!a1")
+func famHeader(premise fuzzy.Premise) (*fuzzy.IDVal, id.ID, error) {
+	switch p := premise.(type) {
+	case fuzzy.IDSet:
+		return p.Parent(), p.ID(), nil
+	case fuzzy.Expression:
+		if len(p.Premises()) == 1 && p.Complement() && p.Hedge() == nil {
+			if idSet, ok := p.Premises()[0].(fuzzy.IDSet); ok {
+				return idSet.Parent(), FamNot(idSet.ID()), nil
+			}
+		}
+	}
+	return nil, "", fmt.Errorf("term or negated term expected (found: %v)", premise)
+}
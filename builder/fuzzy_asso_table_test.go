@@ -0,0 +1,165 @@
+package builder
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sbiemont/fugologic/fuzzy"
+	"github.com/sbiemont/fugologic/id"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestFuzzyAssoTable(t *testing.T) {
+	Convey("fam table", t, func() {
+		fvA := newTestVals("a", "a1", "a2", "a3")
+		fvB := newTestVals("b", "b1", "b2")
+		fvC := newTestVals("c", "c1", "c2", "c3")
+
+		expected := FamTable{
+			Title:   "a/b => c",
+			Columns: []id.ID{"a1", "a2", "a3"},
+			Rows: []FamRow{
+				{Header: "b2", Cells: []id.ID{"c2", "", "c3"}},
+				{Header: "b1", Cells: []id.ID{"c1", "c2", "c3"}},
+			},
+		}
+
+		Convey("when markdown", func() {
+			table, err := ReadFamMarkdown(strings.NewReader(`
+| a/b => c | a1 | a2 | a3 |
+|:---------|----|----|---:|
+| **b2**   | c2 |    | c3 |
+| b1       | c1 | c2 | c3 |
+`))
+			So(err, ShouldBeNil)
+			So(table, ShouldResemble, expected)
+
+			var sb strings.Builder
+			So(table.WriteMarkdown(&sb), ShouldBeNil)
+			So(sb.String(), ShouldEqual, ""+
+				"| a/b => c | a1 | a2 | a3 |\n"+
+				"| --- | --- | --- | --- |\n"+
+				"| **b2** | c2 |  | c3 |\n"+
+				"| **b1** | c1 | c2 | c3 |\n")
+		})
+
+		Convey("when csv", func() {
+			table, err := ReadFamCSV(strings.NewReader("a/b => c,a1,a2,a3\nb2,c2,,c3\nb1, c1, c2, c3\n"))
+			So(err, ShouldBeNil)
+			So(table, ShouldResemble, expected)
+
+			var sb strings.Builder
+			So(table.WriteCSV(&sb), ShouldBeNil)
+			So(sb.String(), ShouldEqual, "a/b => c,a1,a2,a3\nb2,c2,,c3\nb1,c1,c2,c3\n")
+		})
+
+		Convey("when fam", func() {
+			// Matrix (rows sorted) or rows (in order)
+			bld := newTestFAM()
+			So(bld.Asso(fvA, fvB, fvC).Matrix(expected.Columns, expected.Matrix()), ShouldBeNil)
+			So(describe(bld.rules), ShouldResemble, []string{
+				"IF a1 AND b1 THEN c1",
+				"IF a1 AND b2 THEN c2",
+				"IF a2 AND b1 THEN c2",
+				"IF a3 AND b1 THEN c3",
+				"IF a3 AND b2 THEN c3",
+			})
+
+			bld = newTestFAM()
+			So(bld.Asso(fvA, fvB, fvC).Rows(expected.Columns, expected.Rows...), ShouldBeNil)
+			eng, err := bld.Engine()
+			So(err, ShouldBeNil)
+			table, err := NewFamTable(eng, fvA, fvB)
+			So(err, ShouldBeNil)
+			So(table, ShouldResemble, FamTable{ // in the order of the terms
+				Title:   expected.Title,
+				Columns: expected.Columns,
+				Rows:    []FamRow{expected.Rows[1], expected.Rows[0]},
+			})
+		})
+
+		Convey("when round trip with options", func() {
+			fvD := newTestVals("d", "d1", "d2")
+			bld := newTestFAM()
+			err := bld.Asso(fvA, fvB, fvC, WithNegation(), WithOutputs(fvD)).Rows(
+				[]id.ID{"a2", FamNot("a1"), "a1"},
+				FamRow{Header: "b2", Cells: []id.ID{"", "c2", ""}},
+				FamRow{Header: "b1", Cells: []id.ID{"d1", FamThen("c1", "d2"), ""}},
+			)
+			So(err, ShouldBeNil)
+			eng, err := bld.Engine()
+			So(err, ShouldBeNil)
+
+			table, err := NewFamTable(eng, fvA, fvB)
+			So(err, ShouldBeNil)
+			So(table, ShouldResemble, FamTable{
+				Title:   "a/b => c, d",
+				Columns: []id.ID{"!a1", "a2"},
+				Rows: []FamRow{
+					{Header: "b1", Cells: []id.ID{"c:c1, d:d2", "d:d1"}},
+					{Header: "b2", Cells: []id.ID{"c:c2", ""}},
+				},
+			})
+
+			var sb strings.Builder
+			So(table.WriteCSV(&sb), ShouldBeNil)
+			read, err := ReadFamCSV(strings.NewReader(sb.String()))
+			So(err, ShouldBeNil)
+			So(read, ShouldResemble, table)
+		})
+
+		Convey("when error", func() {
+			_, err := ReadFamMarkdown(strings.NewReader(""))
+			So(err, ShouldBeError, "fam: empty table")
+
+			_, err = ReadFamMarkdown(strings.NewReader("| x | a1 |\nb1 | c1 |"))
+			So(err, ShouldBeError, "fam: line 2: table row expected")
+
+			_, err = ReadFamCSV(strings.NewReader("x,a1,a2\nb1,c1\n"))
+			So(err, ShouldBeError, "fam: line 2: sizes should be the same (found: 1, expected: 2)")
+
+			_, err = ReadFamCSV(strings.NewReader("x,a1,a1\n"))
+			So(err, ShouldBeError, "fam: line 1: duplicated column a1")
+
+			_, err = ReadFamCSV(strings.NewReader("x,a1,\n"))
+			So(err, ShouldBeError, "fam: line 1: empty column header")
+
+			_, err = ReadFamCSV(strings.NewReader("x,a1\nb1,c1\nb1,c2\n"))
+			So(err, ShouldBeError, "fam: line 3: duplicated row b1")
+
+			_, err = ReadFamCSV(strings.NewReader("x,a1\n,c1\n"))
+			So(err, ShouldBeError, "fam: line 2: empty row header")
+
+			_, err = ReadFamCSV(strings.NewReader("x,\"a1\n"))
+			So(err, ShouldNotBeNil)
+
+			// Not a 2-input rule base
+			fl := NewFuzzyLogic(fuzzy.OperatorZadeh{}, fuzzy.ImplicationMin, fuzzy.AggregationUnion, fuzzy.DefuzzificationCentroid)
+			fl.If(fvA.Get("a1")).Then(fvC.Get("c1"))
+			eng, err := fl.Engine()
+			So(err, ShouldBeNil)
+			_, err = NewFamTable(eng, fvA, fvB)
+			So(err, ShouldBeError, "fam: rule #0: 2 connected premises expected")
+
+			fl = NewFuzzyLogic(fuzzy.OperatorZadeh{}, fuzzy.ImplicationMin, fuzzy.AggregationUnion, fuzzy.DefuzzificationCentroid)
+			fl.If(fvA.Get("a1")).And(fvB.Get("b1")).Then(fvC.Get("c1"))
+			fl.If(fvA.Get("a1")).And(fvB.Get("b1")).Then(fvC.Get("c2"))
+			eng, err = fl.Engine()
+			So(err, ShouldBeNil)
+			_, err = NewFamTable(eng, fvA, fvB)
+			So(err, ShouldBeError, "fam: rules #0 and #1 share the same headers (a1, b1)")
+
+			_, err = NewFamTable(eng, fvA, fvC)
+			So(err, ShouldBeError, "fam: rule #0: unexpected value b")
+
+			// The connective is not kept in the table
+			bld := newTestFAM()
+			So(bld.Asso(fvA, fvB, fvC, WithConnective(fuzzy.ConnectiveOr)).Rows([]id.ID{"a1"}, FamRow{Header: "b1", Cells: []id.ID{"c1"}}), ShouldBeNil)
+			eng, err = bld.Engine()
+			So(err, ShouldBeNil)
+			_, err = NewFamTable(eng, fvA, fvB)
+			So(err, ShouldBeError, "fam: rule #0: connective and expected (found: or)")
+		})
+	})
+}
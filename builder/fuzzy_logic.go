@@ -33,13 +33,14 @@ func NewFuzzyLogic(
 func (fl *FuzzyLogic) If(premise fuzzy.Premise) flExpression {
 	return flExpression{
 		fl:    fl,
-		fzExp: fuzzy.NewExpression([]fuzzy.Premise{premise}, nil),
+		fzExp: fuzzy.NewExpression([]fuzzy.Premise{unwrap(premise)}, nil),
 	}
 }
 
 // Engine created using the defined rules and the default configuration
-func (fl FuzzyLogic) Engine() (fuzzy.Engine, error) {
-	return fuzzy.NewEngine(fl.rules, fl.agg, fl.defuzz)
+// The options set the identifier and the description of the engine (see. fuzzy.WithID, fuzzy.WithDescription)
+func (fl FuzzyLogic) Engine(opts ...fuzzy.EngineOption) (fuzzy.Engine, error) {
+	return fuzzy.NewEngine(fl.rules, fl.agg, fl.defuzz, opts...)
 }
 
 // add a new rule to the builder
@@ -53,32 +54,46 @@ type flExpression struct {
 	fzExp fuzzy.Expression
 }
 
+// unwrap returns the fuzzy expression of a builder expression, or the premise itself
+// Rules only contain fuzzy expressions and can be inspected (see. fuzzy.Expression)
+func unwrap(premise fuzzy.Premise) fuzzy.Premise {
+	if exp, ok := premise.(flExpression); ok {
+		return exp.fzExp
+	}
+	return premise
+}
+
+// String describes the fuzzy expression linked
+func (exp flExpression) String() string {
+	return exp.fzExp.String()
+}
+
 // Evaluate the fuzzy expression linked
 func (exp flExpression) Evaluate(input fuzzy.DataInput) (float64, error) {
 	return exp.fzExp.Evaluate(input)
 }
 
-// connect the current expression with a new one with a connector
-func (exp flExpression) connect(premise fuzzy.Premise, cnt fuzzy.Connector) flExpression {
+// connect the current expression with a new one with a connective of the builder operator
+func (exp flExpression) connect(premise fuzzy.Premise, cnt fuzzy.Connective) flExpression {
 	return flExpression{
 		fl:    exp.fl,
-		fzExp: exp.fzExp.Connect(premise, cnt),
+		fzExp: exp.fzExp.ConnectOperator(unwrap(premise), exp.fl.optr, cnt),
 	}
 }
 
 // And connects the current expression and a premise with the AND connector of the builder
 func (exp flExpression) And(premise fuzzy.Premise) flExpression {
-	return exp.connect(premise, exp.fl.optr.And)
+	return exp.connect(premise, fuzzy.ConnectiveAnd)
 }
 
 // Or connects the current expression and a premise with the OR connector of the builder
 func (exp flExpression) Or(premise fuzzy.Premise) flExpression {
-	return exp.connect(premise, exp.fl.optr.Or)
+	return exp.connect(premise, fuzzy.ConnectiveOr)
 }
 
 // XOr connects the current expression and a premise with the XOR connector of the builder
 func (exp flExpression) XOr(premise fuzzy.Premise) flExpression {
-	return exp.connect(premise, exp.fl.optr.XOr)
+	return exp.connect(premise, fuzzy.ConnectiveXOr)
 }
 
 // Not complements the current expression
@@ -169,6 +169,23 @@ func TestAdd(t *testing.T) {
 		bld.If(fsB1).Then(fsC1)
 		So(bld.rules, ShouldHaveLength, 2)
 	})
+
+	Convey("nested expressions", t, func() {
+		_, fsA1 := newTestVal("a", "a1")
+		_, fsB1 := newTestVal("b", "b1")
+		_, fsC1 := newTestVal("c", "c1")
+		_, fsD1 := newTestVal("d", "d1")
+
+		// Sub-expressions of the builder are stored as fuzzy expressions
+		bld := Mamdani().FuzzyLogic()
+		bld.If(fsA1).Or(bld.If(fsB1).And(fsC1).Not()).Then(fsD1)
+		So(bld.rules, ShouldHaveLength, 1)
+		So(bld.rules[0].String(), ShouldEqual, "IF a1 OR NOT (b1 AND c1) THEN d1")
+		So(bld.If(fsA1).And(fsB1).String(), ShouldEqual, "a1 AND b1")
+
+		inputs, _ := bld.rules[0].IO()
+		So(inputs, ShouldHaveLength, 3)
+	})
 }
 
 func TestEngine(t *testing.T) {
@@ -185,6 +202,11 @@ func TestEngine(t *testing.T) {
 		So(engine, ShouldNotBeEmpty)
 		So(err, ShouldBeNil)
 
+		named, err := bld.Engine(fuzzy.WithID("c"), fuzzy.WithDescription("a or b"))
+		So(err, ShouldBeNil)
+		So(named.ID(), ShouldEqual, id.ID("c"))
+		So(named.Description(), ShouldEqual, "a or b")
+
 		result, err := engine.Evaluate(fuzzy.DataInput{
 			fvA: 1,
 			fvB: 2,
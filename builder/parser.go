@@ -0,0 +1,532 @@
+package builder
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/sbiemont/fugologic/fuzzy"
+	"github.com/sbiemont/fugologic/id"
+)
+
+// ParseError is a syntax error in a textual rule
+type ParseError struct {
+	Column  int    // column of the error in the rule (starting at 1)
+	Message string // description of the error
+}
+
+// Error formats the error with its column
+func (err ParseError) Error() string {
+	return fmt.Sprintf("rule: column %d: %s", err.Column, err.Message)
+}
+
+// Parse reads a textual rule and stores it into the builder (see. Config.ParseRule)
+func (fl *FuzzyLogic) Parse(text string, values ...*fuzzy.IDVal) error {
+	rule, err := parseRule(text, fl.optr, fl.impl, values)
+	if err != nil {
+		return err
+	}
+	fl.add(rule)
+	return nil
+}
+
+// ParseRule reads a textual rule using the operator and the implication of the configuration
+// Values and sets are resolved using the identifiers of the given fuzzy values
+//
+//	if HP is "Low HP" and (FP is not "Weak FP" or FP is very "High FP") then Act is Defend with 0.5
+//
+// Keywords are "if", "is", "then", "and", "or", "xor", "not", "with" and hedges "very", "somewhat", "extremely"
+// Identifiers with spaces, parentheses or matching a keyword shall be quoted
+func (cfg Config) ParseRule(text string, values ...*fuzzy.IDVal) (fuzzy.Rule, error) {
+	return parseRule(text, cfg.Optr, cfg.Impl, values)
+}
+
+// ParseRuleWith reads a textual rule like ParseRule, the options restricting the syntax (see. WithoutQuotes, WithConnectives)
+func (cfg Config) ParseRuleWith(text string, values []*fuzzy.IDVal, opts ...ParseOption) (fuzzy.Rule, error) {
+	return parseRule(text, cfg.Optr, cfg.Impl, values, opts...)
+}
+
+// ParseOption restricts the syntax of a textual rule
+type ParseOption func(rp *ruleParser)
+
+// WithoutQuotes reads identifiers as plain words: a quote is a character of a word
+func WithoutQuotes() ParseOption {
+	return func(rp *ruleParser) {
+		rp.unquoted = true
+	}
+}
+
+// WithConnectives only allows the given connectives (default: all connectives)
+func WithConnectives(connectives ...fuzzy.Connective) ParseOption {
+	return func(rp *ruleParser) {
+		rp.allowed = make(map[fuzzy.Connective]struct{}, len(connectives))
+		for _, connective := range connectives {
+			rp.allowed[connective] = struct{}{}
+		}
+	}
+}
+
+// parseRule reads a textual rule
+func parseRule(text string, optr fuzzy.Operator, impl fuzzy.Implication, values []*fuzzy.IDVal, opts ...ParseOption) (fuzzy.Rule, error) {
+	vals := make(map[id.ID]*fuzzy.IDVal, len(values))
+	for _, value := range values {
+		if _, exists := vals[value.ID()]; exists {
+			return fuzzy.Rule{}, fmt.Errorf("rule: value `%s` defined twice", value.ID())
+		}
+		vals[value.ID()] = value
+	}
+
+	rp := ruleParser{
+		values: vals,
+		optr:   optr,
+	}
+	for _, opt := range opts {
+		opt(&rp)
+	}
+	tokens, err := lex(text, !rp.unquoted)
+	if err != nil {
+		return fuzzy.Rule{}, err
+	}
+	rp.tokens = tokens
+
+	premise, outputs, weight, err := rp.rule()
+	if err != nil {
+		return fuzzy.Rule{}, err
+	}
+	return fuzzy.NewRule(premise, impl, outputs).WithWeight(weight), nil
+}
+
+// Rule keywords
+const (
+	kwIf   = "if"
+	kwIs   = "is"
+	kwThen = "then"
+	kwAnd  = "and"
+	kwOr   = "or"
+	kwXOr  = "xor"
+	kwNot  = "not"
+	kwWith = "with"
+)
+
+// keywords cannot be used as unquoted identifiers
+var keywords = map[string]struct{}{
+	kwIf: {}, kwIs: {}, kwThen: {}, kwAnd: {}, kwOr: {}, kwXOr: {}, kwNot: {}, kwWith: {},
+	"very": {}, "somewhat": {}, "extremely": {},
+}
+
+// tokenKind identifies the kind of a token
+type tokenKind int
+
+const (
+	tokenEnd    tokenKind = iota // end of the rule
+	tokenWord                    // keyword, identifier or number
+	tokenString                  // quoted identifier
+	tokenOpen                    // "("
+	tokenClose                   // ")"
+)
+
+// token is a located part of a rule
+type token struct {
+	kind   tokenKind
+	text   string
+	column int
+}
+
+// String describes the token for error messages
+func (tk token) String() string {
+	switch tk.kind {
+	case tokenEnd:
+		return "end of rule"
+	case tokenString:
+		return strconv.Quote(tk.text)
+	default:
+		return "`" + tk.text + "`"
+	}
+}
+
+// is checks if the token is the given keyword (case insensitive)
+func (tk token) is(keyword string) bool {
+	return tk.kind == tokenWord && strings.EqualFold(tk.text, keyword)
+}
+
+// isKeyword checks if the token is a keyword
+func (tk token) isKeyword() bool {
+	_, ok := keywords[strings.ToLower(tk.text)]
+	return tk.kind == tokenWord && ok
+}
+
+// lex splits a rule into tokens, quoted identifiers being read if enabled. The last token is always tokenEnd
+func lex(text string, quotes bool) ([]token, error) {
+	separators := `()`
+	if quotes {
+		separators = `()"`
+	}
+
+	var tokens []token
+	runes := []rune(text)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		column := i + 1
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenOpen, text: "(", column: column})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenClose, text: ")", column: column})
+			i++
+		case quotes && r == '"':
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			if end == len(runes) {
+				return nil, ParseError{Column: column, Message: "unterminated quoted identifier"}
+			}
+			tokens = append(tokens, token{kind: tokenString, text: string(runes[i+1 : end]), column: column})
+			i = end + 1
+		default:
+			end := i
+			for end < len(runes) && !unicode.IsSpace(runes[end]) && !strings.ContainsRune(separators, runes[end]) {
+				end++
+			}
+			tokens = append(tokens, token{kind: tokenWord, text: string(runes[i:end]), column: column})
+			i = end
+		}
+	}
+	return append(tokens, token{kind: tokenEnd, column: len(runes) + 1}), nil
+}
+
+// ruleParser converts the tokens of a rule (recursive descent)
+//
+//	rule        = "if" disjunction "then" consequent { "and" consequent } [ "with" weight ]
+//	disjunction = conjunction { ( "or" | "xor" ) conjunction }
+//	conjunction = factor { "and" factor }
+//	factor      = "not" factor | hedge factor | "(" disjunction ")" | proposition
+//	proposition = value "is" [ "not" ] { hedge } set
+//	consequent  = value "is" set
+type ruleParser struct {
+	tokens   []token
+	pos      int
+	values   map[id.ID]*fuzzy.IDVal
+	optr     fuzzy.Operator
+	unquoted bool                          // identifiers are plain words (see. WithoutQuotes)
+	allowed  map[fuzzy.Connective]struct{} // allowed connectives, nil for all (see. WithConnectives)
+}
+
+// peek returns the current token
+func (rp *ruleParser) peek() token {
+	return rp.tokens[rp.pos]
+}
+
+// next returns the current token and moves forward (except at the end)
+func (rp *ruleParser) next() token {
+	tk := rp.peek()
+	if tk.kind != tokenEnd {
+		rp.pos++
+	}
+	return tk
+}
+
+// errorf creates an error located at the token
+func errorf(tk token, format string, args ...any) error {
+	return ParseError{Column: tk.column, Message: fmt.Sprintf(format, args...)}
+}
+
+// expect checks the current keyword and moves forward
+func (rp *ruleParser) expect(keyword string) error {
+	if tk := rp.next(); !tk.is(keyword) {
+		return errorf(tk, "`%s` expected (found: %s)", keyword, tk)
+	}
+	return nil
+}
+
+// rule parses the whole rule and returns its premise, outputs and weight
+func (rp *ruleParser) rule() (fuzzy.Premise, []fuzzy.IDSet, float64, error) {
+	if err := rp.expect(kwIf); err != nil {
+		return nil, nil, 0, err
+	}
+	premise, err := rp.disjunction()
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	if err := rp.expect(kwThen); err != nil {
+		return nil, nil, 0, err
+	}
+
+	var outputs []fuzzy.IDSet
+	for {
+		idVal, err := rp.value()
+		if err != nil {
+			return nil, nil, 0, err
+		}
+		output, err := rp.set(idVal)
+		if err != nil {
+			return nil, nil, 0, err
+		}
+		outputs = append(outputs, output)
+		if !rp.peek().is(kwAnd) {
+			break
+		}
+		rp.next()
+	}
+
+	weight := 1.0
+	if rp.peek().is(kwWith) {
+		rp.next()
+		tk := rp.next()
+		weight, err = strconv.ParseFloat(tk.text, 64)
+		if tk.kind != tokenWord || err != nil || weight < 0 || weight > 1 {
+			return nil, nil, 0, errorf(tk, "weight in [0, 1] expected (found: %s)", tk)
+		}
+	}
+	if tk := rp.peek(); tk.kind != tokenEnd {
+		return nil, nil, 0, errorf(tk, "unexpected %s", tk)
+	}
+	return premise, outputs, weight, nil
+}
+
+// connectives links the keywords to the connectives of the operator
+var connectives = map[string]fuzzy.Connective{
+	kwAnd: fuzzy.ConnectiveAnd,
+	kwOr:  fuzzy.ConnectiveOr,
+	kwXOr: fuzzy.ConnectiveXOr,
+}
+
+// connected parses a list of sub-premises linked with the given keywords
+// Sub-premises are grouped from left to right when the connective changes
+func (rp *ruleParser) connected(sub func() (fuzzy.Premise, error), kws ...string) (fuzzy.Premise, error) {
+	first, err := sub()
+	if err != nil {
+		return nil, err
+	}
+
+	premises := []fuzzy.Premise{first}
+	var current fuzzy.Connective
+	for {
+		var cnt fuzzy.Connective
+		for _, kw := range kws {
+			if rp.peek().is(kw) {
+				cnt = connectives[kw]
+			}
+		}
+		if cnt == "" {
+			break
+		}
+		if _, ok := rp.allowed[cnt]; rp.allowed != nil && !ok {
+			return nil, errorf(rp.peek(), "connective %s not allowed", rp.peek())
+		}
+		rp.next()
+
+		if current != "" && cnt != current {
+			premises = []fuzzy.Premise{fuzzy.NewOperatorExpression(premises, rp.optr, current)}
+		}
+		current = cnt
+		premise, err := sub()
+		if err != nil {
+			return nil, err
+		}
+		premises = append(premises, premise)
+	}
+
+	if len(premises) == 1 {
+		return first, nil
+	}
+	return fuzzy.NewOperatorExpression(premises, rp.optr, current), nil
+}
+
+// disjunction parses premises linked with "or" and "xor"
+func (rp *ruleParser) disjunction() (fuzzy.Premise, error) {
+	return rp.connected(rp.conjunction, kwOr, kwXOr)
+}
+
+// conjunction parses premises linked with "and"
+func (rp *ruleParser) conjunction() (fuzzy.Premise, error) {
+	return rp.connected(rp.factor, kwAnd)
+}
+
+// factor parses a complemented or hedged premise, a parenthesized expression or a proposition
+func (rp *ruleParser) factor() (fuzzy.Premise, error) {
+	tk := rp.peek()
+	if tk.is(kwNot) {
+		rp.next()
+		premise, err := rp.factor()
+		if err != nil {
+			return nil, err
+		}
+		return complement(premise), nil
+	}
+	if hedge, ok := rp.hedge(); ok {
+		premise, err := rp.factor()
+		if err != nil {
+			return nil, err
+		}
+		return withHedge(premise, hedge), nil
+	}
+	if tk.kind == tokenOpen {
+		rp.next()
+		premise, err := rp.disjunction()
+		if err != nil {
+			return nil, err
+		}
+		if tk := rp.next(); tk.kind != tokenClose {
+			return nil, errorf(tk, "`)` expected (found: %s)", tk)
+		}
+		return premise, nil
+	}
+	return rp.proposition()
+}
+
+// proposition parses "<value> is [not] {hedge} <set>"
+func (rp *ruleParser) proposition() (fuzzy.Premise, error) {
+	idVal, err := rp.value()
+	if err != nil {
+		return nil, err
+	}
+	isComplement := false
+	if rp.peek().is(kwNot) {
+		rp.next()
+		isComplement = true
+	}
+	var hedges []fuzzy.Hedge
+	for {
+		hedge, ok := rp.hedge()
+		if !ok {
+			break
+		}
+		hedges = append(hedges, hedge)
+	}
+	idSet, err := rp.set(idVal)
+	if err != nil {
+		return nil, err
+	}
+
+	// Apply the closest hedge first
+	var premise fuzzy.Premise = idSet
+	for i := len(hedges) - 1; i >= 0; i-- {
+		premise = withHedge(premise, hedges[i])
+	}
+	if isComplement {
+		premise = complement(premise)
+	}
+	return premise, nil
+}
+
+// hedge parses an optional hedge
+func (rp *ruleParser) hedge() (fuzzy.Hedge, bool) {
+	tk := rp.peek()
+	if tk.kind != tokenWord {
+		return nil, false
+	}
+	hedge, ok := fuzzy.HedgeByName(strings.ToLower(tk.text))
+	if ok {
+		rp.next()
+	}
+	return hedge, ok
+}
+
+// identifier parses a quoted or unquoted identifier
+func (rp *ruleParser) identifier(what string) (token, error) {
+	tk := rp.next()
+	if tk.kind == tokenString || (tk.kind == tokenWord && !tk.isKeyword()) {
+		return tk, nil
+	}
+	return token{}, errorf(tk, "%s expected (found: %s)", what, tk)
+}
+
+// value parses "<value> is"
+func (rp *ruleParser) value() (*fuzzy.IDVal, error) {
+	tk, err := rp.identifier("value")
+	if err != nil {
+		return nil, err
+	}
+	idVal, ok := rp.values[id.ID(tk.text)]
+	if !ok {
+		names := make([]string, 0, len(rp.values))
+		for name := range rp.values {
+			names = append(names, string(name))
+		}
+		return nil, errorf(tk, "unknown value `%s`%s", tk.text, suggest(tk.text, names))
+	}
+	if err := rp.expect(kwIs); err != nil {
+		return nil, err
+	}
+	return idVal, nil
+}
+
+// set parses the set of a value
+func (rp *ruleParser) set(idVal *fuzzy.IDVal) (fuzzy.IDSet, error) {
+	tk, err := rp.identifier("set")
+	if err != nil {
+		return fuzzy.IDSet{}, err
+	}
+	idSet, ok := idVal.Fetch(id.ID(tk.text))
+	if !ok {
+		var names []string
+		for _, term := range idVal.Terms() {
+			names = append(names, string(term.ID()))
+		}
+		return fuzzy.IDSet{}, errorf(tk, "unknown set `%s` for value `%s`%s", tk.text, idVal.ID(), suggest(tk.text, names))
+	}
+	return idSet, nil
+}
+
+// complement returns the complement of a premise
+func complement(premise fuzzy.Premise) fuzzy.Premise {
+	if exp, ok := premise.(fuzzy.Expression); ok {
+		return exp.Not()
+	}
+	return fuzzy.NewExpression([]fuzzy.Premise{premise}, nil).Not()
+}
+
+// withHedge applies the hedge on the premise
+func withHedge(premise fuzzy.Premise, hedge fuzzy.Hedge) fuzzy.Premise {
+	if exp, ok := premise.(fuzzy.Expression); ok && exp.Hedge() == nil && !exp.Complement() {
+		return exp.WithHedge(hedge)
+	}
+	return fuzzy.NewExpression([]fuzzy.Premise{premise}, nil).WithHedge(hedge)
+}
+
+// suggest returns a suggestion with the closest name, or an empty string if no name is close enough
+// Names are compared using the Levenshtein distance (case insensitive)
+func suggest(name string, names []string) string {
+	sort.Strings(names)
+	best, bestDist := "", -1
+	for _, candidate := range names {
+		dist := levenshtein(strings.ToLower(name), strings.ToLower(candidate))
+		if bestDist < 0 || dist < bestDist {
+			best, bestDist = candidate, dist
+		}
+	}
+
+	maxDist := max(1, len([]rune(name))/2)
+	if bestDist < 0 || bestDist > maxDist {
+		return ""
+	}
+	return fmt.Sprintf(", did you mean `%s`?", best)
+}
+
+// levenshtein computes the edit distance between two strings
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
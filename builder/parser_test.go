@@ -0,0 +1,141 @@
+package builder
+
+import (
+	"testing"
+
+	"github.com/sbiemont/fugologic/crisp"
+	"github.com/sbiemont/fugologic/fuzzy"
+	"github.com/sbiemont/fugologic/id"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestParseRule(t *testing.T) {
+	low := func(x float64) float64 { return 1 - x }
+	high := func(x float64) float64 { return x }
+	u, _ := crisp.NewSet(0, 1, 0.1)
+	fvHP, _ := fuzzy.NewIDVal("HP", u, map[id.ID]fuzzy.Set{"Low HP": low, "High HP": high})
+	fvFP, _ := fuzzy.NewIDVal("FP", u, map[id.ID]fuzzy.Set{"Weak FP": low, "High FP": high})
+	fvAct, _ := fuzzy.NewIDVal("Act", u, map[id.ID]fuzzy.Set{"Defend": low, "Attack": high})
+	values := []*fuzzy.IDVal{fvHP, fvFP, fvAct}
+	cfg := Mamdani()
+
+	Convey("parse rule", t, func() {
+		Convey("when ok", func() {
+			rule, err := cfg.ParseRule(`if HP is "Low HP" and (FP is not "Weak FP" or FP is very "High FP") then Act is Defend with 0.5`, values...)
+			So(err, ShouldBeNil)
+			So(rule.Weight(), ShouldEqual, 0.5)
+			So(rule.Outputs(), ShouldHaveLength, 1)
+			So(rule.Outputs()[0].ID(), ShouldEqual, id.ID("Defend"))
+
+			// HP is "Low HP" and (...)
+			exp := rule.Premise().(fuzzy.Expression)
+			So(exp.Connective(), ShouldEqual, fuzzy.ConnectiveAnd)
+			So(exp.Premises()[0].(fuzzy.IDSet).ID(), ShouldEqual, id.ID("Low HP"))
+
+			// FP is not "Weak FP" or FP is very "High FP"
+			expOr := exp.Premises()[1].(fuzzy.Expression)
+			So(expOr.Connective(), ShouldEqual, fuzzy.ConnectiveOr)
+			So(expOr.Premises()[0].(fuzzy.Expression).Complement(), ShouldBeTrue)
+			So(expOr.Premises()[1].(fuzzy.Expression).Hedge().Name(), ShouldEqual, "very")
+
+			// min(1-0.2, max(1-(1-0.6), 0.6²))
+			y, err := rule.Premise().Evaluate(fuzzy.DataInput{fvHP: 0.2, fvFP: 0.6})
+			So(err, ShouldBeNil)
+			So(y, ShouldAlmostEqual, 0.6)
+		})
+
+		Convey("when precedence", func() {
+			// HP is "Low HP" or (FP is "Weak FP" and FP is "High FP")
+			rule, err := cfg.ParseRule(`IF HP is "Low HP" or FP is "Weak FP" and FP is "High FP" THEN Act is Defend and Act is Attack`, values...)
+			So(err, ShouldBeNil)
+			So(rule.Weight(), ShouldEqual, 1)
+			So(rule.Outputs(), ShouldHaveLength, 2)
+			exp := rule.Premise().(fuzzy.Expression)
+			So(exp.Connective(), ShouldEqual, fuzzy.ConnectiveOr)
+			So(exp.Premises()[1].(fuzzy.Expression).Connective(), ShouldEqual, fuzzy.ConnectiveAnd)
+		})
+
+		Convey("when mixed connectives", func() {
+			// (HP is "Low HP" or HP is "High HP") xor FP is "Weak FP"
+			rule, err := cfg.ParseRule(`if HP is "Low HP" or HP is "High HP" xor FP is "Weak FP" then Act is Defend`, values...)
+			So(err, ShouldBeNil)
+			exp := rule.Premise().(fuzzy.Expression)
+			So(exp.Connective(), ShouldEqual, fuzzy.ConnectiveXOr)
+			So(exp.Premises(), ShouldHaveLength, 2)
+			So(exp.Premises()[0].(fuzzy.Expression).Connective(), ShouldEqual, fuzzy.ConnectiveOr)
+		})
+
+		Convey("when complement and hedges of expressions", func() {
+			// not very (somewhat HP)
+			rule, err := cfg.ParseRule(`if not very (HP is somewhat "Low HP") then Act is Defend`, values...)
+			So(err, ShouldBeNil)
+			exp := rule.Premise().(fuzzy.Expression)
+			So(exp.Complement(), ShouldBeTrue)
+			So(exp.Hedge().Name(), ShouldEqual, "very")
+
+			// 1 - (√(1-0.36))²
+			y, err := rule.Premise().Evaluate(fuzzy.DataInput{fvHP: 0.36})
+			So(err, ShouldBeNil)
+			So(y, ShouldAlmostEqual, 0.36)
+		})
+
+		Convey("when options", func() {
+			fvQ, _ := fuzzy.NewIDVal("q", u, map[id.ID]fuzzy.Set{`"low"`: low})
+			rule, err := cfg.ParseRuleWith(`if q is "low" and q is not "low" then Act is Defend`, []*fuzzy.IDVal{fvQ, fvAct},
+				WithoutQuotes(), WithConnectives(fuzzy.ConnectiveAnd))
+			So(err, ShouldBeNil)
+			So(rule.String(), ShouldEqual, `IF "low" AND NOT "low" THEN Defend`)
+
+			_, err = cfg.ParseRuleWith(`if q is "low" or q is "low" then Act is Defend`, []*fuzzy.IDVal{fvQ, fvAct},
+				WithoutQuotes(), WithConnectives(fuzzy.ConnectiveAnd))
+			So(err, ShouldBeError, "rule: column 15: connective `or` not allowed")
+
+			_, err = cfg.ParseRuleWith(`if HP is "Low HP" and HP is "High HP" then Act is Defend`, values, WithConnectives())
+			So(err, ShouldBeError, "rule: column 19: connective `and` not allowed")
+		})
+
+		Convey("when builder", func() {
+			bld := cfg.FuzzyLogic()
+			So(bld.Parse(`if HP is "Low HP" then Act is Defend`, values...), ShouldBeNil)
+			So(bld.Parse(`if HP is "High HP" then Act is Attack`, values...), ShouldBeNil)
+			So(bld.Parse(`if HP is "Medium HP" then Act is Attack`, values...), ShouldNotBeNil)
+			eng, err := bld.Engine()
+			So(err, ShouldBeNil)
+			So(eng.Rules(), ShouldHaveLength, 2)
+		})
+
+		Convey("when errors", func() {
+			parse := func(text string) error {
+				_, err := cfg.ParseRule(text, values...)
+				return err
+			}
+
+			So(parse(`HP is "Low HP" then Act is Defend`), ShouldResemble, ParseError{Column: 1, Message: "`if` expected (found: `HP`)"})
+			So(parse(`if HPP is "Low HP" then Act is Defend`), ShouldBeError, "rule: column 4: unknown value `HPP`, did you mean `HP`?")
+			So(parse(`if Foo is "Low HP" then Act is Defend`), ShouldBeError, "rule: column 4: unknown value `Foo`")
+			So(parse(`if HP is "Low Hp" then Act is Defend`), ShouldBeError, "rule: column 10: unknown set `Low Hp` for value `HP`, did you mean `Low HP`?")
+			So(parse(`if HP is Low then Act is Defend`), ShouldBeError, "rule: column 10: unknown set `Low` for value `HP`")
+			So(parse(`if HP is "Low HP" then Act is Defnd`), ShouldBeError, "rule: column 31: unknown set `Defnd` for value `Act`, did you mean `Defend`?")
+			So(parse(`if HP is "Low HP then Act is Defend`), ShouldBeError, "rule: column 10: unterminated quoted identifier")
+			So(parse(`if HP "Low HP" then Act is Defend`), ShouldBeError, "rule: column 7: `is` expected (found: \"Low HP\")")
+			So(parse(`if (HP is "Low HP" then Act is Defend`), ShouldBeError, "rule: column 20: `)` expected (found: `then`)")
+			So(parse(`if HP is "Low HP"`), ShouldBeError, "rule: column 18: `then` expected (found: end of rule)")
+			So(parse(`if HP is then Act is Defend`), ShouldBeError, "rule: column 10: set expected (found: `then`)")
+			So(parse(`if HP is "Low HP" then Act is Defend with 2`), ShouldBeError, "rule: column 43: weight in [0, 1] expected (found: `2`)")
+			So(parse(`if HP is "Low HP" then Act is Defend )`), ShouldBeError, "rule: column 38: unexpected `)`")
+			So(parse(`if HP is "Low HP" then Act is not Defend`), ShouldBeError, "rule: column 31: set expected (found: `not`)")
+
+			_, err := cfg.ParseRule(`if HP is "Low HP" then Act is Defend`, fvHP, fvHP)
+			So(err, ShouldBeError, "rule: value `HP` defined twice")
+		})
+	})
+
+	Convey("suggest", t, func() {
+		So(suggest("hp", []string{"FP", "HP"}), ShouldEqual, ", did you mean `HP`?")
+		So(suggest("Lowest", []string{"Low", "Highest"}), ShouldEqual, ", did you mean `Low`?")
+		So(suggest("abc", []string{"xyz"}), ShouldBeEmpty)
+		So(suggest("abc", nil), ShouldBeEmpty)
+		So(levenshtein("kitten", "sitting"), ShouldEqual, 3)
+	})
+}
@@ -0,0 +1,264 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/sbiemont/fugologic/fuzzy"
+	"github.com/sbiemont/fugologic/id"
+)
+
+// inputFlags gathers the repeated "-in id=value" flags
+type inputFlags []string
+
+// String describes the flags
+func (in *inputFlags) String() string {
+	return strings.Join(*in, " ")
+}
+
+// Set adds a new flag
+func (in *inputFlags) Set(value string) error {
+	*in = append(*in, value)
+	return nil
+}
+
+// runEval evaluates a model for each set of inputs
+func runEval(args []string, stdin io.Reader, stdout io.Writer) error {
+	var inputs inputFlags
+	fs := newFlagSet("eval")
+	fs.Var(&inputs, "in", "input value `id=value` (repeated)")
+	csvFile := fs.String("csv", "", "CSV file of inputs (header with input ids)")
+	format := fs.String("format", "json", "output format: json or csv")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	path, err := modelArg(positional)
+	if err != nil {
+		return err
+	}
+
+	// Outputs
+	newWriter, ok := writers[*format]
+	if !ok {
+		return fmt.Errorf("unknown format `%s` (expected: csv, json)", *format)
+	}
+
+	// Model
+	ld, err := load(path)
+	if err != nil {
+		return err
+	}
+
+	// Inputs
+	var next func() (map[string]float64, error)
+	switch {
+	case len(inputs) > 0 && *csvFile != "":
+		return fmt.Errorf("-in and -csv cannot be both defined")
+	case len(inputs) > 0:
+		next, err = flagInputs(inputs)
+	case *csvFile != "":
+		file, errOpen := os.Open(*csvFile)
+		if errOpen != nil {
+			return errOpen
+		}
+		defer file.Close()
+		next, err = csvInputs(file)
+	default:
+		next = jsonInputs(stdin)
+	}
+	if err != nil {
+		return err
+	}
+
+	return evaluate(ld, next, newWriter(stdout, ld.outputs))
+}
+
+// evaluate the system for each input until the end of the inputs
+// The results already written are flushed, even if an input fails
+func evaluate(ld loaded, next func() (map[string]float64, error), w resultWriter) error {
+	err := evaluateAll(ld, next, w)
+	if errFlush := w.flush(); err == nil {
+		err = errFlush
+	}
+	return err
+}
+
+// evaluateAll writes the result of each input until the end of the inputs
+func evaluateAll(ld loaded, next func() (map[string]float64, error), w resultWriter) error {
+	for n := 1; ; n++ {
+		values, err := next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("input #%d: %w", n, err)
+		}
+
+		input, err := ld.dataInput(values)
+		if err != nil {
+			return fmt.Errorf("input #%d: %w", n, err)
+		}
+
+		output, err := ld.sys.Evaluate(input)
+		if err != nil {
+			return fmt.Errorf("input #%d: %w", n, err)
+		}
+		if err := w.write(output); err != nil {
+			return err
+		}
+	}
+}
+
+// values parses the "-in id=value" flags
+func (in inputFlags) values() (map[string]float64, error) {
+	values := make(map[string]float64, len(in))
+	for _, flg := range in {
+		name, text, ok := strings.Cut(flg, "=")
+		if !ok {
+			return nil, fmt.Errorf("-in `%s`: id=value expected", flg)
+		}
+		value, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("-in `%s`: number expected", flg)
+		}
+		values[name] = value
+	}
+	return values, nil
+}
+
+// flagInputs returns the only input described by the flags
+func flagInputs(inputs inputFlags) (func() (map[string]float64, error), error) {
+	values, err := inputs.values()
+	if err != nil {
+		return nil, err
+	}
+
+	done := false
+	return func() (map[string]float64, error) {
+		if done {
+			return nil, io.EOF
+		}
+		done = true
+		return values, nil
+	}, nil
+}
+
+// csvInputs reads the inputs from a CSV file, one evaluation per row
+// The header contains the ids of the inputs
+func csvInputs(r io.Reader) (func() (map[string]float64, error), error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("csv header: %w", err)
+	}
+
+	return func() (map[string]float64, error) {
+		row, err := reader.Read()
+		if err != nil {
+			return nil, err
+		}
+		values := make(map[string]float64, len(row))
+		for i, text := range row {
+			value, err := strconv.ParseFloat(text, 64)
+			if err != nil {
+				return nil, fmt.Errorf("column `%s`: number expected (found: `%s`)", header[i], text)
+			}
+			values[header[i]] = value
+		}
+		return values, nil
+	}, nil
+}
+
+// jsonInputs reads the inputs from JSON lines, one evaluation per object
+func jsonInputs(r io.Reader) func() (map[string]float64, error) {
+	dec := json.NewDecoder(r)
+	return func() (map[string]float64, error) {
+		var values map[string]float64
+		if err := dec.Decode(&values); err != nil {
+			return nil, err
+		}
+		return values, nil
+	}
+}
+
+// resultWriter writes the results of the evaluations
+type resultWriter interface {
+	write(output fuzzy.DataOutput) error
+	flush() error
+}
+
+// writers lists the result writers by format
+var writers = map[string]func(w io.Writer, outputs []*fuzzy.IDVal) resultWriter{
+	"json": newJSONWriter,
+	"csv":  newCSVWriter,
+}
+
+// jsonWriter writes one JSON object per evaluation
+type jsonWriter struct {
+	enc *json.Encoder
+}
+
+// newJSONWriter creates a JSON lines writer
+func newJSONWriter(w io.Writer, _ []*fuzzy.IDVal) resultWriter {
+	return jsonWriter{enc: json.NewEncoder(w)}
+}
+
+// write the outputs as a JSON object (sorted by id)
+func (jw jsonWriter) write(output fuzzy.DataOutput) error {
+	values := make(map[id.ID]float64, len(output))
+	for idVal, value := range output {
+		values[idVal.ID()] = value
+	}
+	return jw.enc.Encode(values)
+}
+
+// flush does nothing, each object is written directly
+func (jw jsonWriter) flush() error {
+	return nil
+}
+
+// csvWriter writes one row per evaluation
+type csvWriter struct {
+	w       *csv.Writer
+	outputs []*fuzzy.IDVal
+	header  bool // true when the header has been written
+}
+
+// newCSVWriter creates a CSV writer with one column per output
+func newCSVWriter(w io.Writer, outputs []*fuzzy.IDVal) resultWriter {
+	return &csvWriter{w: csv.NewWriter(w), outputs: outputs}
+}
+
+// write the outputs as a CSV row (the header is written first)
+func (cw *csvWriter) write(output fuzzy.DataOutput) error {
+	if !cw.header {
+		cw.header = true
+		header := make([]string, len(cw.outputs))
+		for i, idVal := range cw.outputs {
+			header[i] = string(idVal.ID())
+		}
+		if err := cw.w.Write(header); err != nil {
+			return err
+		}
+	}
+
+	row := make([]string, len(cw.outputs))
+	for i, idVal := range cw.outputs {
+		row[i] = strconv.FormatFloat(output[idVal], 'g', -1, 64)
+	}
+	return cw.w.Write(row)
+}
+
+// flush writes the buffered rows
+func (cw *csvWriter) flush() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
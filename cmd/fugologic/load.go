@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sbiemont/fugologic/fll"
+	"github.com/sbiemont/fugologic/fuzzy"
+	"github.com/sbiemont/fugologic/id"
+	"github.com/sbiemont/fugologic/model"
+)
+
+// loaded is a system ready to be evaluated
+type loaded struct {
+	sys     fuzzy.System
+	values  map[id.ID]*fuzzy.IDVal
+	inputs  []*fuzzy.IDVal // external inputs, sorted by id
+	outputs []*fuzzy.IDVal // outputs of all engines, sorted by id
+}
+
+// readers lists the model readers by file extension
+var readers = map[string]func(r io.Reader) (fuzzy.System, model.Values, error){
+	".json": readModel(model.ReadJSON),
+	".yaml": readModel(model.ReadYAML),
+	".yml":  readModel(model.ReadYAML),
+	".fll":  readFLL,
+}
+
+// readModel reads and builds a declarative model
+func readModel(read func(r io.Reader) (model.Model, error)) func(r io.Reader) (fuzzy.System, model.Values, error) {
+	return func(r io.Reader) (fuzzy.System, model.Values, error) {
+		m, err := read(r)
+		if err != nil {
+			return nil, nil, err
+		}
+		return m.Build()
+	}
+}
+
+// readFLL reads a FuzzyLite engine
+func readFLL(r io.Reader) (fuzzy.System, model.Values, error) {
+	m, err := fll.Read(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	values := make(model.Values)
+	for _, idVal := range append(m.Inputs, m.Outputs...) {
+		values[idVal.ID()] = idVal
+	}
+	sys, err := fuzzy.NewSystem([]fuzzy.Engine{m.Engine})
+	if err != nil {
+		return nil, nil, err
+	}
+	return sys, values, nil
+}
+
+// load reads a model file, its format depends on its extension
+func load(path string) (loaded, error) {
+	ext := strings.ToLower(filepath.Ext(path))
+	read, ok := readers[ext]
+	if !ok {
+		return loaded{}, fmt.Errorf("%s: unknown model format `%s` (expected: .fll, .json, .yaml, .yml)", path, ext)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return loaded{}, err
+	}
+	defer file.Close()
+
+	sys, values, err := read(file)
+	if err != nil {
+		return loaded{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return newLoaded(sys, values), nil
+}
+
+// newLoaded finds the external inputs and the outputs of the system
+func newLoaded(sys fuzzy.System, values model.Values) loaded {
+	result := loaded{
+		sys:     sys,
+		values:  values,
+		inputs:  sys.Inputs(),
+		outputs: sys.Produced(),
+	}
+	return result
+}
+
+// dataInput converts values by id into system inputs
+func (ld loaded) dataInput(values map[string]float64) (fuzzy.DataInput, error) {
+	input := make(fuzzy.DataInput, len(values))
+	for name, value := range values {
+		idVal, ok := ld.values[id.ID(name)]
+		if !ok {
+			return nil, fmt.Errorf("unknown value `%s`", name)
+		}
+		input[idVal] = value
+	}
+	return input, nil
+}
@@ -0,0 +1,116 @@
+// Command fugologic loads and evaluates fuzzy systems described in files
+//
+// Supported model files are the declarative models (.json, .yaml, .yml) and FuzzyLite files (.fll)
+//
+// Usage:
+//
+//	fugologic eval [-in id=value]... [-csv file] [-format json|csv] <model file>
+//	fugologic sweep -x id [-y id] [-in id=value]... [-format csv|gnuplot] <model file>
+//	fugologic validate <model file>
+//
+// Inputs of "eval" are read from the "-in" flags, else from the CSV file (header with input ids),
+// else from JSON lines on stdin (one object per evaluation, eg.: {"HP": 75, "FP": 30})
+//
+// "sweep" evaluates the model over the universes of one or two inputs (response curve or control surface);
+// the other inputs are fixed by the "-in" flags, else set to the middle of their universe
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+// command is a subcommand of the tool
+type command struct {
+	usage string
+	run   func(args []string, stdin io.Reader, stdout io.Writer) error
+}
+
+// commands lists all subcommands by name
+var commands = map[string]command{
+	"eval": {
+		usage: "eval [-in id=value]... [-csv file] [-format json|csv] <model file>",
+		run:   runEval,
+	},
+	"sweep": {
+		usage: "sweep -x id [-y id] [-in id=value]... [-format csv|gnuplot] <model file>",
+		run:   runSweep,
+	},
+	"validate": {
+		usage: "validate <model file>",
+		run:   runValidate,
+	},
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run executes a subcommand and returns the exit code
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return 2
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "fugologic: unknown command `%s`\n", args[0])
+		usage(stderr)
+		return 2
+	}
+	if err := cmd.run(args[1:], stdin, stdout); err != nil {
+		fmt.Fprintf(stderr, "fugologic: %s\n", err)
+		return 1
+	}
+	return 0
+}
+
+// usage prints the usage of all commands
+func usage(w io.Writer) {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(w, "usage:")
+	for _, name := range names {
+		fmt.Fprintf(w, "  fugologic %s\n", commands[name].usage)
+	}
+}
+
+// parseFlags parses the flags of a command, even after the positional arguments
+// Returns the positional arguments
+func parseFlags(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// newFlagSet creates the flag set of a command, errors are returned and not printed
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	return fs
+}
+
+// modelArg returns the only expected positional argument
+func modelArg(args []string) (string, error) {
+	if len(args) != 1 {
+		return "", fmt.Errorf("one model file expected (found: %d)", len(args))
+	}
+	return args[0], nil
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// exec runs the tool and returns the exit code, stdout and stderr
+func exec(stdin string, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(args, strings.NewReader(stdin), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestRun(t *testing.T) {
+	Convey("run", t, func() {
+		Convey("when no command", func() {
+			code, stdout, stderr := exec("")
+			So(code, ShouldEqual, 2)
+			So(stdout, ShouldBeEmpty)
+			So(stderr, ShouldStartWith, "usage:\n")
+		})
+
+		Convey("when unknown command", func() {
+			code, _, stderr := exec("", "unknown")
+			So(code, ShouldEqual, 2)
+			So(stderr, ShouldStartWith, "fugologic: unknown command `unknown`\nusage:\n")
+		})
+
+		Convey("when unknown flag", func() {
+			code, _, stderr := exec("", "eval", "-unknown", "testdata/model.yaml")
+			So(code, ShouldEqual, 1)
+			So(stderr, ShouldEqual, "fugologic: flag provided but not defined: -unknown\n")
+		})
+
+		Convey("when no model", func() {
+			code, _, stderr := exec("", "validate")
+			So(code, ShouldEqual, 1)
+			So(stderr, ShouldEqual, "fugologic: one model file expected (found: 0)\n")
+		})
+	})
+}
+
+func TestValidate(t *testing.T) {
+	Convey("validate", t, func() {
+		Convey("when ok", func() {
+			code, stdout, stderr := exec("", "validate", "testdata/model.yaml")
+			So(code, ShouldEqual, 0)
+			So(stderr, ShouldBeEmpty)
+			So(stdout, ShouldEqual, "testdata/model.yaml: ok\n"+
+				"  engines: 2\n"+
+				"  rules:   9\n"+
+				"  inputs:  a, b\n"+
+				"  outputs: c, d\n")
+		})
+
+		Convey("when fll", func() {
+			code, stdout, _ := exec("", "validate", "testdata/tipper.fll")
+			So(code, ShouldEqual, 0)
+			So(stdout, ShouldContainSubstring, "  inputs:  food, service\n  outputs: tip\n")
+		})
+
+		Convey("when invalid model", func() {
+			code, _, stderr := exec("", "validate", "testdata/invalid.yaml")
+			So(code, ShouldEqual, 1)
+			So(stderr, ShouldEqual, "fugologic: testdata/invalid.yaml: model: engines[0].rules[0].if: unknown value `x`\n")
+		})
+
+		Convey("when unknown format", func() {
+			code, _, stderr := exec("", "validate", "testdata/inputs.csv")
+			So(code, ShouldEqual, 1)
+			So(stderr, ShouldEqual, "fugologic: testdata/inputs.csv: unknown model format `.csv` (expected: .fll, .json, .yaml, .yml)\n")
+		})
+	})
+}
+
+func TestEval(t *testing.T) {
+	Convey("eval", t, func() {
+		Convey("when input flags", func() {
+			code, stdout, stderr := exec("", "eval", "-in", "service=3", "--in", "food=8", "testdata/tipper.fll")
+			So(code, ShouldEqual, 0)
+			So(stderr, ShouldBeEmpty)
+			So(stdout, ShouldStartWith, `{"tip":18.00`)
+		})
+
+		Convey("when csv inputs and csv outputs", func() {
+			code, stdout, stderr := exec("", "eval", "testdata/model.yaml", "-csv", "testdata/inputs.csv", "-format", "csv")
+			So(code, ShouldEqual, 0)
+			So(stderr, ShouldBeEmpty)
+			lines := strings.Split(strings.TrimSpace(stdout), "\n")
+			So(lines, ShouldHaveLength, 3)
+			So(lines[0], ShouldEqual, "c,d")
+		})
+
+		Convey("when json lines on stdin", func() {
+			stdin := `{"a": 0.1, "b": 0.9}` + "\n" + `{"a": 0.8, "b": 0.2}` + "\n"
+			code, stdout, stderr := exec(stdin, "eval", "testdata/model.yaml")
+			So(code, ShouldEqual, 0)
+			So(stderr, ShouldBeEmpty)
+			lines := strings.Split(strings.TrimSpace(stdout), "\n")
+			So(lines, ShouldHaveLength, 2)
+			So(lines[0], ShouldStartWith, `{"c":0.35`)
+
+			// Same results using the csv file
+			_, fromCSV, _ := exec("", "eval", "-csv", "testdata/inputs.csv", "testdata/model.yaml")
+			So(fromCSV, ShouldEqual, stdout)
+		})
+
+		Convey("when failing input after csv outputs", func() {
+			stdin := `{"a": 0.1, "b": 0.9}` + "\n" + `{"a": 0.8, "b": 0.2}` + "\n" + `{"x": 1}` + "\n"
+			code, stdout, stderr := exec(stdin, "eval", "-format", "csv", "testdata/model.yaml")
+			So(code, ShouldEqual, 1)
+			So(stderr, ShouldEqual, "fugologic: input #3: unknown value `x`\n")
+
+			// Results already written are kept
+			lines := strings.Split(strings.TrimSpace(stdout), "\n")
+			So(lines, ShouldHaveLength, 3)
+			So(lines[0], ShouldEqual, "c,d")
+		})
+
+		Convey("when unknown input", func() {
+			code, _, stderr := exec("", "eval", "-in", "x=1", "testdata/model.yaml")
+			So(code, ShouldEqual, 1)
+			So(stderr, ShouldEqual, "fugologic: input #1: unknown value `x`\n")
+		})
+
+		Convey("when invalid input", func() {
+			code, _, stderr := exec("", "eval", "-in", "a", "testdata/model.yaml")
+			So(code, ShouldEqual, 1)
+			So(stderr, ShouldEqual, "fugologic: -in `a`: id=value expected\n")
+
+			code, _, stderr = exec(`{"a": "x"}`, "eval", "testdata/model.yaml")
+			So(code, ShouldEqual, 1)
+			So(stderr, ShouldStartWith, "fugologic: input #1: json: ")
+		})
+
+		Convey("when both input flags and csv", func() {
+			code, _, stderr := exec("", "eval", "-in", "a=1", "-csv", "testdata/inputs.csv", "testdata/model.yaml")
+			So(code, ShouldEqual, 1)
+			So(stderr, ShouldEqual, "fugologic: -in and -csv cannot be both defined\n")
+		})
+
+		Convey("when unknown output format", func() {
+			code, _, stderr := exec("", "eval", "-format", "xml", "testdata/model.yaml")
+			So(code, ShouldEqual, 1)
+			So(stderr, ShouldEqual, "fugologic: unknown format `xml` (expected: csv, json)\n")
+		})
+	})
+}
+
+func TestSweep(t *testing.T) {
+	Convey("sweep", t, func() {
+		Convey("when 1 input", func() {
+			code, stdout, stderr := exec("", "sweep", "-x", "a", "-in", "b=0.9", "testdata/model.yaml")
+			So(code, ShouldEqual, 0)
+			So(stderr, ShouldBeEmpty)
+			lines := strings.Split(strings.TrimSpace(stdout), "\n")
+			So(lines, ShouldHaveLength, 12)
+			So(lines[0], ShouldEqual, "a,c,d")
+			So(lines[2], ShouldStartWith, "0.1,0.35")
+
+			// Same as the evaluation
+			_, evaluated, _ := exec("", "eval", "-in", "a=0.1", "-in", "b=0.9", "-format", "csv", "testdata/model.yaml")
+			So(lines[2], ShouldEqual, "0.1,"+strings.Split(evaluated, "\n")[1])
+		})
+
+		Convey("when 2 inputs as gnuplot", func() {
+			code, stdout, stderr := exec("", "sweep", "-x", "a", "-y", "b", "-format", "gnuplot", "testdata/model.yaml")
+			So(code, ShouldEqual, 0)
+			So(stderr, ShouldBeEmpty)
+			So(stdout, ShouldStartWith, "$sweep << EOD\n# a b c d\n")
+			So(stdout, ShouldEndWith, "\nEOD\n")
+			So(strings.Count(stdout, "\n\n"), ShouldEqual, 10) // 11 scans
+		})
+
+		Convey("when no swept input", func() {
+			code, _, stderr := exec("", "sweep", "testdata/model.yaml")
+			So(code, ShouldEqual, 1)
+			So(stderr, ShouldEqual, "fugologic: -x input expected\n")
+		})
+
+		Convey("when unknown input", func() {
+			code, _, stderr := exec("", "sweep", "-x", "a", "-y", "x", "testdata/model.yaml")
+			So(code, ShouldEqual, 1)
+			So(stderr, ShouldEqual, "fugologic: unknown value `x`\n")
+		})
+
+		Convey("when unknown format", func() {
+			code, _, stderr := exec("", "sweep", "-x", "a", "-format", "json", "testdata/model.yaml")
+			So(code, ShouldEqual, 1)
+			So(stderr, ShouldEqual, "fugologic: unknown format `json` (expected: csv, gnuplot)\n")
+		})
+	})
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/sbiemont/fugologic/fuzzy"
+	"github.com/sbiemont/fugologic/id"
+	"github.com/sbiemont/fugologic/sweep"
+)
+
+// runSweep evaluates a model over the universes of one or two inputs
+// The other inputs are fixed by flags, else set to the middle of their universe
+func runSweep(args []string, _ io.Reader, stdout io.Writer) error {
+	var inputs inputFlags
+	fs := newFlagSet("sweep")
+	fs.Var(&inputs, "in", "fixed input value `id=value` (repeated)")
+	x := fs.String("x", "", "first swept input id")
+	y := fs.String("y", "", "second swept input id (optional)")
+	format := fs.String("format", "csv", "output format: csv or gnuplot")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	path, err := modelArg(positional)
+	if err != nil {
+		return err
+	}
+	if *x == "" {
+		return fmt.Errorf("-x input expected")
+	}
+	if *format != "csv" && *format != "gnuplot" {
+		return fmt.Errorf("unknown format `%s` (expected: csv, gnuplot)", *format)
+	}
+
+	// Model
+	ld, err := load(path)
+	if err != nil {
+		return err
+	}
+
+	// Swept inputs
+	var swept []*fuzzy.IDVal
+	for _, name := range []string{*x, *y} {
+		if name == "" {
+			continue
+		}
+		idVal, ok := ld.values[id.ID(name)]
+		if !ok {
+			return fmt.Errorf("unknown value `%s`", name)
+		}
+		swept = append(swept, idVal)
+	}
+
+	// Fixed inputs
+	values, err := inputs.values()
+	if err != nil {
+		return err
+	}
+	fixed, err := ld.dataInput(values)
+	if err != nil {
+		return err
+	}
+	for _, idVal := range ld.inputs {
+		if _, ok := fixed[idVal]; !ok {
+			fixed[idVal] = (idVal.U().XMin() + idVal.U().XMax()) / 2
+		}
+	}
+
+	// Sweep
+	tbl, err := sweep.Sweep(ld.sys, fixed, swept...)
+	if err != nil {
+		return err
+	}
+	if *format == "gnuplot" {
+		return tbl.WriteGnuplot(stdout, "sweep")
+	}
+	return tbl.WriteCSV(stdout)
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/sbiemont/fugologic/fuzzy"
+)
+
+// runValidate loads a model and reports its errors, or describes its inputs and outputs
+func runValidate(args []string, _ io.Reader, stdout io.Writer) error {
+	positional, err := parseFlags(newFlagSet("validate"), args)
+	if err != nil {
+		return err
+	}
+	path, err := modelArg(positional)
+	if err != nil {
+		return err
+	}
+
+	ld, err := load(path)
+	if err != nil {
+		return err
+	}
+
+	rules := 0
+	for _, eng := range ld.sys {
+		rules += len(eng.Rules())
+	}
+	fmt.Fprintf(stdout, "%s: ok\n", path)
+	fmt.Fprintf(stdout, "  engines: %d\n", len(ld.sys))
+	fmt.Fprintf(stdout, "  rules:   %d\n", rules)
+	fmt.Fprintf(stdout, "  inputs:  %s\n", names(ld.inputs))
+	fmt.Fprintf(stdout, "  outputs: %s\n", names(ld.outputs))
+	return nil
+}
+
+// names joins the ids of the values
+func names(idVals []*fuzzy.IDVal) string {
+	result := make([]string, len(idVals))
+	for i, idVal := range idVals {
+		result[i] = string(idVal.ID())
+	}
+	return strings.Join(result, ", ")
+}
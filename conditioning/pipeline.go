@@ -0,0 +1,169 @@
+// Package conditioning pre-processes the inputs and post-processes the outputs of an engine or a system
+package conditioning
+
+import (
+	"github.com/sbiemont/fugologic/fuzzy"
+	"github.com/sbiemont/fugologic/id"
+)
+
+// Pipeline evaluates an engine or a system with conditioned inputs and outputs
+// Stages of a value are applied in their order of declaration
+// A pipeline keeps the state of its stages, it shall not be used concurrently
+type Pipeline struct {
+	ev         fuzzy.Tracer
+	inputs     map[*fuzzy.IDVal][]Stage
+	outputs    map[*fuzzy.IDVal][]Stage
+	hysteresis map[*fuzzy.IDVal]*hysteresis
+}
+
+// Trace describes the whole chain of an evaluation
+type Trace struct {
+	Raw    fuzzy.DataInput        // inputs before conditioning
+	Input  fuzzy.DataInput        // inputs after conditioning
+	Engine fuzzy.Trace            // raw outputs and aggregated sets of the engine (or the system)
+	Output fuzzy.DataOutput       // outputs after conditioning
+	Labels map[*fuzzy.IDVal]id.ID // labels of the outputs with hysteresis
+}
+
+// New creates a pipeline without any stage
+func New(ev fuzzy.Tracer) *Pipeline {
+	return &Pipeline{
+		ev:         ev,
+		inputs:     make(map[*fuzzy.IDVal][]Stage),
+		outputs:    make(map[*fuzzy.IDVal][]Stage),
+		hysteresis: make(map[*fuzzy.IDVal]*hysteresis),
+	}
+}
+
+// Input appends stages applied to an input value before the evaluation
+func (p *Pipeline) Input(fv *fuzzy.IDVal, stages ...Stage) *Pipeline {
+	p.inputs[fv] = append(p.inputs[fv], stages...)
+	return p
+}
+
+// Output appends stages applied to an output value after the evaluation
+func (p *Pipeline) Output(fv *fuzzy.IDVal, stages ...Stage) *Pipeline {
+	p.outputs[fv] = append(p.outputs[fv], stages...)
+	return p
+}
+
+// Hysteresis computes the label of an output (its term of highest membership, after conditioning)
+// The label only switches when the membership of the new term exceeds the one of the current term by the margin
+func (p *Pipeline) Hysteresis(fv *fuzzy.IDVal, margin float64) *Pipeline {
+	p.hysteresis[fv] = &hysteresis{fv: fv, margin: margin}
+	return p
+}
+
+// Evaluate conditions the inputs, evaluates the engine (or the system) and conditions the outputs
+func (p *Pipeline) Evaluate(input fuzzy.DataInput) (fuzzy.DataOutput, error) {
+	trace, err := p.run(input, func(in fuzzy.DataInput) (fuzzy.Trace, error) {
+		output, err := p.ev.Evaluate(in)
+		return fuzzy.Trace{Output: output}, err
+	})
+	return trace.Output, err
+}
+
+// Trace evaluates the pipeline like Evaluate, and also returns all intermediate results
+func (p *Pipeline) Trace(input fuzzy.DataInput) (Trace, error) {
+	return p.run(input, p.ev.Trace)
+}
+
+// Labels returns the current labels of the outputs with hysteresis
+func (p *Pipeline) Labels() map[*fuzzy.IDVal]id.ID {
+	result := make(map[*fuzzy.IDVal]id.ID)
+	for fv, hst := range p.hysteresis {
+		if hst.label != "" {
+			result[fv] = hst.label
+		}
+	}
+	return result
+}
+
+// Reset clears the state of all stages and labels
+func (p *Pipeline) Reset() {
+	for _, stages := range p.inputs {
+		reset(stages)
+	}
+	for _, stages := range p.outputs {
+		reset(stages)
+	}
+	for _, hst := range p.hysteresis {
+		hst.label = ""
+	}
+}
+
+// run evaluates the whole chain using the evaluation function
+// On error, only the input stages have been applied
+func (p *Pipeline) run(input fuzzy.DataInput, eval func(fuzzy.DataInput) (fuzzy.Trace, error)) (Trace, error) {
+	conditioned := fuzzy.DataInput{}
+	for fv, value := range input {
+		conditioned[fv] = apply(p.inputs[fv], value)
+	}
+
+	trace, err := eval(conditioned)
+	if err != nil {
+		return Trace{}, err
+	}
+
+	output := fuzzy.DataOutput{}
+	for fv, value := range trace.Output {
+		output[fv] = apply(p.outputs[fv], value)
+	}
+	for fv, hst := range p.hysteresis {
+		if value, exists := output[fv]; exists {
+			hst.update(value)
+		}
+	}
+
+	return Trace{
+		Raw:    input,
+		Input:  conditioned,
+		Engine: trace,
+		Output: output,
+		Labels: p.Labels(),
+	}, nil
+}
+
+// apply chains the stages
+func apply(stages []Stage, x float64) float64 {
+	for _, stage := range stages {
+		x = stage.Apply(x)
+	}
+	return x
+}
+
+// reset clears the state of the stages
+func reset(stages []Stage) {
+	for _, stage := range stages {
+		stage.Reset()
+	}
+}
+
+// hysteresis keeps the current label of an output
+type hysteresis struct {
+	fv     *fuzzy.IDVal
+	margin float64
+	label  id.ID
+}
+
+// update switches to the term of highest membership if it is better than the current one by the margin
+func (hst *hysteresis) update(x float64) {
+	var best fuzzy.IDSet
+	bestValue := -1.0
+	for _, idSet := range hst.fv.Terms() {
+		if value := idSet.Set()(x); value > bestValue {
+			best, bestValue = idSet, value
+		}
+	}
+	if bestValue < 0 {
+		return
+	}
+
+	if hst.label != "" {
+		current := hst.fv.Get(hst.label).Set()(x)
+		if bestValue <= current+hst.margin {
+			return
+		}
+	}
+	hst.label = best.ID()
+}
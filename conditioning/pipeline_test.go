@@ -0,0 +1,127 @@
+package conditioning
+
+import (
+	"testing"
+
+	"github.com/sbiemont/fugologic/builder"
+	"github.com/sbiemont/fugologic/crisp"
+	"github.com/sbiemont/fugologic/fuzzy"
+	"github.com/sbiemont/fugologic/id"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestPipeline(t *testing.T) {
+	Convey("pipeline", t, func() {
+		newVal := func(name id.ID) *fuzzy.IDVal {
+			u, err := crisp.NewSet(0, 1, 0.1)
+			So(err, ShouldBeNil)
+			fv, err := fuzzy.NewIDValBuilders(name, u, map[id.ID]fuzzy.SetBuilder{
+				"low":  fuzzy.StepDown{A: 0, B: 1},
+				"high": fuzzy.StepUp{A: 0, B: 1},
+			})
+			So(err, ShouldBeNil)
+			return fv
+		}
+		fvA, fvB := newVal("a"), newVal("b")
+
+		// a => b
+		fl := builder.Mamdani().FuzzyLogic()
+		fl.If(fvA.Get("low")).Then(fvB.Get("low"))
+		fl.If(fvA.Get("high")).Then(fvB.Get("high"))
+		eng, err := fl.Engine()
+		So(err, ShouldBeNil)
+
+		evaluate := func(a float64) float64 {
+			out, err := eng.Evaluate(fuzzy.DataInput{fvA: a})
+			So(err, ShouldBeNil)
+			return out[fvB]
+		}
+
+		Convey("when no stage", func() {
+			out, err := New(eng).Evaluate(fuzzy.DataInput{fvA: 0.3})
+			So(err, ShouldBeNil)
+			So(out, ShouldResemble, fuzzy.DataOutput{fvB: evaluate(0.3)})
+		})
+
+		Convey("when stages", func() {
+			p := New(eng).
+				Input(fvA, Gain(0.01), Clamp(0, 1)).
+				Output(fvB, Gain(100), Offset(1))
+
+			out, err := p.Evaluate(fuzzy.DataInput{fvA: 30})
+			So(err, ShouldBeNil)
+			So(out[fvB], ShouldAlmostEqual, evaluate(0.3)*100+1)
+
+			out, err = p.Evaluate(fuzzy.DataInput{fvA: 300})
+			So(err, ShouldBeNil)
+			So(out[fvB], ShouldAlmostEqual, evaluate(1)*100+1)
+		})
+
+		Convey("when trace", func() {
+			sys, err := fuzzy.NewSystem([]fuzzy.Engine{eng})
+			So(err, ShouldBeNil)
+			p := New(sys).Input(fvA, Gain(0.5)).Output(fvB, Gain(0.5)).Hysteresis(fvB, 0.1)
+
+			trace, err := p.Trace(fuzzy.DataInput{fvA: 0.4})
+			So(err, ShouldBeNil)
+			So(trace.Raw, ShouldResemble, fuzzy.DataInput{fvA: 0.4})
+			So(trace.Input, ShouldResemble, fuzzy.DataInput{fvA: 0.2})
+			So(trace.Engine.Output, ShouldResemble, fuzzy.DataOutput{fvB: evaluate(0.2)})
+			So(trace.Engine.Aggregated, ShouldContainKey, fvB)
+			So(trace.Output, ShouldResemble, fuzzy.DataOutput{fvB: evaluate(0.2) * 0.5})
+			So(trace.Labels, ShouldResemble, map[*fuzzy.IDVal]id.ID{fvB: "low"})
+		})
+
+		Convey("when stateful stages", func() {
+			p := New(eng).Input(fvA, MovingAverage(2)).Output(fvB, SlewRate(0.01))
+
+			out1, err := p.Evaluate(fuzzy.DataInput{fvA: 0})
+			So(err, ShouldBeNil)
+			So(out1[fvB], ShouldAlmostEqual, evaluate(0))
+
+			// Input filtered, output limited
+			out2, err := p.Evaluate(fuzzy.DataInput{fvA: 1})
+			So(err, ShouldBeNil)
+			So(evaluate(0.5), ShouldBeGreaterThan, out1[fvB]+0.01)
+			So(out2[fvB], ShouldAlmostEqual, out1[fvB]+0.01)
+
+			// Reset
+			p.Reset()
+			out3, err := p.Evaluate(fuzzy.DataInput{fvA: 1})
+			So(err, ShouldBeNil)
+			So(out3[fvB], ShouldAlmostEqual, evaluate(1))
+		})
+
+		Convey("when hysteresis", func() {
+			// The output is replaced by a given value
+			p := New(eng).Output(fvB, Gain(0), Offset(0)).Hysteresis(fvB, 0.2)
+			label := func(b float64) string {
+				p.outputs[fvB] = []Stage{Gain(0), Offset(b)}
+				_, err := p.Evaluate(fuzzy.DataInput{fvA: 0})
+				So(err, ShouldBeNil)
+				return string(p.Labels()[fvB])
+			}
+			So(p.Labels(), ShouldBeEmpty)
+			So(label(0.2), ShouldEqual, "low")
+			So(label(0.55), ShouldEqual, "low") // high: 0.55 <= low: 0.45 + 0.2
+			So(label(0.65), ShouldEqual, "high")
+			So(label(0.45), ShouldEqual, "high")
+			So(label(0.35), ShouldEqual, "low")
+
+			p.Reset()
+			So(p.Labels(), ShouldBeEmpty)
+			So(label(0.55), ShouldEqual, "high")
+		})
+
+		Convey("when error", func() {
+			p := New(eng).Output(fvB, SlewRate(0.01))
+			_, err := p.Evaluate(fuzzy.DataInput{})
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldStartWith, "input: cannot find data for id val `a`")
+
+			_, err = p.Trace(fuzzy.DataInput{})
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,106 @@
+package conditioning
+
+// Stage transforms a value, stages are chained in a pipeline
+// A stage can keep a state (eg.: a filter), it shall only be used by one value of one pipeline
+type Stage interface {
+	Apply(x float64) float64 // Apply transforms the value
+	Reset()                  // Reset clears the state of the stage
+}
+
+// stateless is a stage without state
+type stateless func(x float64) float64
+
+func (fn stateless) Apply(x float64) float64 { return fn(x) }
+func (fn stateless) Reset()                  {}
+
+// Gain multiplies the value
+func Gain(k float64) Stage {
+	return stateless(func(x float64) float64 {
+		return k * x
+	})
+}
+
+// Offset adds a constant to the value
+func Offset(b float64) Stage {
+	return stateless(func(x float64) float64 {
+		return x + b
+	})
+}
+
+// Clamp limits the value to [min ; max]
+func Clamp(min, max float64) Stage {
+	return stateless(func(x float64) float64 {
+		switch {
+		case x < min:
+			return min
+		case x > max:
+			return max
+		default:
+			return x
+		}
+	})
+}
+
+// Deadband sets the value to 0 when it is within [-width ; width]
+func Deadband(width float64) Stage {
+	return stateless(func(x float64) float64 {
+		if -width <= x && x <= width {
+			return 0
+		}
+		return x
+	})
+}
+
+// movingAverage keeps the last values
+type movingAverage struct {
+	n      int
+	values []float64
+}
+
+// MovingAverage returns the mean of the last n values (the values received so far before n values)
+func MovingAverage(n int) Stage {
+	return &movingAverage{n: max(n, 1)}
+}
+
+func (ma *movingAverage) Apply(x float64) float64 {
+	ma.values = append(ma.values, x)
+	if len(ma.values) > ma.n {
+		ma.values = ma.values[1:]
+	}
+	var sum float64
+	for _, value := range ma.values {
+		sum += value
+	}
+	return sum / float64(len(ma.values))
+}
+
+func (ma *movingAverage) Reset() {
+	ma.values = nil
+}
+
+// slewRate keeps the previous value
+type slewRate struct {
+	maxStep     float64
+	initialized bool
+	prev        float64
+}
+
+// SlewRate limits the change of the value between two evaluations to maxStep
+// The first value is not limited
+func SlewRate(maxStep float64) Stage {
+	return &slewRate{maxStep: maxStep}
+}
+
+func (sr *slewRate) Apply(x float64) float64 {
+	if sr.initialized {
+		x = min(max(x, sr.prev-sr.maxStep), sr.prev+sr.maxStep)
+	}
+	sr.initialized = true
+	sr.prev = x
+	return x
+}
+
+func (sr *slewRate) Reset() {
+	sr.initialized = false
+	sr.prev = 0
+}
@@ -0,0 +1,52 @@
+package conditioning
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// applyAll applies the stage to all values
+func applyAll(stage Stage, values ...float64) []float64 {
+	result := make([]float64, len(values))
+	for i, value := range values {
+		result[i] = stage.Apply(value)
+	}
+	return result
+}
+
+func TestStage(t *testing.T) {
+	Convey("stage", t, func() {
+		Convey("gain and offset", func() {
+			So(applyAll(Gain(2), 1, -3), ShouldResemble, []float64{2, -6})
+			So(applyAll(Offset(-1), 1, -3), ShouldResemble, []float64{0, -4})
+		})
+
+		Convey("clamp", func() {
+			So(applyAll(Clamp(-1, 2), -5, 0.5, 5), ShouldResemble, []float64{-1, 0.5, 2})
+		})
+
+		Convey("deadband", func() {
+			So(applyAll(Deadband(0.1), -0.2, -0.1, 0.05, 0.1, 0.3), ShouldResemble, []float64{-0.2, 0, 0, 0, 0.3})
+		})
+
+		Convey("moving average", func() {
+			stage := MovingAverage(3)
+			So(applyAll(stage, 3, 6, 9, 12), ShouldResemble, []float64{3, 4.5, 6, 9})
+
+			stage.Reset()
+			So(stage.Apply(1), ShouldEqual, 1)
+
+			// No filter
+			So(applyAll(MovingAverage(0), 1, 2), ShouldResemble, []float64{1, 2})
+		})
+
+		Convey("slew rate", func() {
+			stage := SlewRate(0.5)
+			So(applyAll(stage, 10, 12, 11.8, 5), ShouldResemble, []float64{10, 10.5, 11, 10.5})
+
+			stage.Reset()
+			So(stage.Apply(0), ShouldEqual, 0)
+		})
+	})
+}
@@ -0,0 +1,241 @@
+// Package controller provides fuzzy P, PD, PI and PID controllers built on fuzzy engines
+package controller
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/sbiemont/fugologic/fuzzy"
+)
+
+// kind of controller
+type kind int
+
+const (
+	kindP kind = iota
+	kindPD
+	kindPI
+	kindPID
+)
+
+// Gains scale the inputs (from the process to the universes of the engine) and the output (from the engine to the process)
+type Gains struct {
+	Ke float64 // gain of the error
+	Kd float64 // gain of the derivative of the error (PD, PI, PID)
+	Ku float64 // gain of the output of the engine (P, PD, PID)
+	Ki float64 // gain of the integral of the output of the engine (PI, PID)
+}
+
+// Option configures a controller
+type Option func(ctrl *Controller)
+
+// WithSampleTime sets the minimal time between two computations, the output is held in between
+func WithSampleTime(ts float64) Option {
+	return func(ctrl *Controller) {
+		ctrl.sampleTime = ts
+	}
+}
+
+// WithSaturation limits the output of the controller
+// The integral stops growing when the output is saturated (anti-windup)
+func WithSaturation(min, max float64) Option {
+	return func(ctrl *Controller) {
+		ctrl.saturated = true
+		ctrl.min = min
+		ctrl.max = max
+	}
+}
+
+// WithDerivativeFilter filters the derivative of the error with a first order low-pass filter (tf is its time constant)
+func WithDerivativeFilter(tf float64) Option {
+	return func(ctrl *Controller) {
+		ctrl.filter = tf
+	}
+}
+
+// Controller is a fuzzy controller
+// The engine computes a command from the scaled error (and the scaled derivative of the error)
+// The output of the controller is computed depending on its kind:
+//   - P, PD: Ku * u
+//   - PI: Ki * ∫u
+//   - PID: Ku * u + Ki * ∫u
+//
+// A controller shall not be used concurrently
+type Controller struct {
+	kind   kind
+	eng    fuzzy.Engine
+	fvErr  *fuzzy.IDVal
+	fvDErr *fuzzy.IDVal
+	fvOut  *fuzzy.IDVal
+	gains  Gains
+
+	// Options
+	sampleTime float64
+	filter     float64
+	saturated  bool
+	min, max   float64
+
+	// State
+	initialized bool
+	elapsed     float64 // time since the last computation
+	prevErr     float64 // last error
+	derr        float64 // last (filtered) derivative of the error
+	integral    float64 // integral part of the output
+	output      float64 // last output
+	err         error   // last evaluation error
+}
+
+// NewP creates a fuzzy proportional controller: the engine only uses the error
+func NewP(eng fuzzy.Engine, fvErr, fvOut *fuzzy.IDVal, gains Gains, opts ...Option) (*Controller, error) {
+	return newController(kindP, eng, fvErr, nil, fvOut, gains, opts)
+}
+
+// NewPD creates a fuzzy proportional-derivative controller
+func NewPD(eng fuzzy.Engine, fvErr, fvDErr, fvOut *fuzzy.IDVal, gains Gains, opts ...Option) (*Controller, error) {
+	return newController(kindPD, eng, fvErr, fvDErr, fvOut, gains, opts)
+}
+
+// NewPI creates a fuzzy proportional-integral controller: the output of the engine is integrated
+func NewPI(eng fuzzy.Engine, fvErr, fvDErr, fvOut *fuzzy.IDVal, gains Gains, opts ...Option) (*Controller, error) {
+	return newController(kindPI, eng, fvErr, fvDErr, fvOut, gains, opts)
+}
+
+// NewPID creates a fuzzy proportional-integral-derivative controller
+func NewPID(eng fuzzy.Engine, fvErr, fvDErr, fvOut *fuzzy.IDVal, gains Gains, opts ...Option) (*Controller, error) {
+	return newController(kindPID, eng, fvErr, fvDErr, fvOut, gains, opts)
+}
+
+// newController checks the configuration and creates a new controller
+func newController(knd kind, eng fuzzy.Engine, fvErr, fvDErr, fvOut *fuzzy.IDVal, gains Gains, opts []Option) (*Controller, error) {
+	ctrl := &Controller{
+		kind:   knd,
+		eng:    eng,
+		fvErr:  fvErr,
+		fvDErr: fvDErr,
+		fvOut:  fvOut,
+		gains:  gains,
+	}
+	for _, opt := range opts {
+		opt(ctrl)
+	}
+
+	// Check values
+	wantedInputs := []*fuzzy.IDVal{fvErr}
+	if knd != kindP {
+		wantedInputs = append(wantedInputs, fvDErr)
+	}
+	if fvOut == nil || slices.Contains(wantedInputs, nil) {
+		return nil, fmt.Errorf("controller: nil value")
+	}
+	inputs, outputs := eng.IO()
+	in, out := fuzzy.IDSets(inputs).IDVals(), fuzzy.IDSets(outputs).IDVals()
+	for _, fv := range wantedInputs {
+		if _, exists := in[fv]; !exists {
+			return nil, fmt.Errorf("controller: engine does not use input `%s`", fv.ID())
+		}
+	}
+	if _, exists := out[fvOut]; !exists {
+		return nil, fmt.Errorf("controller: engine does not produce output `%s`", fvOut.ID())
+	}
+
+	// Check options
+	if ctrl.sampleTime < 0 {
+		return nil, fmt.Errorf("controller: negative sample time %g", ctrl.sampleTime)
+	}
+	if ctrl.filter < 0 {
+		return nil, fmt.Errorf("controller: negative derivative filter %g", ctrl.filter)
+	}
+	if ctrl.saturated && ctrl.min > ctrl.max {
+		return nil, fmt.Errorf("controller: saturation min %g > max %g", ctrl.min, ctrl.max)
+	}
+
+	return ctrl, nil
+}
+
+// Update computes the new output of the controller, dt is the time elapsed since the last update
+// The previous output is held until the sample time is reached
+// If the engine cannot be evaluated, the previous output is held and the error is kept (see Err)
+func (ctrl *Controller) Update(setpoint, measurement, dt float64) float64 {
+	if dt > 0 {
+		ctrl.elapsed += dt
+	}
+	if ctrl.initialized && (ctrl.elapsed <= 0 || ctrl.elapsed < ctrl.sampleTime) {
+		return ctrl.output
+	}
+	h := ctrl.elapsed
+
+	// Error and its derivative
+	e := setpoint - measurement
+	var de float64
+	if ctrl.initialized {
+		raw := (e - ctrl.prevErr) / h
+		de = raw
+		if ctrl.filter > 0 {
+			de = ctrl.derr + h/(ctrl.filter+h)*(raw-ctrl.derr)
+		}
+	}
+
+	// Evaluate the engine
+	input := fuzzy.DataInput{ctrl.fvErr: ctrl.gains.Ke * e}
+	if ctrl.kind != kindP {
+		input[ctrl.fvDErr] = ctrl.gains.Kd * de
+	}
+	result, err := ctrl.eng.Evaluate(input)
+	ctrl.err = err
+	if err != nil {
+		return ctrl.output
+	}
+	u := result[ctrl.fvOut]
+
+	// Proportional and integral parts
+	var proportional float64
+	if ctrl.kind != kindPI {
+		proportional = ctrl.gains.Ku * u
+	}
+	integral := ctrl.integral
+	if ctrl.kind == kindPI || ctrl.kind == kindPID {
+		integral += ctrl.gains.Ki * u * h
+	}
+	output := proportional + integral
+
+	// Saturation and anti-windup
+	if ctrl.saturated {
+		// Only integrate up to the saturation
+		switch {
+		case output > ctrl.max && integral > ctrl.integral:
+			integral = max(ctrl.integral, ctrl.max-proportional)
+		case output < ctrl.min && integral < ctrl.integral:
+			integral = min(ctrl.integral, ctrl.min-proportional)
+		}
+		output = min(max(proportional+integral, ctrl.min), ctrl.max)
+	}
+
+	ctrl.initialized = true
+	ctrl.elapsed = 0
+	ctrl.prevErr = e
+	ctrl.derr = de
+	ctrl.integral = integral
+	ctrl.output = output
+	return output
+}
+
+// Output returns the last output of the controller
+func (ctrl *Controller) Output() float64 {
+	return ctrl.output
+}
+
+// Err returns the error of the last evaluation of the engine (nil if none)
+func (ctrl *Controller) Err() error {
+	return ctrl.err
+}
+
+// Reset clears the state of the controller (error, derivative, integral and output)
+func (ctrl *Controller) Reset() {
+	ctrl.initialized = false
+	ctrl.elapsed = 0
+	ctrl.prevErr = 0
+	ctrl.derr = 0
+	ctrl.integral = 0
+	ctrl.output = 0
+	ctrl.err = nil
+}
@@ -0,0 +1,148 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/sbiemont/fugologic/builder"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// simulate a first order plant (time constant 1s) driven by the controller
+// Returns the measurements
+func simulate(ctrl *Controller, setpoint float64, steps int, dt float64) []float64 {
+	var x float64
+	result := make([]float64, steps)
+	for i := range steps {
+		u := ctrl.Update(setpoint, x, dt)
+		x += (u - x) * dt
+		result[i] = x
+	}
+	return result
+}
+
+func TestController(t *testing.T) {
+	Convey("controller", t, func() {
+		eng, fvErr, fvDErr, fvOut := newStandard()
+
+		Convey("when P", func() {
+			fl := builder.Mamdani().FuzzyLogic()
+			for _, term := range Terms() {
+				fl.If(fvErr.Get(term)).Then(fvOut.Get(term))
+			}
+			engP, err := fl.Engine()
+			So(err, ShouldBeNil)
+
+			ctrl, err := NewP(engP, fvErr, fvOut, Gains{Ke: 1, Ku: 4})
+			So(err, ShouldBeNil)
+			xs := simulate(ctrl, 0.5, 100, 0.1)
+
+			// Static error of a proportional controller
+			x := xs[len(xs)-1]
+			So(x, ShouldBeBetween, 0.3, 0.5)
+			So(ctrl.Err(), ShouldBeNil)
+		})
+
+		Convey("when PD", func() {
+			ctrl, err := NewPD(eng, fvErr, fvDErr, fvOut, Gains{Ke: 1, Kd: 0.1, Ku: 4})
+			So(err, ShouldBeNil)
+			xs := simulate(ctrl, 0.5, 100, 0.1)
+			So(xs[len(xs)-1], ShouldBeBetween, 0.3, 0.5)
+		})
+
+		Convey("when PI", func() {
+			ctrl, err := NewPI(eng, fvErr, fvDErr, fvOut, Gains{Ke: 1, Kd: 0.1, Ki: 2})
+			So(err, ShouldBeNil)
+			xs := simulate(ctrl, 0.5, 300, 0.1)
+			So(xs[len(xs)-1], ShouldAlmostEqual, 0.5, 0.01)
+		})
+
+		Convey("when PID", func() {
+			ctrl, err := NewPID(eng, fvErr, fvDErr, fvOut, Gains{Ke: 1, Kd: 0.1, Ku: 1, Ki: 2})
+			So(err, ShouldBeNil)
+			xs := simulate(ctrl, 0.5, 300, 0.1)
+			So(xs[len(xs)-1], ShouldAlmostEqual, 0.5, 0.01)
+
+			// Reset
+			ctrl.Reset()
+			So(ctrl.Output(), ShouldEqual, 0)
+			So(ctrl.integral, ShouldEqual, 0)
+			So(simulate(ctrl, 0.5, 300, 0.1), ShouldResemble, xs)
+		})
+
+		Convey("when saturation", func() {
+			// Unreachable set-point: the integral shall not grow without limit
+			ctrl, err := NewPI(eng, fvErr, fvDErr, fvOut, Gains{Ke: 1, Kd: 0.1, Ki: 2}, WithSaturation(-0.2, 0.2))
+			So(err, ShouldBeNil)
+			for _, x := range simulate(ctrl, 0.5, 300, 0.1) {
+				So(x, ShouldBeLessThanOrEqualTo, 0.2)
+			}
+			So(ctrl.Output(), ShouldEqual, 0.2)
+			So(ctrl.integral, ShouldBeLessThanOrEqualTo, 0.2)
+
+			// Immediate recovery when the set-point is reachable again
+			u := ctrl.Update(0, 0.2, 0.1)
+			So(u, ShouldBeLessThan, 0.2)
+		})
+
+		Convey("when sample time", func() {
+			ctrl, err := NewPD(eng, fvErr, fvDErr, fvOut, Gains{Ke: 1, Kd: 0.1, Ku: 1}, WithSampleTime(0.3))
+			So(err, ShouldBeNil)
+			u0 := ctrl.Update(0.5, 0, 0.1)
+			So(u0, ShouldBeGreaterThan, 0)
+
+			// Held until 0.3s
+			So(ctrl.Update(0, 0, 0.1), ShouldEqual, u0)
+			So(ctrl.Update(0, 0, 0.1), ShouldEqual, u0)
+			So(ctrl.Update(0, 0, 0), ShouldEqual, u0)
+			So(ctrl.Update(0, 0, 0.1), ShouldNotEqual, u0)
+			So(ctrl.derr, ShouldAlmostEqual, -0.5/0.3)
+		})
+
+		Convey("when derivative filter", func() {
+			raw, err := NewPD(eng, fvErr, fvDErr, fvOut, Gains{Ke: 1, Kd: 0.1, Ku: 1})
+			So(err, ShouldBeNil)
+			filtered, err := NewPD(eng, fvErr, fvDErr, fvOut, Gains{Ke: 1, Kd: 0.1, Ku: 1}, WithDerivativeFilter(0.9))
+			So(err, ShouldBeNil)
+
+			for _, ctrl := range []*Controller{raw, filtered} {
+				ctrl.Update(0, 0, 0.1)
+				ctrl.Update(1, 0, 0.1)
+			}
+			So(raw.derr, ShouldAlmostEqual, 10)
+			So(filtered.derr, ShouldAlmostEqual, 1)
+		})
+
+		Convey("when evaluation error", func() {
+			// The derivative is not provided to the engine
+			ctrl, err := NewP(eng, fvErr, fvOut, Gains{Ke: 1, Ku: 1})
+			So(err, ShouldBeNil)
+			So(ctrl.Update(0.5, 0, 0.1), ShouldEqual, 0)
+			So(ctrl.Err(), ShouldNotBeNil)
+			So(ctrl.Err().Error(), ShouldStartWith, "input: cannot find data for id val `de`")
+		})
+
+		Convey("when ko", func() {
+			_, err := NewPD(eng, fvErr, nil, fvOut, Gains{})
+			So(err, ShouldBeError, "controller: nil value")
+
+			_, err = NewP(eng, fvErr, nil, Gains{})
+			So(err, ShouldBeError, "controller: nil value")
+
+			_, err = NewPD(eng, fvErr, fvOut, fvOut, Gains{})
+			So(err, ShouldBeError, "controller: engine does not use input `u`")
+
+			_, err = NewPD(eng, fvErr, fvDErr, fvErr, Gains{})
+			So(err, ShouldBeError, "controller: engine does not produce output `e`")
+
+			_, err = NewPD(eng, fvErr, fvDErr, fvOut, Gains{}, WithSampleTime(-1))
+			So(err, ShouldBeError, "controller: negative sample time -1")
+
+			_, err = NewPD(eng, fvErr, fvDErr, fvOut, Gains{}, WithDerivativeFilter(-1))
+			So(err, ShouldBeError, "controller: negative derivative filter -1")
+
+			_, err = NewPD(eng, fvErr, fvDErr, fvOut, Gains{}, WithSaturation(1, -1))
+			So(err, ShouldBeError, "controller: saturation min 1 > max -1")
+		})
+	})
+}
@@ -0,0 +1,70 @@
+package controller
+
+import (
+	"fmt"
+
+	"github.com/sbiemont/fugologic/builder"
+	"github.com/sbiemont/fugologic/crisp"
+	"github.com/sbiemont/fugologic/fuzzy"
+	"github.com/sbiemont/fugologic/id"
+)
+
+// Terms returns the terms of the standard values, from negative big to positive big
+func Terms() []id.ID {
+	return []id.ID{"NB", "NM", "NS", "ZE", "PS", "PM", "PB"}
+}
+
+// NewValue creates a normalized fuzzy value on [-1, 1] using the standard terms (n is the number of values of the universe)
+// Terms are triangles evenly spread, the extreme ones are shoulders
+func NewValue(uuid id.ID, n int) (*fuzzy.IDVal, error) {
+	u, err := crisp.NewSetN(-1, 1, n)
+	if err != nil {
+		return nil, err
+	}
+
+	terms := Terms()
+	step := 2.0 / float64(len(terms)-1)
+	builders := make(map[id.ID]fuzzy.SetBuilder, len(terms))
+	for i, term := range terms {
+		c := -1 + float64(i)*step
+		switch i {
+		case 0:
+			builders[term] = fuzzy.StepDown{A: c, B: c + step}
+		case len(terms) - 1:
+			builders[term] = fuzzy.StepUp{A: c - step, B: c}
+		default:
+			builders[term] = fuzzy.Triangular{A: c - step, B: c, C: c + step}
+		}
+	}
+	return fuzzy.NewIDValBuildersOrdered(uuid, u, terms, builders)
+}
+
+// StandardRuleBase adds the standard 7x7 rule base into the matrix
+// The values shall define the standard terms (see Terms)
+// The output term is the sum of the error term and the derivative term (ZE being the origin), saturated at NB and PB:
+//
+//	| de \ e | NB | NM | NS | ZE | PS | PM | PB |
+//	|--------|----|----|----|----|----|----|----|
+//	| NB     | NB | NB | NB | NB | NM | NS | ZE |
+//	| ...    |    |    |    |    |    |    |    |
+//	| ZE     | NB | NM | NS | ZE | PS | PM | PB |
+//	| ...    |    |    |    |    |    |    |    |
+//	| PB     | ZE | PS | PM | PB | PB | PB | PB |
+func StandardRuleBase(mx *builder.FuzzyAssoMatrix, fvErr, fvDErr, fvOut *fuzzy.IDVal) error {
+	terms := Terms()
+	zero := len(terms) / 2
+	rows := make(map[id.ID][]id.ID, len(terms))
+	for j, dterm := range terms {
+		row := make([]id.ID, len(terms))
+		for i := range terms {
+			k := min(max(i+j-zero, 0), len(terms)-1)
+			row[i] = terms[k]
+		}
+		rows[dterm] = row
+	}
+
+	if err := mx.Asso(fvErr, fvDErr, fvOut).Matrix(terms, rows); err != nil {
+		return fmt.Errorf("controller: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/sbiemont/fugologic/builder"
+	"github.com/sbiemont/fugologic/fuzzy"
+	"github.com/sbiemont/fugologic/id"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// newStandard creates an engine using the standard values and the standard rule base
+func newStandard() (fuzzy.Engine, *fuzzy.IDVal, *fuzzy.IDVal, *fuzzy.IDVal) {
+	fvErr, err := NewValue("e", 101)
+	So(err, ShouldBeNil)
+	fvDErr, err := NewValue("de", 101)
+	So(err, ShouldBeNil)
+	fvOut, err := NewValue("u", 101)
+	So(err, ShouldBeNil)
+
+	mx := builder.Mamdani().FuzzyAssoMatrix()
+	So(StandardRuleBase(&mx, fvErr, fvDErr, fvOut), ShouldBeNil)
+	eng, err := mx.Engine()
+	So(err, ShouldBeNil)
+	return eng, fvErr, fvDErr, fvOut
+}
+
+func TestNewValue(t *testing.T) {
+	Convey("new value", t, func() {
+		Convey("when ok", func() {
+			fv, err := NewValue("e", 21)
+			So(err, ShouldBeNil)
+			So(fv.U().XMin(), ShouldEqual, -1)
+			So(fv.U().XMax(), ShouldEqual, 1)
+			So(fv.Terms(), ShouldHaveLength, 7)
+			for i, term := range Terms() {
+				// Each term is centered on its position
+				idSet, ok := fv.Fetch(term)
+				So(ok, ShouldBeTrue)
+				So(idSet.Set()(-1+float64(i)/3), ShouldAlmostEqual, 1)
+			}
+			So(fv.Get("NB").Set()(-2), ShouldEqual, 1)
+			So(fv.Get("PB").Set()(2), ShouldEqual, 1)
+			So(fv.Get("ZE").Set()(0.5), ShouldEqual, 0)
+		})
+
+		Convey("when ko", func() {
+			_, err := NewValue("e", 1)
+			So(err, ShouldBeError, "crisp set: n shall be >= 2")
+		})
+	})
+}
+
+func TestStandardRuleBase(t *testing.T) {
+	Convey("standard rule base", t, func() {
+		Convey("when ok", func() {
+			eng, fvErr, fvDErr, fvOut := newStandard()
+			So(eng.Rules(), ShouldHaveLength, 49)
+
+			evaluate := func(e, de float64) float64 {
+				out, err := eng.Evaluate(fuzzy.DataInput{fvErr: e, fvDErr: de})
+				So(err, ShouldBeNil)
+				return out[fvOut]
+			}
+			So(evaluate(0, 0), ShouldAlmostEqual, 0)
+			So(evaluate(1, 1), ShouldBeGreaterThan, 0.5)
+			So(evaluate(-1, -1), ShouldBeLessThan, -0.5)
+			So(evaluate(1, -1), ShouldAlmostEqual, 0)
+			So(evaluate(0.5, 0), ShouldBeGreaterThan, evaluate(0.2, 0))
+		})
+
+		Convey("when unknown term", func() {
+			fvErr, err := NewValue("e", 11)
+			So(err, ShouldBeNil)
+			fvOut, err := fuzzy.NewIDVal("u", fvErr.U(), map[id.ID]fuzzy.Set{"low": func(float64) float64 { return 0 }})
+			So(err, ShouldBeNil)
+
+			mx := builder.Mamdani().FuzzyAssoMatrix()
+			err = StandardRuleBase(&mx, fvErr, fvErr, fvOut)
+			So(err, ShouldBeError)
+			So(err.Error(), ShouldStartWith, "controller: 'then' statement, cannot find ")
+		})
+	})
+}
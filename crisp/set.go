@@ -43,6 +43,21 @@ func NewSetN(xmin, xmax float64, n int) (Set, error) {
 	}, nil
 }
 
+// XMin returns the lower bound of the set
+func (set Set) XMin() float64 {
+	return set.xmin
+}
+
+// XMax returns the upper bound of the set
+func (set Set) XMax() float64 {
+	return set.xmax
+}
+
+// Step returns the delta between two consecutive values
+func (set Set) Step() float64 {
+	return set.dx
+}
+
 // Values translates the interval into discrete increasing values
 func (set Set) Values() []float64 {
 	if set.dx == 0 {
@@ -59,7 +74,9 @@ func (set Set) Values() []float64 {
 	// }
 
 	// Prefer the solution of x = min + i*dx (a delta error is still present but more acceptable)
-	n := int(1 + (set.xmax-set.xmin)/set.dx)
+	// A small tolerance keeps the last value when the division is slightly under an integer
+	// Eg.: [0 ; 1] with 100 values
+	n := int(1 + (set.xmax-set.xmin)/set.dx + 1e-9)
 	result := make([]float64, n)
 	for i := 0; i < n; i++ {
 		result[i] = set.xmin + float64(i)*set.dx
@@ -53,6 +53,16 @@ func TestSet(t *testing.T) {
 
 			set, _ = NewSetN(0, 0.5, 4)
 			So(set.Values(), ShouldResemble, []float64{0, 0.16666666666666666, 0.3333333333333333, 0.5})
+
+			set, _ = NewSetN(0, 1, 100)
+			So(set.Values(), ShouldHaveLength, 100)
+		})
+
+		Convey("when bounds", func() {
+			set, _ := NewSetN(-1, 2, 5)
+			So(set.XMin(), ShouldEqual, -1)
+			So(set.XMax(), ShouldEqual, 2)
+			So(set.Step(), ShouldEqual, 0.75)
 		})
 	})
 
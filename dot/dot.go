@@ -0,0 +1,143 @@
+// Package dot writes the topology of systems and engines using the Graphviz DOT language
+package dot
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/sbiemont/fugologic/fuzzy"
+)
+
+// WriteSystem writes the dependency graph of a system
+// Engines are nodes (labelled with their identifier, description, inputs and outputs), shared values are edges
+func WriteSystem(w io.Writer, sys fuzzy.System) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "digraph system {")
+	fmt.Fprintln(bw, "  rankdir=LR;")
+	fmt.Fprintln(bw, "  node [shape=box];")
+
+	// Engines
+	for i, eng := range sys {
+		inputs, outputs := eng.IO()
+		name := fmt.Sprintf("engine #%d", i)
+		if eng.ID() != "" {
+			name = string(eng.ID())
+		}
+		if eng.Description() != "" {
+			name += "\n" + eng.Description()
+		}
+		label := fmt.Sprintf("%s\nin: %s\nout: %s", name, valueIDs(inputs), valueIDs(outputs))
+		fmt.Fprintf(bw, "  e%d [label=%s];\n", i, strconv.Quote(label))
+	}
+
+	// Shared values
+	for _, dep := range sys.Dependencies() {
+		ids := make([]string, len(dep.Values))
+		for i, idVal := range dep.Values {
+			ids[i] = string(idVal.ID())
+		}
+		fmt.Fprintf(bw, "  e%d -> e%d [label=%s];\n", dep.From, dep.To, strconv.Quote(strings.Join(ids, ", ")))
+	}
+
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+// WriteEngine writes the rules of an engine: input terms -> rules -> output terms
+// Terms are grouped by fuzzy value
+func WriteEngine(w io.Writer, eng fuzzy.Engine) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "digraph engine {")
+	fmt.Fprintln(bw, "  rankdir=LR;")
+
+	// Terms used by the rules, grouped by value
+	inputs, outputs := eng.IO()
+	groups := make(map[*fuzzy.IDVal]map[string]struct{})
+	for _, idSet := range append(inputs, outputs...) {
+		idVal := idSet.Parent()
+		if groups[idVal] == nil {
+			groups[idVal] = make(map[string]struct{})
+		}
+		groups[idVal][string(idSet.ID())] = struct{}{}
+	}
+	idVals := make([]*fuzzy.IDVal, 0, len(groups))
+	for idVal := range groups {
+		idVals = append(idVals, idVal)
+	}
+	sort.Slice(idVals, func(i, j int) bool {
+		return idVals[i].ID() < idVals[j].ID()
+	})
+	for i, idVal := range idVals {
+		fmt.Fprintf(bw, "  subgraph cluster_%d {\n", i)
+		fmt.Fprintf(bw, "    label=%s;\n", strconv.Quote(string(idVal.ID())))
+		for _, term := range sortedKeys(groups[idVal]) {
+			fmt.Fprintf(bw, "    %s [label=%s];\n", termNode(idVal, term), strconv.Quote(term))
+		}
+		fmt.Fprintln(bw, "  }")
+	}
+
+	// Rules
+	for i, rule := range eng.Rules() {
+		fmt.Fprintf(bw, "  r%d [shape=box, label=%s];\n", i, strconv.Quote(rule.String()))
+		in, out := rule.IO()
+		for _, idSet := range unique(in) {
+			fmt.Fprintf(bw, "  %s -> r%d;\n", termNode(idSet.Parent(), string(idSet.ID())), i)
+		}
+		for _, idSet := range unique(out) {
+			fmt.Fprintf(bw, "  r%d -> %s;\n", i, termNode(idSet.Parent(), string(idSet.ID())))
+		}
+	}
+
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+// termNode returns the quoted identifier of the node of a term
+func termNode(idVal *fuzzy.IDVal, term string) string {
+	var value string
+	if idVal != nil {
+		value = string(idVal.ID())
+	}
+	return strconv.Quote(value + "/" + term)
+}
+
+// valueIDs joins the sorted ids of the values of the sets
+func valueIDs(idSets []fuzzy.IDSet) string {
+	ids := make(map[string]struct{})
+	for idVal := range fuzzy.IDSets(idSets).IDVals() {
+		ids[string(idVal.ID())] = struct{}{}
+	}
+	return strings.Join(sortedKeys(ids), ", ")
+}
+
+// unique removes the duplicated sets (same value and same id), keeping the first ones
+func unique(idSets []fuzzy.IDSet) []fuzzy.IDSet {
+	type key struct {
+		idVal *fuzzy.IDVal
+		id    string
+	}
+	found := make(map[key]struct{})
+	var result []fuzzy.IDSet
+	for _, idSet := range idSets {
+		k := key{idVal: idSet.Parent(), id: string(idSet.ID())}
+		if _, exists := found[k]; !exists {
+			found[k] = struct{}{}
+			result = append(result, idSet)
+		}
+	}
+	return result
+}
+
+// sortedKeys returns the sorted keys of a set
+func sortedKeys(set map[string]struct{}) []string {
+	result := make([]string, 0, len(set))
+	for key := range set {
+		result = append(result, key)
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,103 @@
+package dot
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sbiemont/fugologic/fuzzy"
+	"github.com/sbiemont/fugologic/model"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const testModel = `
+version: 1
+values:
+  - id: a
+    universe: {xmin: 0, xmax: 1, dx: 0.5}
+    sets:
+      - {id: a1, type: step-down, params: [0, 1]}
+      - {id: a2, type: step-up, params: [0, 1]}
+  - id: b
+    universe: {xmin: 0, xmax: 1, dx: 0.5}
+    sets:
+      - {id: b1, type: step-down, params: [0, 1]}
+      - {id: b2, type: step-up, params: [0, 1]}
+  - id: c
+    universe: {xmin: 0, xmax: 1, dx: 0.5}
+    sets:
+      - {id: c1, type: step-down, params: [0, 1]}
+      - {id: c2, type: step-up, params: [0, 1]}
+  - id: d
+    universe: {xmin: 0, xmax: 1, dx: 0.5}
+    sets:
+      - {id: d1, type: step-down, params: [0, 1]}
+engines:
+  - rules:
+      - if: {value: a, set: a1}
+        then: [{value: b, set: b1}, {value: c, set: c1}]
+      - if: {not: {value: a, set: a1}}
+        then: [{value: b, set: b2}]
+  - id: second
+    description: b, c => d
+    rules:
+      - if: {and: [{value: b, set: b1}, {value: c, set: c1}]}
+        then: [{value: d, set: d1}]
+`
+
+func TestWrite(t *testing.T) {
+	Convey("write", t, func() {
+		m, err := model.ReadYAML(strings.NewReader(testModel))
+		So(err, ShouldBeNil)
+		sys, _, err := m.Build()
+		So(err, ShouldBeNil)
+
+		Convey("when system", func() {
+			var sb strings.Builder
+			So(WriteSystem(&sb, sys), ShouldBeNil)
+			So(sb.String(), ShouldEqual, `digraph system {
+  rankdir=LR;
+  node [shape=box];
+  e0 [label="engine #0\nin: a\nout: b, c"];
+  e1 [label="second\nb, c => d\nin: b, c\nout: d"];
+  e0 -> e1 [label="b, c"];
+}
+`)
+		})
+
+		Convey("when empty system", func() {
+			var sb strings.Builder
+			So(WriteSystem(&sb, fuzzy.System{}), ShouldBeNil)
+			So(sb.String(), ShouldEqual, "digraph system {\n  rankdir=LR;\n  node [shape=box];\n}\n")
+		})
+
+		Convey("when engine", func() {
+			var sb strings.Builder
+			So(WriteEngine(&sb, sys[0]), ShouldBeNil)
+			So(sb.String(), ShouldEqual, `digraph engine {
+  rankdir=LR;
+  subgraph cluster_0 {
+    label="a";
+    "a/a1" [label="a1"];
+  }
+  subgraph cluster_1 {
+    label="b";
+    "b/b1" [label="b1"];
+    "b/b2" [label="b2"];
+  }
+  subgraph cluster_2 {
+    label="c";
+    "c/c1" [label="c1"];
+  }
+  r0 [shape=box, label="IF a1 THEN b1, c1"];
+  "a/a1" -> r0;
+  r0 -> "b/b1";
+  r0 -> "c/c1";
+  r1 [shape=box, label="IF NOT a1 THEN b2"];
+  "a/a1" -> r1;
+  r1 -> "b/b2";
+}
+`)
+		})
+	})
+}
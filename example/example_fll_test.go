@@ -0,0 +1,152 @@
+package example
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/sbiemont/fugologic/builder"
+	"github.com/sbiemont/fugologic/crisp"
+	"github.com/sbiemont/fugologic/fll"
+	"github.com/sbiemont/fugologic/fuzzy"
+	"github.com/sbiemont/fugologic/id"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// roundTripFLL exports the engine using FLL, imports it, and checks that both engines give the same results
+func roundTripFLL(name string, eng fuzzy.Engine, inputs [][]float64) {
+	// Export, import
+	var buf bytes.Buffer
+	model := fll.NewModel(name, eng)
+	So(fll.Write(&buf, model), ShouldBeNil)
+	imported, err := fll.Read(bytes.NewReader(buf.Bytes()))
+	So(err, ShouldBeNil)
+	So(imported.Name, ShouldEqual, name)
+	So(imported.Inputs, ShouldHaveLength, len(model.Inputs))
+	So(imported.Outputs, ShouldHaveLength, len(model.Outputs))
+
+	// Export again: same description expected
+	var buf2 bytes.Buffer
+	So(fll.Write(&buf2, imported), ShouldBeNil)
+	So(buf2.String(), ShouldEqual, buf.String())
+
+	// Evaluate both engines
+	for _, values := range inputs {
+		input := fuzzy.DataInput{}
+		importedInput := fuzzy.DataInput{}
+		for i, value := range values {
+			input[model.Inputs[i]] = value
+			importedInput[imported.Inputs[i]] = value
+		}
+
+		out, err := eng.Evaluate(input)
+		So(err, ShouldBeNil)
+		importedOut, err := imported.Engine.Evaluate(importedInput)
+		So(err, ShouldBeNil)
+		for i, output := range model.Outputs {
+			So(importedOut[imported.Outputs[i]], ShouldAlmostEqual, out[output], 1e-9)
+		}
+	}
+}
+
+func TestFLL(t *testing.T) {
+	Convey("fll round trip", t, func() {
+		Convey("when tipper", func() {
+			k := 100
+			crispSvc, _ := crisp.NewSetN(0, 10, k)
+			fvSvc, _ := fuzzy.NewIDValBuilders("service", crispSvc, map[id.ID]fuzzy.SetBuilder{
+				"poor":      fuzzy.Gauss{Sigma: 1.5, C: 0},
+				"good":      fuzzy.Gauss{Sigma: 1.5, C: 5},
+				"excellent": fuzzy.Gauss{Sigma: 1.5, C: 10},
+			})
+			crispFood, _ := crisp.NewSetN(0, 10, k)
+			fvFood, _ := fuzzy.NewIDValBuilders("food", crispFood, map[id.ID]fuzzy.SetBuilder{
+				"rancid":    fuzzy.Trapezoid{A: -2, B: 0, C: 1, D: 3},
+				"delicious": fuzzy.Trapezoid{A: 7, B: 9, C: 10, D: 12},
+			})
+			crispTip, _ := crisp.NewSetN(0, 30, 3*k)
+			fvTip, _ := fuzzy.NewIDValBuilders("tip", crispTip, map[id.ID]fuzzy.SetBuilder{
+				"cheap":    fuzzy.Triangular{A: 0, B: 5, C: 10},
+				"average":  fuzzy.Triangular{A: 10, B: 15, C: 20},
+				"generous": fuzzy.Triangular{A: 20, B: 25, C: 30},
+			})
+
+			bld := builder.Mamdani().FuzzyLogic()
+			bld.If(fvSvc.Get("poor")).Or(fvFood.Get("rancid")).Then(fvTip.Get("cheap"))
+			bld.If(fvSvc.Get("good")).Then(fvTip.Get("average"))
+			bld.If(fvSvc.Get("excellent")).Or(fvFood.Get("delicious")).Then(fvTip.Get("generous"))
+			eng, err := bld.Engine()
+			So(err, ShouldBeNil)
+
+			roundTripFLL("tipper", eng, [][]float64{{1, 2}, {3, 5}, {2, 7}, {3, 1}})
+		})
+
+		Convey("when fam", func() {
+			crispDiff, _ := crisp.NewSetN(-2, 2, 40)
+			fvDiff, _ := fuzzy.NewIDValBuilders("diff_consigne", crispDiff, map[id.ID]fuzzy.SetBuilder{
+				"NB": fuzzy.StepDown{A: -2, B: -0.5},
+				"NS": fuzzy.Triangular{A: -2, B: -0.5, C: 0},
+				"ZE": fuzzy.Triangular{A: -0.5, B: 0, C: 0.5},
+				"PS": fuzzy.Triangular{A: 0, B: 0.5, C: 2},
+				"PB": fuzzy.StepUp{A: 0.5, B: 2},
+			})
+			crispDt, _ := crisp.NewSetN(-0.2, 0.2, 40)
+			fvDt, _ := fuzzy.NewIDValBuilders("temp_dt", crispDt, map[id.ID]fuzzy.SetBuilder{
+				"NB": fuzzy.StepDown{A: -0.2, B: -0.1},
+				"NS": fuzzy.Triangular{A: -0.2, B: -0.1, C: 0},
+				"ZE": fuzzy.Triangular{A: -0.1, B: 0, C: 0.1},
+				"PS": fuzzy.Triangular{A: 0, B: 0.1, C: 0.2},
+				"PB": fuzzy.StepUp{A: 0.1, B: 0.2},
+			})
+			crispForce, _ := crisp.NewSetN(-4, 4, 80)
+			fvForce, _ := fuzzy.NewIDValBuilders("force", crispForce, map[id.ID]fuzzy.SetBuilder{
+				"NB": fuzzy.StepDown{A: -4, B: -1},
+				"NS": fuzzy.Triangular{A: -2, B: -1, C: 0},
+				"ZE": fuzzy.Triangular{A: -1, B: 0, C: 1},
+				"PS": fuzzy.Triangular{A: 0, B: 1, C: 2},
+				"PB": fuzzy.StepUp{A: 1, B: 4},
+			})
+
+			bld := builder.Mamdani().FuzzyAssoMatrix()
+			err := bld.Asso(fvDiff, fvDt, fvForce).
+				Matrix(
+					[]id.ID{"NB", "NS", "ZE", "PS", "PB"},
+					map[id.ID][]id.ID{
+						"NB": {"PB", "PB", "PS", "ZE", "NS"},
+						"NS": {"PB", "PB", "PS", "ZE", "NS"},
+						"ZE": {"PB", "PS", "ZE", "NS", "NB"},
+						"PS": {"PS", "ZE", "NS", "NB", "NB"},
+						"PB": {"PS", "ZE", "NS", "NB", "NB"},
+					})
+			So(err, ShouldBeNil)
+			eng, err := bld.Engine()
+			So(err, ShouldBeNil)
+
+			var inputs [][]float64
+			for _, diff := range []float64{-2, -0.7, 0, 0.05, 1.3} {
+				for _, dt := range []float64{-0.2, -0.05, 0, 0.3} {
+					inputs = append(inputs, []float64{diff, dt})
+				}
+			}
+			roundTripFLL("fam", eng, inputs)
+		})
+
+		Convey("when names cannot be exported", func() {
+			crispHP, _ := crisp.NewSetN(0, 100, 1000)
+			fvHP, _ := fuzzy.NewIDValBuilders("HP", crispHP, map[id.ID]fuzzy.SetBuilder{
+				"Very low HP": fuzzy.StepDown{A: 0, B: 20},
+			})
+			crispAct, _ := crisp.NewSetN(-10, 10, 100)
+			fvAct, _ := fuzzy.NewIDValBuilders("Act", crispAct, map[id.ID]fuzzy.SetBuilder{
+				"Retreat!": fuzzy.StepDown{A: -10, B: -5},
+			})
+
+			bld := builder.Mamdani().FuzzyLogic()
+			bld.If(fvHP.Get("Very low HP")).Then(fvAct.Get("Retreat!"))
+			eng, err := bld.Engine()
+			So(err, ShouldBeNil)
+
+			So(fll.Write(&bytes.Buffer{}, fll.NewModel("hp", eng)), ShouldBeError, "fll: name `Very low HP` cannot be written")
+		})
+	})
+}
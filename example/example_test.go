@@ -7,6 +7,7 @@ import (
 	"github.com/sbiemont/fugologic/crisp"
 	"github.com/sbiemont/fugologic/fuzzy"
 	"github.com/sbiemont/fugologic/id"
+	"github.com/sbiemont/fugologic/sweep"
 
 	. "github.com/smartystreets/goconvey/convey"
 )
@@ -239,21 +240,12 @@ func TestExample(t *testing.T) {
 				engine, errEngine := bld.Engine()
 				So(errEngine, ShouldBeNil)
 
-				var result [][]float64
-				for _, diff := range fvDiff.U().Values() {
-					for _, dt := range fvDt.U().Values() {
-						data, errEval := engine.Evaluate(fuzzy.DataInput{
-							fvDiff: diff,
-							fvDt:   dt,
-						})
-						So(errEval, ShouldBeNil)
-
-						force := data[fvForce]
-						result = append(result, []float64{diff, dt, force})
-					}
-				}
-
-				So(writeCSV("./example_test.csv", []string{"diff", "dt", "force"}, result), ShouldBeNil)
+				surface, errSweep := sweep.Sweep(engine, nil, fvDiff, fvDt)
+				So(errSweep, ShouldBeNil)
+				So(surface.Columns, ShouldResemble, []string{"diff/consigne", "temp/dt", "force"})
+				So(surface.Rows, ShouldHaveLength, len(fvDiff.U().Values())*len(fvDt.U().Values()))
+
+				So(writeCSV("./example_test.csv", surface.Columns, surface.Rows), ShouldBeNil)
 			})
 		})
 	})
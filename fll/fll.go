@@ -0,0 +1,127 @@
+// Package fll imports and exports engines using the FuzzyLite Language (FLL)
+// https://fuzzylite.com/fll-fld/
+//
+// Supported features are:
+//   - InputVariable / OutputVariable: range and terms (Triangle, Trapezoid, Gaussian, Bell, Sigmoid, Ramp)
+//   - OutputVariable: aggregation and defuzzifier (shared by all outputs)
+//   - RuleBlock: conjunction, disjunction, implication and rules using "and", "or", "not", parentheses,
+//     hedges "very", "somewhat" and weights
+//
+// Rules are read using the rule parser of the builder, without quotes (see. builder.Config.ParseRuleWith)
+package fll
+
+import (
+	"github.com/sbiemont/fugologic/fuzzy"
+)
+
+// DefaultResolution is the number of crisp values of a variable without resolution
+// FLL input variables only define a range
+const DefaultResolution = 100
+
+// Model is the content of a FLL description
+type Model struct {
+	Name        string         // name of the engine (optional)
+	Description string         // description of the engine (optional)
+	Inputs      []*fuzzy.IDVal // input variables
+	Outputs     []*fuzzy.IDVal // output variables
+	Engine      fuzzy.Engine
+}
+
+// NewModel creates a model from an engine
+// Inputs and outputs are extracted from the rules, in order of appearance
+// The description is the one of the engine
+func NewModel(name string, eng fuzzy.Engine) Model {
+	unique := func(idSets []fuzzy.IDSet) []*fuzzy.IDVal {
+		var result []*fuzzy.IDVal
+		found := make(map[*fuzzy.IDVal]struct{})
+		for _, idSet := range idSets {
+			if _, exists := found[idSet.Parent()]; !exists {
+				found[idSet.Parent()] = struct{}{}
+				result = append(result, idSet.Parent())
+			}
+		}
+		return result
+	}
+
+	inputs, outputs := eng.IO()
+	return Model{
+		Name:        name,
+		Description: eng.Description(),
+		Inputs:      unique(inputs),
+		Outputs:     unique(outputs),
+		Engine:      eng,
+	}
+}
+
+// FLL keywords
+const (
+	kwIf   = "if"
+	kwIs   = "is"
+	kwThen = "then"
+	kwAnd  = "and"
+	kwOr   = "or"
+	kwNot  = "not"
+	kwWith = "with"
+)
+
+// keywords cannot be used as names
+var keywords = map[string]struct{}{
+	kwIf: {}, kwIs: {}, kwThen: {}, kwAnd: {}, kwOr: {}, kwNot: {}, kwWith: {},
+}
+
+// hedges are FLL hedges (see. supportedHedges)
+var hedges = map[string]struct{}{
+	"very": {}, "somewhat": {}, "seldom": {}, "extremely": {}, "any": {},
+}
+
+// supportedHedges are FLL hedges matching a predefined hedge (see. fuzzy.HedgeByName)
+var supportedHedges = map[string]bool{
+	"very": true, "somewhat": true,
+}
+
+// FLL terms
+const (
+	termTriangle  = "Triangle"
+	termTrapezoid = "Trapezoid"
+	termGaussian  = "Gaussian"
+	termBell      = "Bell"
+	termSigmoid   = "Sigmoid"
+	termRamp      = "Ramp"
+)
+
+// norms links the predefined operators to the FLL conjunction (t-norm) and disjunction (s-norm)
+var norms = map[string][2]string{
+	"zadeh":      {"Minimum", "Maximum"},
+	"hyperbolic": {"AlgebraicProduct", "AlgebraicSum"},
+}
+
+// implications links the predefined implications to the FLL implications
+var implications = map[string]string{
+	"min":  "Minimum",
+	"prod": "AlgebraicProduct",
+}
+
+// aggregations links the predefined aggregations to the FLL aggregations
+var aggregations = map[string]string{
+	"union":        "Maximum",
+	"intersection": "Minimum",
+}
+
+// defuzzifiers links the predefined defuzzification methods to the FLL defuzzifiers
+var defuzzifiers = map[string]string{
+	"centroid":        "Centroid",
+	"bisector":        "Bisector",
+	"smallest-of-max": "SmallestOfMaximum",
+	"middle-of-max":   "MeanOfMaximum",
+	"largest-of-max":  "LargestOfMaximum",
+}
+
+// reverse finds the predefined name linked to a FLL name
+func reverse(mapping map[string]string, fllName string) (string, bool) {
+	for name, value := range mapping {
+		if value == fllName {
+			return name, true
+		}
+	}
+	return "", false
+}
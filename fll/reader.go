@@ -0,0 +1,513 @@
+package fll
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/sbiemont/fugologic/builder"
+	"github.com/sbiemont/fugologic/crisp"
+	"github.com/sbiemont/fugologic/fuzzy"
+	"github.com/sbiemont/fugologic/id"
+)
+
+// term is the raw definition of a FLL term
+type term struct {
+	name   string
+	shape  string
+	params []float64
+}
+
+// variable is the raw definition of a FLL input or output variable
+type variable struct {
+	line        int
+	name        string
+	xmin, xmax  float64
+	hasRange    bool
+	terms       []term
+	aggregation string
+	defuzzifier string
+	resolution  int
+}
+
+// ruleBlock is the raw definition of a FLL rule block
+type ruleBlock struct {
+	line        int
+	conjunction string
+	disjunction string
+	implication string
+	rules       []rawRule
+}
+
+// rawRule is the raw text of a FLL rule
+type rawRule struct {
+	line int
+	text string
+}
+
+// document gathers all raw definitions of a FLL description
+type document struct {
+	name        string
+	description string
+	inputs      []*variable
+	outputs     []*variable
+	blocks      []*ruleBlock
+}
+
+// Read parses a FLL description and builds the engine
+func Read(r io.Reader) (Model, error) {
+	doc, err := parse(r)
+	if err != nil {
+		return Model{}, err
+	}
+	return doc.build()
+}
+
+// section is the kind of the current FLL section
+type section int
+
+const (
+	sectionEngine section = iota
+	sectionInput
+	sectionOutput
+	sectionRuleBlock
+)
+
+// parse reads all lines and collects the raw definitions
+func parse(r io.Reader) (document, error) {
+	var doc document
+	current := sectionEngine
+	var currentVar *variable
+	var currentBlock *ruleBlock
+
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := scanner.Text()
+		if i := strings.Index(text, "#"); i >= 0 {
+			text = text[:i]
+		}
+		text = strings.TrimSpace(text)
+		if text == "" {
+			continue
+		}
+
+		key, value, found := strings.Cut(text, ":")
+		if !found {
+			return document{}, fmt.Errorf("fll: line %d: `key: value` expected", line)
+		}
+		value = strings.TrimSpace(value)
+
+		// New sections
+		switch key {
+		case "Engine":
+			doc.name = value
+			current = sectionEngine
+			continue
+		case "InputVariable", "OutputVariable":
+			currentVar = &variable{line: line, name: value}
+			if key == "InputVariable" {
+				current = sectionInput
+				doc.inputs = append(doc.inputs, currentVar)
+			} else {
+				current = sectionOutput
+				doc.outputs = append(doc.outputs, currentVar)
+			}
+			continue
+		case "RuleBlock":
+			currentBlock = &ruleBlock{line: line}
+			current = sectionRuleBlock
+			doc.blocks = append(doc.blocks, currentBlock)
+			continue
+		}
+
+		// Section properties
+		var err error
+		switch current {
+		case sectionEngine:
+			err = doc.parseEngine(key, value)
+		case sectionInput, sectionOutput:
+			err = currentVar.parse(key, value, current == sectionOutput)
+		case sectionRuleBlock:
+			err = currentBlock.parse(line, key, value)
+		}
+		if err != nil {
+			return document{}, fmt.Errorf("fll: line %d: %w", line, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return document{}, fmt.Errorf("fll: %w", err)
+	}
+	return doc, nil
+}
+
+// parseEngine reads an engine property
+func (doc *document) parseEngine(key, value string) error {
+	switch key {
+	case "description":
+		doc.description = value
+		return nil
+	default:
+		return fmt.Errorf("unknown engine property `%s`", key)
+	}
+}
+
+// parse reads a variable property
+func (v *variable) parse(key, value string, isOutput bool) error {
+	switch key {
+	case "description", "lock-range":
+		return nil
+	case "enabled":
+		return checkEnabled(value)
+	case "range":
+		params, err := parseFloats(value)
+		if err != nil {
+			return err
+		}
+		if len(params) != 2 {
+			return fmt.Errorf("range: 2 values expected")
+		}
+		v.xmin, v.xmax, v.hasRange = params[0], params[1], true
+		return nil
+	case "term":
+		fields := strings.Fields(value)
+		if len(fields) < 2 {
+			return fmt.Errorf("term: name and shape expected")
+		}
+		params, err := parseFloats(strings.Join(fields[2:], " "))
+		if err != nil {
+			return err
+		}
+		v.terms = append(v.terms, term{name: fields[0], shape: fields[1], params: params})
+		return nil
+	}
+
+	if !isOutput {
+		return fmt.Errorf("unknown input variable property `%s`", key)
+	}
+
+	switch key {
+	case "default", "lock-previous":
+		return nil
+	case "aggregation":
+		v.aggregation = value
+		return nil
+	case "defuzzifier":
+		fields := strings.Fields(value)
+		if len(fields) == 0 || len(fields) > 2 {
+			return fmt.Errorf("defuzzifier: name and optional resolution expected")
+		}
+		v.defuzzifier = fields[0]
+		if len(fields) == 2 {
+			resolution, err := strconv.Atoi(fields[1])
+			if err != nil {
+				return fmt.Errorf("defuzzifier: wrong resolution `%s`", fields[1])
+			}
+			v.resolution = resolution
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown output variable property `%s`", key)
+	}
+}
+
+// parse reads a rule block property
+func (rb *ruleBlock) parse(line int, key, value string) error {
+	switch key {
+	case "description":
+		return nil
+	case "enabled":
+		return checkEnabled(value)
+	case "conjunction":
+		rb.conjunction = value
+	case "disjunction":
+		rb.disjunction = value
+	case "implication":
+		rb.implication = value
+	case "activation":
+		if value != "" && value != "General" {
+			return fmt.Errorf("activation `%s` not supported", value)
+		}
+	case "rule":
+		rb.rules = append(rb.rules, rawRule{line: line, text: value})
+	default:
+		return fmt.Errorf("unknown rule block property `%s`", key)
+	}
+	return nil
+}
+
+// checkEnabled only accepts enabled items
+func checkEnabled(value string) error {
+	if value != "true" {
+		return fmt.Errorf("disabled items not supported")
+	}
+	return nil
+}
+
+// parseFloats converts a list of blank separated values
+func parseFloats(value string) ([]float64, error) {
+	fields := strings.Fields(value)
+	result := make([]float64, len(fields))
+	for i, field := range fields {
+		flt, err := strconv.ParseFloat(field, 64)
+		if err != nil {
+			return nil, fmt.Errorf("wrong value `%s`", field)
+		}
+		result[i] = flt
+	}
+	return result, nil
+}
+
+// build converts the raw definitions into a model
+func (doc document) build() (Model, error) {
+	// Variables
+	vars := make(map[id.ID]*fuzzy.IDVal)
+	build := func(raws []*variable) ([]*fuzzy.IDVal, error) {
+		result := make([]*fuzzy.IDVal, len(raws))
+		for i, raw := range raws {
+			idVal, err := raw.build()
+			if err != nil {
+				return nil, fmt.Errorf("fll: line %d: %w", raw.line, err)
+			}
+			if _, exists := vars[idVal.ID()]; exists {
+				return nil, fmt.Errorf("fll: line %d: variable `%s` already defined", raw.line, raw.name)
+			}
+			vars[idVal.ID()] = idVal
+			result[i] = idVal
+		}
+		return result, nil
+	}
+
+	inputs, err := build(doc.inputs)
+	if err != nil {
+		return Model{}, err
+	}
+	outputs, err := build(doc.outputs)
+	if err != nil {
+		return Model{}, err
+	}
+
+	// Engine configuration (shared by all outputs)
+	agg, defuzz, err := doc.config()
+	if err != nil {
+		return Model{}, err
+	}
+
+	// Rules
+	var rules []fuzzy.Rule
+	for _, block := range doc.blocks {
+		blockRules, err := block.build(vars)
+		if err != nil {
+			return Model{}, err
+		}
+		rules = append(rules, blockRules...)
+	}
+
+	eng, err := fuzzy.NewEngine(rules, agg, defuzz, fuzzy.WithID(id.ID(doc.name)), fuzzy.WithDescription(doc.description))
+	if err != nil {
+		return Model{}, fmt.Errorf("fll: %w", err)
+	}
+
+	return Model{
+		Name:        doc.name,
+		Description: doc.description,
+		Inputs:      inputs,
+		Outputs:     outputs,
+		Engine:      eng,
+	}, nil
+}
+
+// config extracts the aggregation and the defuzzification shared by all outputs
+func (doc document) config() (fuzzy.Aggregation, fuzzy.Defuzzification, error) {
+	var aggName, defuzzName string
+	for i, output := range doc.outputs {
+		if i > 0 && (output.aggregation != aggName || output.defuzzifier != defuzzName) {
+			return nil, nil, fmt.Errorf("fll: line %d: all outputs shall share the same aggregation and defuzzifier", output.line)
+		}
+		aggName, defuzzName = output.aggregation, output.defuzzifier
+	}
+	if len(doc.outputs) == 0 {
+		return nil, nil, fmt.Errorf("fll: at least 1 output variable expected")
+	}
+
+	line := doc.outputs[0].line
+	name, ok := reverse(aggregations, aggName)
+	if !ok {
+		return nil, nil, fmt.Errorf("fll: line %d: aggregation `%s` not supported", line, aggName)
+	}
+	agg, _ := fuzzy.AggregationByName(name)
+
+	name, ok = reverse(defuzzifiers, defuzzName)
+	if !ok {
+		return nil, nil, fmt.Errorf("fll: line %d: defuzzifier `%s` not supported", line, defuzzName)
+	}
+	defuzz, _ := fuzzy.DefuzzificationByName(name)
+	return agg, defuzz, nil
+}
+
+// build converts a raw variable into a fuzzy value
+func (v variable) build() (*fuzzy.IDVal, error) {
+	if !v.hasRange {
+		return nil, fmt.Errorf("variable `%s`: range expected", v.name)
+	}
+
+	resolution := v.resolution
+	if resolution == 0 {
+		resolution = DefaultResolution
+	}
+	u, err := crisp.NewSetN(v.xmin, v.xmax, resolution)
+	if err != nil {
+		return nil, fmt.Errorf("variable `%s`: %w", v.name, err)
+	}
+
+	builders := make(map[id.ID]fuzzy.SetBuilder, len(v.terms))
+	names := make([]id.ID, 0, len(v.terms))
+	for _, t := range v.terms {
+		if _, exists := builders[id.ID(t.name)]; exists {
+			return nil, fmt.Errorf("variable `%s`: term `%s` already defined", v.name, t.name)
+		}
+		builder, err := t.builder()
+		if err != nil {
+			return nil, fmt.Errorf("variable `%s`: term `%s`: %w", v.name, t.name, err)
+		}
+		builders[id.ID(t.name)] = builder
+		names = append(names, id.ID(t.name))
+	}
+	return fuzzy.NewIDValBuildersOrdered(id.ID(v.name), u, names, builders)
+}
+
+// builder converts a raw term into a set builder
+func (t term) builder() (fuzzy.SetBuilder, error) {
+	expected := map[string]int{
+		termTriangle:  3,
+		termTrapezoid: 4,
+		termGaussian:  2,
+		termBell:      3,
+		termSigmoid:   2,
+		termRamp:      2,
+	}
+	n, ok := expected[t.shape]
+	if !ok {
+		return nil, fmt.Errorf("shape `%s` not supported", t.shape)
+	}
+	if len(t.params) != n {
+		return nil, fmt.Errorf("%d parameters expected (found: %d)", n, len(t.params))
+	}
+
+	p := t.params
+	switch t.shape {
+	case termTriangle:
+		return fuzzy.Triangular{A: p[0], B: p[1], C: p[2]}, nil
+	case termTrapezoid:
+		return fuzzy.Trapezoid{A: p[0], B: p[1], C: p[2], D: p[3]}, nil
+	case termGaussian:
+		return fuzzy.Gauss{Sigma: p[1], C: p[0]}, nil
+	case termBell:
+		return fuzzy.Gbell{A: p[1], B: p[2], C: p[0]}, nil
+	case termSigmoid:
+		return fuzzy.Sigmoid{A: p[1], C: p[0]}, nil
+	default: // termRamp
+		switch {
+		case p[0] < p[1]:
+			return fuzzy.StepUp{A: p[0], B: p[1]}, nil
+		case p[0] > p[1]:
+			return fuzzy.StepDown{A: p[1], B: p[0]}, nil
+		default:
+			return nil, fmt.Errorf("start and end shall be different")
+		}
+	}
+}
+
+// build converts all rules of the block
+func (rb ruleBlock) build(vars map[id.ID]*fuzzy.IDVal) ([]fuzzy.Rule, error) {
+	optr, err := rb.operator()
+	if err != nil {
+		return nil, err
+	}
+
+	// Implication
+	implName, ok := reverse(implications, rb.implication)
+	if !ok {
+		return nil, fmt.Errorf("fll: line %d: implication `%s` not supported", rb.line, rb.implication)
+	}
+	impl, _ := fuzzy.ImplicationByName(implName)
+
+	// Rules: plain identifiers, connectives restricted to the defined norms
+	opts := []builder.ParseOption{builder.WithoutQuotes()}
+	var connectives []fuzzy.Connective
+	if !isNone(rb.conjunction) {
+		connectives = append(connectives, fuzzy.ConnectiveAnd)
+	}
+	if !isNone(rb.disjunction) {
+		connectives = append(connectives, fuzzy.ConnectiveOr)
+	}
+	opts = append(opts, builder.WithConnectives(connectives...))
+
+	values := make([]*fuzzy.IDVal, 0, len(vars))
+	for _, idVal := range vars {
+		values = append(values, idVal)
+	}
+	cfg := builder.Config{Optr: optr, Impl: impl}
+	result := make([]fuzzy.Rule, len(rb.rules))
+	for i, raw := range rb.rules {
+		if err := checkHedges(raw.text); err != nil {
+			return nil, fmt.Errorf("fll: line %d: %w", raw.line, err)
+		}
+		rule, err := cfg.ParseRuleWith(raw.text, values, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("fll: line %d: %w", raw.line, err)
+		}
+		result[i] = rule
+	}
+	return result, nil
+}
+
+// operator finds the predefined operator matching both conjunction and disjunction
+// Returns nil if no norm is defined
+func (rb ruleBlock) operator() (fuzzy.Operator, error) {
+	var optrName string
+	for i, fllName := range []string{rb.conjunction, rb.disjunction} {
+		if isNone(fllName) {
+			continue
+		}
+
+		name := ""
+		for n, fllNames := range norms {
+			if fllNames[i] == fllName {
+				name = n
+			}
+		}
+		if name == "" {
+			return nil, fmt.Errorf("fll: line %d: norm `%s` not supported", rb.line, fllName)
+		}
+		if optrName != "" && optrName != name {
+			return nil, fmt.Errorf("fll: line %d: conjunction `%s` and disjunction `%s` not supported together", rb.line, rb.conjunction, rb.disjunction)
+		}
+		optrName = name
+	}
+
+	if optrName == "" {
+		return nil, nil
+	}
+	optr, _ := fuzzy.OperatorByName(optrName)
+	return optr, nil
+}
+
+// isNone checks if a norm is undefined
+func isNone(fllName string) bool {
+	return fllName == "" || fllName == "none"
+}
+
+// checkHedges controls that the FLL hedges of a rule match a predefined hedge (see. supportedHedges)
+func checkHedges(text string) error {
+	text = strings.NewReplacer("(", " ", ")", " ").Replace(text)
+	for _, word := range strings.Fields(text) {
+		if _, isHedge := hedges[word]; isHedge && !supportedHedges[word] {
+			return fmt.Errorf("hedge `%s` not supported", word)
+		}
+	}
+	return nil
+}
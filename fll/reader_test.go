@@ -0,0 +1,189 @@
+package fll
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sbiemont/fugologic/fuzzy"
+	"github.com/sbiemont/fugologic/id"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const fllTipper = `
+# https://fr.mathworks.com/help/fuzzy/working-from-the-command-line.html
+Engine: tipper
+description: tipper example
+InputVariable: service
+  enabled: true
+  range: 0 10
+  lock-range: false
+  term: poor Gaussian 0 1.5
+  term: good Gaussian 5 1.5
+  term: excellent Gaussian 10 1.5
+InputVariable: food
+  enabled: true
+  range: 0 10
+  term: rancid Trapezoid -2 0 1 3
+  term: delicious Trapezoid 7 9 10 12
+OutputVariable: tip
+  enabled: true
+  range: 0 30
+  aggregation: Maximum
+  defuzzifier: Centroid 300
+  default: nan
+  term: cheap Triangle 0 5 10
+  term: average Triangle 10 15 20
+  term: generous Triangle 20 25 30
+RuleBlock: mamdani
+  enabled: true
+  conjunction: Minimum
+  disjunction: Maximum
+  implication: Minimum
+  activation: General
+  rule: if service is poor or food is rancid then tip is cheap
+  rule: if service is good then tip is average
+  rule: if service is excellent or food is delicious then tip is generous
+`
+
+func TestRead(t *testing.T) {
+	Convey("read", t, func() {
+		Convey("when tipper", func() {
+			model, err := Read(strings.NewReader(fllTipper))
+			So(err, ShouldBeNil)
+			So(model.Name, ShouldEqual, "tipper")
+			So(model.Description, ShouldEqual, "tipper example")
+			So(model.Engine.ID(), ShouldEqual, id.ID("tipper"))
+			So(model.Engine.Description(), ShouldEqual, "tipper example")
+			So(model.Inputs, ShouldHaveLength, 2)
+			So(model.Outputs, ShouldHaveLength, 1)
+			So(model.Engine.Rules(), ShouldHaveLength, 3)
+
+			fvSvc, fvFood, fvTip := model.Inputs[0], model.Inputs[1], model.Outputs[0]
+			So(fvSvc.ID(), ShouldEqual, id.ID("service"))
+			So(fvFood.ID(), ShouldEqual, id.ID("food"))
+			So(fvTip.ID(), ShouldEqual, id.ID("tip"))
+			So(fvTip.U().Values(), ShouldHaveLength, 300)
+			So(fvSvc.Get("good").Builder(), ShouldResemble, fuzzy.Gauss{Sigma: 1.5, C: 5})
+
+			eval := func(service, food, tip float64) {
+				out, err := model.Engine.Evaluate(fuzzy.DataInput{
+					fvSvc:  service,
+					fvFood: food,
+				})
+				So(err, ShouldBeNil)
+				So(out[fvTip], ShouldAlmostEqual, tip, 1e-2)
+			}
+			eval(1, 2, 5.5586)
+			eval(3, 5, 12.2184)
+		})
+
+		Convey("when rules with parentheses, precedence and complement", func() {
+			model, err := Read(strings.NewReader(`
+Engine:
+InputVariable: a
+  range: 0 1
+  term: low Ramp 1 0
+  term: high Ramp 0 1
+OutputVariable: b
+  range: 0 1
+  aggregation: Maximum
+  defuzzifier: Centroid
+  term: low Ramp 1 0
+  term: high Ramp 0 1
+RuleBlock:
+  conjunction: AlgebraicProduct
+  disjunction: AlgebraicSum
+  implication: AlgebraicProduct
+  rule: if a is low or a is high and (a is not low or a is high) then b is high with 1.0
+`))
+			So(err, ShouldBeNil)
+			So(model.Outputs[0].U().Values(), ShouldHaveLength, DefaultResolution)
+
+			// low or (high and (not low or high))
+			rule := model.Engine.Rules()[0]
+			So(rule.Implication().Name(), ShouldEqual, "prod")
+			exp, ok := rule.Premise().(fuzzy.Expression)
+			So(ok, ShouldBeTrue)
+			So(exp.Connective(), ShouldEqual, fuzzy.ConnectiveOr)
+			So(exp.Operator(), ShouldEqual, fuzzy.OperatorHyperbolic{})
+			So(exp.Premises(), ShouldHaveLength, 2)
+			So(exp.Premises()[0].(fuzzy.IDSet).ID(), ShouldEqual, id.ID("low"))
+
+			expAnd := exp.Premises()[1].(fuzzy.Expression)
+			So(expAnd.Connective(), ShouldEqual, fuzzy.ConnectiveAnd)
+			expOr := expAnd.Premises()[1].(fuzzy.Expression)
+			So(expOr.Connective(), ShouldEqual, fuzzy.ConnectiveOr)
+			So(expOr.Premises()[0].(fuzzy.Expression).Complement(), ShouldBeTrue)
+			So(model.Inputs[0].Get("low").Builder(), ShouldResemble, fuzzy.StepDown{A: 0, B: 1})
+			So(model.Inputs[0].Get("high").Builder(), ShouldResemble, fuzzy.StepUp{A: 0, B: 1})
+		})
+
+		Convey("when rules with hedges and weight", func() {
+			model, err := Read(strings.NewReader(`
+Engine:
+InputVariable: a
+  range: 0 1
+  term: low Ramp 1 0
+OutputVariable: b
+  range: 0 1
+  aggregation: Maximum
+  defuzzifier: Centroid
+  term: high Ramp 0 1
+RuleBlock:
+  implication: Minimum
+  rule: if a is not very low then b is high with 0.5
+  rule: if a is somewhat low then b is high
+`))
+			So(err, ShouldBeNil)
+			rules := model.Engine.Rules()
+			So(rules[0].Weight(), ShouldEqual, 0.5)
+			exp := rules[0].Premise().(fuzzy.Expression)
+			So(exp.Complement(), ShouldBeTrue)
+			So(exp.Hedge().Name(), ShouldEqual, "very")
+			So(rules[1].Weight(), ShouldEqual, 1)
+			So(rules[1].Premise().(fuzzy.Expression).Hedge().Name(), ShouldEqual, "somewhat")
+		})
+
+		Convey("when errors", func() {
+			read := func(body string) error {
+				_, err := Read(strings.NewReader(`
+InputVariable: a
+  range: 0 1
+  term: a1 Triangle 0 0.5 1
+OutputVariable: b
+  range: 0 1
+  aggregation: Maximum
+  defuzzifier: Centroid 10
+  term: b1 Triangle 0 0.5 1
+` + body))
+				return err
+			}
+
+			So(read("wrong line"), ShouldBeError, "fll: line 10: `key: value` expected")
+			So(read("foo: bar"), ShouldBeError, "fll: line 10: unknown output variable property `foo`")
+			So(read("  term: b2 Constant 1"), ShouldBeError, "fll: line 5: variable `b`: term `b2`: shape `Constant` not supported")
+			So(read("  term: b2 Triangle 1 2"), ShouldBeError, "fll: line 5: variable `b`: term `b2`: 3 parameters expected (found: 2)")
+			So(read("  term: b1 Triangle 0 1 2"), ShouldBeError, "fll: line 5: variable `b`: term `b1` already defined")
+			So(read("  enabled: false"), ShouldBeError, "fll: line 10: disabled items not supported")
+			So(read("  aggregation: AlgebraicSum"), ShouldBeError, "fll: line 5: aggregation `AlgebraicSum` not supported")
+			So(read("InputVariable: a\n  range: 0 1"), ShouldBeError, "fll: line 10: variable `a` already defined")
+			So(read("InputVariable: c"), ShouldBeError, "fll: line 10: variable `c`: range expected")
+
+			rules := func(conjunction, disjunction, rule string) error {
+				return read("RuleBlock:\n  conjunction: " + conjunction + "\n  disjunction: " + disjunction + "\n  implication: Minimum\n  rule: " + rule)
+			}
+			So(rules("Minimum", "AlgebraicSum", "if a is a1 then b is b1"), ShouldBeError, "fll: line 10: conjunction `Minimum` and disjunction `AlgebraicSum` not supported together")
+			So(rules("Minimum", "DrasticSum", "if a is a1 then b is b1"), ShouldBeError, "fll: line 10: norm `DrasticSum` not supported")
+			So(rules("Minimum", "none", "if a is a1 or a is a1 then b is b1"), ShouldBeError, "fll: line 14: rule: column 12: connective `or` not allowed")
+			So(rules("Minimum", "Maximum", "if c is a1 then b is b1"), ShouldBeError, "fll: line 14: rule: column 4: unknown value `c`, did you mean `a`?")
+			So(rules("Minimum", "Maximum", "if a is a2 then b is b1"), ShouldBeError, "fll: line 14: rule: column 9: unknown set `a2` for value `a`, did you mean `a1`?")
+			So(rules("Minimum", "Maximum", "if a is seldom a1 then b is b1"), ShouldBeError, "fll: line 14: hedge `seldom` not supported")
+			So(rules("Minimum", "Maximum", "if (a is a1 then b is b1"), ShouldBeError, "fll: line 14: rule: column 13: `)` expected (found: `then`)")
+			So(rules("Minimum", "Maximum", "if a is a1 then b is b1 with 2"), ShouldBeError, "fll: line 14: rule: column 30: weight in [0, 1] expected (found: `2`)")
+			So(rules("Minimum", "Maximum", "if a is a1 then b is b1 b"), ShouldBeError, "fll: line 14: rule: column 25: unexpected `b`")
+			So(rules("Minimum", "Maximum", "if a is a1 xor a is a1 then b is b1"), ShouldBeError, "fll: line 14: rule: column 12: connective `xor` not allowed")
+			So(rules("Minimum", "Maximum", "if a is extremely a1 then b is b1"), ShouldBeError, "fll: line 14: hedge `extremely` not supported")
+		})
+	})
+}
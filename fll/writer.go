@@ -0,0 +1,321 @@
+package fll
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/sbiemont/fugologic/fuzzy"
+)
+
+// Write exports the model using the FLL format
+// Every fuzzy set shall have been created with a builder (see. fuzzy.NewIDValBuilders)
+func Write(w io.Writer, model Model) error {
+	if model.Engine.Members() != nil {
+		return fmt.Errorf("fll: ensemble not supported")
+	}
+	aggName, ok := aggregations[model.Engine.Aggregation().Name()]
+	if !ok {
+		return fmt.Errorf("fll: aggregation not supported")
+	}
+	defuzzName, ok := defuzzifiers[model.Engine.Defuzzification().Name()]
+	if !ok {
+		return fmt.Errorf("fll: defuzzification not supported")
+	}
+
+	if err := checkVariables(model); err != nil {
+		return err
+	}
+	blocks, err := newRuleBlocks(model.Engine.Rules())
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, strings.TrimSpace("Engine: "+model.Name))
+	if model.Description != "" {
+		fmt.Fprintf(bw, "description: %s\n", model.Description)
+	}
+
+	// Variables
+	for _, input := range model.Inputs {
+		if err := writeVariable(bw, "InputVariable", input, nil); err != nil {
+			return err
+		}
+	}
+	for _, output := range model.Outputs {
+		props := []string{
+			"aggregation: " + aggName,
+			fmt.Sprintf("defuzzifier: %s %d", defuzzName, len(output.U().Values())),
+			"default: nan",
+			"lock-previous: false",
+		}
+		if err := writeVariable(bw, "OutputVariable", output, props); err != nil {
+			return err
+		}
+	}
+
+	// Rules
+	for _, block := range blocks {
+		block.write(bw)
+	}
+	return bw.Flush()
+}
+
+// checkVariables controls that all variables used by the rules are defined in the model
+func checkVariables(model Model) error {
+	defined := make(map[*fuzzy.IDVal]struct{})
+	for _, idVal := range append(append([]*fuzzy.IDVal{}, model.Inputs...), model.Outputs...) {
+		defined[idVal] = struct{}{}
+	}
+
+	inputs, outputs := model.Engine.IO()
+	for _, idSet := range append(inputs, outputs...) {
+		if _, ok := defined[idSet.Parent()]; !ok {
+			return fmt.Errorf("fll: variable `%s` used but not defined", idSet.Parent().ID())
+		}
+	}
+	return nil
+}
+
+// writeVariable writes a variable section, its additional properties and its terms
+func writeVariable(w io.Writer, section string, idVal *fuzzy.IDVal, props []string) error {
+	if err := checkName(string(idVal.ID())); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "%s: %s\n", section, idVal.ID())
+	fmt.Fprintf(w, "  enabled: true\n")
+	fmt.Fprintf(w, "  range: %s %s\n", formatFloat(idVal.U().XMin()), formatFloat(idVal.U().XMax()))
+	fmt.Fprintf(w, "  lock-range: false\n")
+	for _, prop := range props {
+		fmt.Fprintf(w, "  %s\n", prop)
+	}
+
+	for _, idSet := range idVal.Terms() {
+		if err := checkName(string(idSet.ID())); err != nil {
+			return err
+		}
+		shape, params, err := termShape(idSet.Builder())
+		if err != nil {
+			return fmt.Errorf("fll: variable `%s`: term `%s`: %w", idVal.ID(), idSet.ID(), err)
+		}
+		values := make([]string, len(params))
+		for i, param := range params {
+			values[i] = formatFloat(param)
+		}
+		fmt.Fprintf(w, "  term: %s %s %s\n", idSet.ID(), shape, strings.Join(values, " "))
+	}
+	return nil
+}
+
+// termShape converts a set builder into a FLL term shape and its parameters
+func termShape(builder fuzzy.SetBuilder) (string, []float64, error) {
+	name, p, err := fuzzy.SetBuilderParams(builder)
+	if err != nil {
+		return "", nil, err
+	}
+
+	switch name {
+	case fuzzy.TRI:
+		return termTriangle, p, nil
+	case fuzzy.TRAP:
+		return termTrapezoid, p, nil
+	case fuzzy.GAUSS:
+		return termGaussian, []float64{p[1], p[0]}, nil // mean, sigma
+	case fuzzy.GBELL:
+		return termBell, []float64{p[2], p[0], p[1]}, nil // center, width, slope
+	case fuzzy.SIG:
+		return termSigmoid, []float64{p[1], p[0]}, nil // inflection, slope
+	case fuzzy.STEPUP:
+		return termRamp, p, nil // start, end
+	default: // fuzzy.STEPDOWN
+		return termRamp, []float64{p[1], p[0]}, nil // start, end
+	}
+}
+
+// writeRuleBlock gathers rules sharing the same operator and implication
+type writeRuleBlock struct {
+	optr  string // operator name (empty if no connective is used)
+	impl  string // implication name
+	rules []string
+}
+
+// newRuleBlocks converts and groups rules into rule blocks (in order of appearance)
+func newRuleBlocks(rules []fuzzy.Rule) ([]*writeRuleBlock, error) {
+	var blocks []*writeRuleBlock
+	for _, rule := range rules {
+		impl := rule.Implication().Name()
+		if _, ok := implications[impl]; !ok {
+			return nil, fmt.Errorf("fll: implication not supported")
+		}
+
+		text, optr, err := writeRule(rule)
+		if err != nil {
+			return nil, err
+		}
+
+		// Find a compatible block (rules without connective are compatible with any operator)
+		var found *writeRuleBlock
+		for _, block := range blocks {
+			if block.impl == impl && (block.optr == optr || block.optr == "" || optr == "") {
+				found = block
+				break
+			}
+		}
+		if found == nil {
+			found = &writeRuleBlock{impl: impl}
+			blocks = append(blocks, found)
+		}
+		if found.optr == "" {
+			found.optr = optr
+		}
+		found.rules = append(found.rules, text)
+	}
+	return blocks, nil
+}
+
+// write the rule block section
+func (rb writeRuleBlock) write(w io.Writer) {
+	conjunction, disjunction := "none", "none"
+	if rb.optr != "" {
+		conjunction, disjunction = norms[rb.optr][0], norms[rb.optr][1]
+	}
+
+	fmt.Fprintf(w, "RuleBlock:\n")
+	fmt.Fprintf(w, "  enabled: true\n")
+	fmt.Fprintf(w, "  conjunction: %s\n", conjunction)
+	fmt.Fprintf(w, "  disjunction: %s\n", disjunction)
+	fmt.Fprintf(w, "  implication: %s\n", implications[rb.impl])
+	fmt.Fprintf(w, "  activation: General\n")
+	for _, rule := range rb.rules {
+		fmt.Fprintf(w, "  rule: %s\n", rule)
+	}
+}
+
+// writeRule converts a rule into text and returns the name of the operator used
+func writeRule(rule fuzzy.Rule) (string, string, error) {
+	optr := ""
+	premise, err := writePremise(rule.Premise(), &optr)
+	if err != nil {
+		return "", "", err
+	}
+
+	outputs := make([]string, len(rule.Outputs()))
+	for i, output := range rule.Outputs() {
+		outputs[i] = writeIDSet(output, false)
+	}
+	text := fmt.Sprintf("%s %s %s %s", kwIf, premise, kwThen, strings.Join(outputs, " "+kwAnd+" "))
+	if weight := rule.Weight(); weight != 1 {
+		text += fmt.Sprintf(" %s %s", kwWith, formatFloat(weight))
+	}
+	return text, optr, nil
+}
+
+// writePremise converts a premise into text, and checks that only one operator is used
+func writePremise(premise fuzzy.Premise, optr *string) (string, error) {
+	switch p := premise.(type) {
+	case fuzzy.IDSet:
+		return writeIDSet(p, false), nil
+	case fuzzy.Expression:
+		premises := p.Premises()
+		hedge := p.Hedge().Name()
+		if p.Hedge() != nil && !supportedHedges[hedge] {
+			return "", fmt.Errorf("fll: hedge `%s` not supported", hedge)
+		}
+		if len(premises) == 1 {
+			if !p.Complement() && p.Hedge() == nil {
+				return writePremise(premises[0], optr)
+			}
+			if idSet, ok := premises[0].(fuzzy.IDSet); ok {
+				return writeHedgedIDSet(idSet, p.Complement(), hedge), nil
+			}
+		}
+		if p.Complement() {
+			return "", fmt.Errorf("fll: complement of a compound expression not supported")
+		}
+		if p.Hedge() != nil {
+			return "", fmt.Errorf("fll: hedge of a compound expression not supported")
+		}
+
+		// Check connective and operator
+		var keyword string
+		switch p.Connective() {
+		case fuzzy.ConnectiveAnd:
+			keyword = kwAnd
+		case fuzzy.ConnectiveOr:
+			keyword = kwOr
+		default:
+			return "", fmt.Errorf("fll: connective `%s` not supported", p.Connective())
+		}
+		name := fuzzy.OperatorName(p.Operator())
+		if _, ok := norms[name]; !ok {
+			return "", fmt.Errorf("fll: operator not supported")
+		}
+		if *optr != "" && *optr != name {
+			return "", fmt.Errorf("fll: several operators used in the same rule")
+		}
+		*optr = name
+
+		// Convert sub-premises
+		texts := make([]string, len(premises))
+		for i, sub := range premises {
+			text, err := writePremise(sub, optr)
+			if err != nil {
+				return "", err
+			}
+			if exp, ok := sub.(fuzzy.Expression); ok && len(exp.Premises()) > 1 {
+				text = "(" + text + ")"
+			}
+			texts[i] = text
+		}
+		return strings.Join(texts, " "+keyword+" "), nil
+	default:
+		return "", fmt.Errorf("fll: premise %T not supported", premise)
+	}
+}
+
+// writeIDSet converts a proposition into text
+func writeIDSet(idSet fuzzy.IDSet, complement bool) string {
+	if complement {
+		return fmt.Sprintf("%s %s %s %s", idSet.Parent().ID(), kwIs, kwNot, idSet.ID())
+	}
+	return fmt.Sprintf("%s %s %s", idSet.Parent().ID(), kwIs, idSet.ID())
+}
+
+// writeHedgedIDSet converts a proposition with an optional hedge into text
+func writeHedgedIDSet(idSet fuzzy.IDSet, complement bool, hedge string) string {
+	if hedge == "" {
+		return writeIDSet(idSet, complement)
+	}
+	if complement {
+		return fmt.Sprintf("%s %s %s %s %s", idSet.Parent().ID(), kwIs, kwNot, hedge, idSet.ID())
+	}
+	return fmt.Sprintf("%s %s %s %s", idSet.Parent().ID(), kwIs, hedge, idSet.ID())
+}
+
+// checkName controls that a name is a FuzzyLite identifier (letters, digits, '_' and '.')
+func checkName(name string) error {
+	if name == "" || strings.IndexFunc(name, func(r rune) bool { return !isNameRune(r) }) >= 0 {
+		return fmt.Errorf("fll: name `%s` cannot be written", name)
+	}
+	if _, isKeyword := keywords[name]; isKeyword {
+		return fmt.Errorf("fll: name `%s` is a keyword", name)
+	}
+	if _, isHedge := hedges[name]; isHedge {
+		return fmt.Errorf("fll: name `%s` is a hedge", name)
+	}
+	return nil
+}
+
+// isNameRune returns true if the rune is allowed in a FuzzyLite identifier
+func isNameRune(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '.'
+}
+
+// formatFloat writes the shortest representation of a float
+func formatFloat(flt float64) string {
+	return strconv.FormatFloat(flt, 'f', -1, 64)
+}
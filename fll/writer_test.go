@@ -0,0 +1,219 @@
+package fll
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/sbiemont/fugologic/crisp"
+	"github.com/sbiemont/fugologic/fuzzy"
+	"github.com/sbiemont/fugologic/id"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// Create a fuzzy value using builders
+func newTestVal(name id.ID, builders map[id.ID]fuzzy.SetBuilder) *fuzzy.IDVal {
+	u, err := crisp.NewSetN(0, 1, 11)
+	So(err, ShouldBeNil)
+	idVal, err := fuzzy.NewIDValBuilders(name, u, builders)
+	So(err, ShouldBeNil)
+	return idVal
+}
+
+func TestWrite(t *testing.T) {
+	Convey("write", t, func() {
+		fvA := newTestVal("a", map[id.ID]fuzzy.SetBuilder{
+			"a1": fuzzy.StepDown{A: 0, B: 0.5},
+			"a2": &fuzzy.Triangular{A: 0, B: 0.5, C: 1},
+			"a3": fuzzy.StepUp{A: 0.5, B: 1},
+		})
+		fvB := newTestVal("b", map[id.ID]fuzzy.SetBuilder{
+			"b1": fuzzy.Gauss{Sigma: 0.2, C: 0},
+			"b2": fuzzy.Gbell{A: 0.2, B: 2, C: 1},
+		})
+		fvC := newTestVal("c", map[id.ID]fuzzy.SetBuilder{
+			"c1": fuzzy.Trapezoid{A: 0, B: 0.1, C: 0.2, D: 0.3},
+			"c2": fuzzy.Sigmoid{A: 10, C: 0.5},
+		})
+		optr := fuzzy.OperatorZadeh{}
+
+		write := func(rules []fuzzy.Rule) (string, error) {
+			eng, err := fuzzy.NewEngine(rules, fuzzy.AggregationUnion, fuzzy.DefuzzificationCentroid)
+			So(err, ShouldBeNil)
+
+			var buf bytes.Buffer
+			err = Write(&buf, NewModel("test", eng))
+			return buf.String(), err
+		}
+
+		Convey("when ok", func() {
+			text, err := write([]fuzzy.Rule{
+				fuzzy.NewRule(
+					fuzzy.NewOperatorExpression([]fuzzy.Premise{
+						fvA.Get("a1"),
+						fuzzy.NewOperatorExpression([]fuzzy.Premise{
+							fvA.Get("a2"),
+							fuzzy.NewExpression([]fuzzy.Premise{fvB.Get("b1")}, nil).Not(),
+						}, optr, fuzzy.ConnectiveAnd),
+					}, optr, fuzzy.ConnectiveOr),
+					fuzzy.ImplicationMin,
+					[]fuzzy.IDSet{fvC.Get("c1")},
+				),
+				fuzzy.NewRule(fvA.Get("a3"), fuzzy.ImplicationProd, []fuzzy.IDSet{fvC.Get("c2")}),
+				fuzzy.NewRule(fvB.Get("b2"), fuzzy.ImplicationMin, []fuzzy.IDSet{fvC.Get("c2")}),
+			})
+			So(err, ShouldBeNil)
+			So(text, ShouldEqual, `Engine: test
+InputVariable: a
+  enabled: true
+  range: 0 1
+  lock-range: false
+  term: a1 Ramp 0.5 0
+  term: a2 Triangle 0 0.5 1
+  term: a3 Ramp 0.5 1
+InputVariable: b
+  enabled: true
+  range: 0 1
+  lock-range: false
+  term: b1 Gaussian 0 0.2
+  term: b2 Bell 1 0.2 2
+OutputVariable: c
+  enabled: true
+  range: 0 1
+  lock-range: false
+  aggregation: Maximum
+  defuzzifier: Centroid 11
+  default: nan
+  lock-previous: false
+  term: c1 Trapezoid 0 0.1 0.2 0.3
+  term: c2 Sigmoid 0.5 10
+RuleBlock:
+  enabled: true
+  conjunction: Minimum
+  disjunction: Maximum
+  implication: Minimum
+  activation: General
+  rule: if a is a1 or (a is a2 and b is not b1) then c is c1
+  rule: if b is b2 then c is c2
+RuleBlock:
+  enabled: true
+  conjunction: none
+  disjunction: none
+  implication: AlgebraicProduct
+  activation: General
+  rule: if a is a3 then c is c2
+`)
+		})
+
+		Convey("when hedges and weight", func() {
+			text, err := write([]fuzzy.Rule{
+				fuzzy.NewRule(
+					fuzzy.NewExpression([]fuzzy.Premise{fvA.Get("a1")}, nil).WithHedge(fuzzy.HedgeSomewhat).Not(),
+					fuzzy.ImplicationMin,
+					[]fuzzy.IDSet{fvC.Get("c1")},
+				).WithWeight(0.5),
+				fuzzy.NewRule(
+					fuzzy.NewExpression([]fuzzy.Premise{fvB.Get("b1")}, nil).WithHedge(fuzzy.HedgeVery),
+					fuzzy.ImplicationMin,
+					[]fuzzy.IDSet{fvC.Get("c2")},
+				),
+			})
+			So(err, ShouldBeNil)
+			So(text, ShouldContainSubstring, "  rule: if a is not somewhat a1 then c is c1 with 0.5\n")
+			So(text, ShouldContainSubstring, "  rule: if b is very b1 then c is c2\n")
+		})
+
+		Convey("when ko", func() {
+			Convey("when hedge not supported", func() {
+				_, err := write([]fuzzy.Rule{
+					fuzzy.NewRule(
+						fuzzy.NewExpression([]fuzzy.Premise{fvA.Get("a1")}, nil).WithHedge(fuzzy.HedgeExtremely),
+						fuzzy.ImplicationMin,
+						[]fuzzy.IDSet{fvC.Get("c1")},
+					),
+				})
+				So(err, ShouldBeError, "fll: hedge `extremely` not supported")
+			})
+
+			Convey("when complement of a compound expression", func() {
+				_, err := write([]fuzzy.Rule{
+					fuzzy.NewRule(
+						fuzzy.NewOperatorExpression([]fuzzy.Premise{fvA.Get("a1"), fvB.Get("b1")}, optr, fuzzy.ConnectiveAnd).Not(),
+						fuzzy.ImplicationMin,
+						[]fuzzy.IDSet{fvC.Get("c1")},
+					),
+				})
+				So(err, ShouldBeError, "fll: complement of a compound expression not supported")
+			})
+
+			Convey("when xor", func() {
+				_, err := write([]fuzzy.Rule{
+					fuzzy.NewRule(
+						fuzzy.NewOperatorExpression([]fuzzy.Premise{fvA.Get("a1"), fvB.Get("b1")}, optr, fuzzy.ConnectiveXOr),
+						fuzzy.ImplicationMin,
+						[]fuzzy.IDSet{fvC.Get("c1")},
+					),
+				})
+				So(err, ShouldBeError, "fll: connective `xor` not supported")
+			})
+
+			Convey("when several operators", func() {
+				_, err := write([]fuzzy.Rule{
+					fuzzy.NewRule(
+						fuzzy.NewOperatorExpression([]fuzzy.Premise{
+							fvA.Get("a1"),
+							fuzzy.NewOperatorExpression([]fuzzy.Premise{fvA.Get("a2"), fvB.Get("b1")}, fuzzy.OperatorHyperbolic{}, fuzzy.ConnectiveAnd),
+						}, optr, fuzzy.ConnectiveOr),
+						fuzzy.ImplicationMin,
+						[]fuzzy.IDSet{fvC.Get("c1")},
+					),
+				})
+				So(err, ShouldBeError, "fll: several operators used in the same rule")
+			})
+
+			Convey("when no builder", func() {
+				fvD, err := fuzzy.NewIDVal("d", crisp.Set{}, map[id.ID]fuzzy.Set{
+					"d1": func(x float64) float64 { return x },
+				})
+				So(err, ShouldBeNil)
+
+				_, err = write([]fuzzy.Rule{
+					fuzzy.NewRule(fvD.Get("d1"), fuzzy.ImplicationMin, []fuzzy.IDSet{fvC.Get("c1")}),
+				})
+				So(err, ShouldBeError, "fll: variable `d`: term `d1`: set builder expected")
+			})
+
+			Convey("when wrong name", func() {
+				fvD := newTestVal("d d", map[id.ID]fuzzy.SetBuilder{
+					"d1": fuzzy.StepUp{A: 0, B: 1},
+				})
+
+				_, err := write([]fuzzy.Rule{
+					fuzzy.NewRule(fvD.Get("d1"), fuzzy.ImplicationMin, []fuzzy.IDSet{fvC.Get("c1")}),
+				})
+				So(err, ShouldBeError, "fll: name `d d` cannot be written")
+
+				// Only letters, digits, '_' and '.' are allowed by FuzzyLite
+				for _, name := range []id.ID{"diff/consigne", "--", "+", "d-1", "é"} {
+					fvE := newTestVal("e", map[id.ID]fuzzy.SetBuilder{
+						name: fuzzy.StepUp{A: 0, B: 1},
+					})
+					_, err = write([]fuzzy.Rule{
+						fuzzy.NewRule(fvE.Get(name), fuzzy.ImplicationMin, []fuzzy.IDSet{fvC.Get("c1")}),
+					})
+					So(err, ShouldBeError, "fll: name `"+string(name)+"` cannot be written")
+				}
+			})
+
+			Convey("when undefined variable", func() {
+				eng, err := fuzzy.NewEngine([]fuzzy.Rule{
+					fuzzy.NewRule(fvA.Get("a1"), fuzzy.ImplicationMin, []fuzzy.IDSet{fvC.Get("c1")}),
+				}, fuzzy.AggregationUnion, fuzzy.DefuzzificationCentroid)
+				So(err, ShouldBeNil)
+
+				err = Write(&bytes.Buffer{}, Model{Engine: eng, Inputs: []*fuzzy.IDVal{fvA}})
+				So(err, ShouldBeError, "fll: variable `c` used but not defined")
+			})
+		})
+	})
+}
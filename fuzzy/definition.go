@@ -2,6 +2,9 @@ package fuzzy
 
 import (
 	"errors"
+	"fmt"
+	"slices"
+	"sort"
 
 	"github.com/sbiemont/fugologic/crisp"
 	"github.com/sbiemont/fugologic/id"
@@ -9,9 +12,10 @@ import (
 
 // IDSet represents a static Set with an ID
 type IDSet struct {
-	set    Set    // membership function
-	uuid   id.ID  // identifier is only used to have error information
-	parent *IDVal // parent leads to the IDVal (for defuzzification)
+	set     Set        // membership function
+	uuid    id.ID      // identifier is only used to have error information
+	parent  *IDVal     // parent leads to the IDVal (for defuzzification)
+	builder SetBuilder // builder of the membership function (optional)
 }
 
 // ID returns the identifier
@@ -19,6 +23,26 @@ func (is IDSet) ID() id.ID {
 	return is.uuid
 }
 
+// Parent returns the fuzzy value linked to the fuzzy set
+func (is IDSet) Parent() *IDVal {
+	return is.parent
+}
+
+// Set returns the membership function
+func (is IDSet) Set() Set {
+	return is.set
+}
+
+// Builder returns the builder of the membership function (nil if the set has not been built from a builder)
+func (is IDSet) Builder() SetBuilder {
+	return is.builder
+}
+
+// String returns the identifier of the set
+func (is IDSet) String() string {
+	return string(is.uuid)
+}
+
 // Evaluate fetches the right input and returns the Set value
 func (is IDSet) Evaluate(input DataInput) (float64, error) {
 	x, err := input.value(is)
@@ -42,41 +66,121 @@ func (iss IDSets) IDVals() map[*IDVal]struct{} {
 
 // IDVal represents a static Val with an ID and a set of crisp values
 type IDVal struct {
-	uuid   id.ID
-	u      crisp.Set
-	idSets map[id.ID]IDSet // used for consistency checking
+	uuid    id.ID
+	u       crisp.Set
+	idSets  map[id.ID]IDSet // used for consistency checking
+	order   []id.ID         // order of the terms
+	ordered bool            // the order has been declared (see. NewIDValOrdered)
 }
 
 // NewIDVal associates a list of Set with a custom ID
+// The terms are ordered by identifier (see. NewIDValOrdered to declare the order)
 func NewIDVal(
 	uuid id.ID, // uuid is the identifier of the fuzzy value (empty uuid is rejected)
 	u crisp.Set, // u is the crisp universe of the value
 	sets map[id.ID]Set, // sets are the list of couples uuid + fuzzy set (empty uuids are rejected)
 ) (*IDVal, error) {
+	names := make([]id.ID, 0, len(sets))
+	for name := range sets {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return names[i] < names[j]
+	})
+	return newIDVal(uuid, u, names, sets, false)
+}
+
+// NewIDValOrdered associates a list of Set with a custom ID, the terms being ordered by names
+// Eg.: []id.ID{"low", "medium", "high"}
+// The names shall list each fuzzy set exactly once
+func NewIDValOrdered(uuid id.ID, u crisp.Set, names []id.ID, sets map[id.ID]Set) (*IDVal, error) {
+	return newIDVal(uuid, u, names, sets, true)
+}
+
+// newIDVal associates a list of Set with a custom ID, the terms being ordered by names
+func newIDVal(uuid id.ID, u crisp.Set, names []id.ID, sets map[id.ID]Set, ordered bool) (*IDVal, error) {
 	// Check for empty id
 	if uuid.Empty() {
 		return nil, errors.New("id val cannot be empty")
 	}
 	iv := &IDVal{
-		uuid:   uuid,
-		u:      u,
-		idSets: make(map[id.ID]IDSet, len(sets)),
+		uuid:    uuid,
+		u:       u,
+		idSets:  make(map[id.ID]IDSet, len(sets)),
+		order:   make([]id.ID, 0, len(names)),
+		ordered: ordered,
 	}
 
 	// Convert to id set and check for empty id
-	for name, set := range sets {
+	for _, name := range names {
 		if name.Empty() {
 			return nil, errors.New("id set cannot be empty")
 		}
+		set, ok := sets[name]
+		if !ok {
+			return nil, fmt.Errorf("id set `%s` not defined", name)
+		}
+		if _, exists := iv.idSets[name]; exists {
+			return nil, fmt.Errorf("id set `%s` already ordered", name)
+		}
 		iv.idSets[name] = IDSet{
 			set:    set,
 			uuid:   name,
 			parent: iv,
 		}
+		iv.order = append(iv.order, name)
+	}
+	if len(iv.order) != len(sets) {
+		for name := range sets {
+			if _, exists := iv.idSets[name]; !exists {
+				return nil, fmt.Errorf("id set `%s` not ordered", name)
+			}
+		}
 	}
 	return iv, nil
 }
 
+// NewIDValBuilders builds the fuzzy sets and associates them with a custom ID
+// Unlike NewIDVal, the builders are kept within the fuzzy sets (see. IDSet.Builder)
+func NewIDValBuilders(uuid id.ID, u crisp.Set, builders map[id.ID]SetBuilder) (*IDVal, error) {
+	sets, err := NewIDSets(builders)
+	if err != nil {
+		return nil, err
+	}
+
+	iv, err := NewIDVal(uuid, u, sets)
+	if err != nil {
+		return nil, err
+	}
+	iv.setBuilders(builders)
+	return iv, nil
+}
+
+// NewIDValBuildersOrdered builds the fuzzy sets and associates them with a custom ID, the terms being ordered by names
+// (see. NewIDValOrdered and NewIDValBuilders)
+func NewIDValBuildersOrdered(uuid id.ID, u crisp.Set, names []id.ID, builders map[id.ID]SetBuilder) (*IDVal, error) {
+	sets, err := NewIDSets(builders)
+	if err != nil {
+		return nil, err
+	}
+
+	iv, err := NewIDValOrdered(uuid, u, names, sets)
+	if err != nil {
+		return nil, err
+	}
+	iv.setBuilders(builders)
+	return iv, nil
+}
+
+// setBuilders keeps the builders within the fuzzy sets
+func (iv *IDVal) setBuilders(builders map[id.ID]SetBuilder) {
+	for name, builder := range builders {
+		idSet := iv.idSets[name]
+		idSet.builder = builder
+		iv.idSets[name] = idSet
+	}
+}
+
 // ID returns the identifier
 func (iv IDVal) ID() id.ID {
 	return iv.uuid
@@ -97,3 +201,40 @@ func (iv IDVal) Fetch(name id.ID) (IDSet, bool) {
 	idSet, ok := iv.idSets[name]
 	return idSet, ok
 }
+
+// Terms returns all fuzzy sets of the value, in the declared order
+func (iv IDVal) Terms() []IDSet {
+	result := make([]IDSet, len(iv.order))
+	for i, name := range iv.order {
+		result[i] = iv.idSets[name]
+	}
+	return result
+}
+
+// Ordered checks if the order of the terms has been declared (see. NewIDValOrdered), instead of being sorted by identifier
+func (iv IDVal) Ordered() bool {
+	return iv.ordered
+}
+
+// Index returns the position of a fuzzy set in the declared order (-1 if not found)
+func (iv IDVal) Index(name id.ID) int {
+	return slices.Index(iv.order, name)
+}
+
+// Next returns the fuzzy set following the given one (false if not found or if last)
+func (iv IDVal) Next(name id.ID) (IDSet, bool) {
+	return iv.at(iv.Index(name), 1)
+}
+
+// Prev returns the fuzzy set preceding the given one (false if not found or if first)
+func (iv IDVal) Prev(name id.ID) (IDSet, bool) {
+	return iv.at(iv.Index(name), -1)
+}
+
+// at returns the fuzzy set shifted from the position i (false if not found or out of range)
+func (iv IDVal) at(i, shift int) (IDSet, bool) {
+	if i < 0 || i+shift < 0 || i+shift >= len(iv.order) {
+		return IDSet{}, false
+	}
+	return iv.idSets[iv.order[i+shift]], true
+}
@@ -58,6 +58,7 @@ func TestIDVal(t *testing.T) {
 				uuid:   "value",
 				u:      crisp.Set{},
 				idSets: map[id.ID]IDSet{},
+				order:  []id.ID{},
 			})
 		})
 
@@ -132,6 +133,113 @@ func TestIDVal(t *testing.T) {
 			So(v3.uuid, ShouldBeEmpty)
 		})
 	})
+
+	Convey("ordered terms", t, func() {
+		val, err := NewIDValOrdered("value", crisp.Set{}, []id.ID{"low", "medium", "high"}, map[id.ID]Set{
+			"high":   nil,
+			"low":    nil,
+			"medium": nil,
+		})
+		So(err, ShouldBeNil)
+
+		Convey("when terms()", func() {
+			terms := val.Terms()
+			So(terms, ShouldHaveLength, 3)
+			So(terms[0].ID(), ShouldEqual, id.ID("low"))
+			So(terms[1].ID(), ShouldEqual, id.ID("medium"))
+			So(terms[2].ID(), ShouldEqual, id.ID("high"))
+			So(val.Ordered(), ShouldBeTrue)
+		})
+
+		Convey("when index()", func() {
+			So(val.Index("low"), ShouldEqual, 0)
+			So(val.Index("high"), ShouldEqual, 2)
+			So(val.Index("none"), ShouldEqual, -1)
+		})
+
+		Convey("when next() and prev()", func() {
+			next, ok := val.Next("low")
+			So(ok, ShouldBeTrue)
+			So(next.ID(), ShouldEqual, id.ID("medium"))
+			_, ok = val.Next("high")
+			So(ok, ShouldBeFalse)
+
+			prev, ok := val.Prev("medium")
+			So(ok, ShouldBeTrue)
+			So(prev.ID(), ShouldEqual, id.ID("low"))
+			_, ok = val.Prev("low")
+			So(ok, ShouldBeFalse)
+			_, ok = val.Prev("none")
+			So(ok, ShouldBeFalse)
+		})
+
+		Convey("when unordered, sorted by identifier", func() {
+			val, err := NewIDVal("value", crisp.Set{}, map[id.ID]Set{"b": nil, "c": nil, "a": nil})
+			So(err, ShouldBeNil)
+			So(val.Index("a"), ShouldEqual, 0)
+			So(val.Index("c"), ShouldEqual, 2)
+			So(val.Ordered(), ShouldBeFalse)
+		})
+
+		Convey("when error", func() {
+			sets := map[id.ID]Set{"low": nil, "high": nil}
+			_, err := NewIDValOrdered("value", crisp.Set{}, []id.ID{"low", "medium"}, sets)
+			So(err, ShouldBeError, "id set `medium` not defined")
+
+			_, err = NewIDValOrdered("value", crisp.Set{}, []id.ID{"low", "low"}, sets)
+			So(err, ShouldBeError, "id set `low` already ordered")
+
+			_, err = NewIDValOrdered("value", crisp.Set{}, []id.ID{"low"}, sets)
+			So(err, ShouldBeError, "id set `high` not ordered")
+
+			_, err = NewIDValOrdered("value", crisp.Set{}, []id.ID{""}, sets)
+			So(err, ShouldBeError, "id set cannot be empty")
+		})
+	})
+}
+
+func TestIDValBuilders(t *testing.T) {
+	Convey("new id val with builders", t, func() {
+		Convey("when ok", func() {
+			val, err := NewIDValBuilders("value", crisp.Set{}, map[id.ID]SetBuilder{
+				"set #2": Triangular{A: 0, B: 1, C: 2},
+				"set #1": StepUp{A: 0, B: 1},
+			})
+			So(err, ShouldBeNil)
+			So(val.Get("set #1").Builder(), ShouldResemble, StepUp{A: 0, B: 1})
+			So(val.Get("set #1").Parent(), ShouldEqual, val)
+			So(val.Get("set #2").Builder(), ShouldResemble, Triangular{A: 0, B: 1, C: 2})
+			So(val.Get("set #2").set(1), ShouldEqual, 1)
+
+			terms := val.Terms()
+			So(terms, ShouldHaveLength, 2)
+			So(terms[0].ID(), ShouldEqual, id.ID("set #1"))
+			So(terms[1].ID(), ShouldEqual, id.ID("set #2"))
+		})
+
+		Convey("when ordered", func() {
+			val, err := NewIDValBuildersOrdered("value", crisp.Set{}, []id.ID{"set #2", "set #1"}, map[id.ID]SetBuilder{
+				"set #1": StepUp{A: 0, B: 1},
+				"set #2": Triangular{A: 0, B: 1, C: 2},
+			})
+			So(err, ShouldBeNil)
+			So(val.Terms()[0].Builder(), ShouldResemble, Triangular{A: 0, B: 1, C: 2})
+			So(val.Index("set #1"), ShouldEqual, 1)
+		})
+
+		Convey("when wrong builder", func() {
+			val, err := NewIDValBuilders("value", crisp.Set{}, map[id.ID]SetBuilder{
+				"set #1": Triangular{A: 2, B: 1, C: 0},
+			})
+			So(err, ShouldBeError, "set #1: tri: params shall be sorted")
+			So(val, ShouldBeNil)
+		})
+
+		Convey("when no builder", func() {
+			val, _ := NewIDVal("value", crisp.Set{}, map[id.ID]Set{"set #1": nil})
+			So(val.Get("set #1").Builder(), ShouldBeNil)
+		})
+	})
 }
 
 func TestIDSets(t *testing.T) {
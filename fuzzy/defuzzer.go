@@ -36,6 +36,26 @@ var (
 	DefuzzificationBisector Defuzzification = defuzzificationBisector
 )
 
+// defuzzifications lists the predefined defuzzification methods by name
+var defuzzifications = map[string]Defuzzification{
+	"centroid":        DefuzzificationCentroid,
+	"bisector":        DefuzzificationBisector,
+	"smallest-of-max": DefuzzificationSmallestOfMaxs,
+	"middle-of-max":   DefuzzificationMiddleOfMaxs,
+	"largest-of-max":  DefuzzificationLargestOfMaxs,
+}
+
+// DefuzzificationByName returns the predefined defuzzification method matching the name
+func DefuzzificationByName(name string) (Defuzzification, bool) {
+	defuzz, ok := defuzzifications[name]
+	return defuzz, ok
+}
+
+// Name returns the name of a predefined defuzzification method, or an empty string for a custom one
+func (defuzz Defuzzification) Name() string {
+	return nameOf(defuzz, defuzzifications)
+}
+
 func defuzzificationCentroid(fs Set, u crisp.Set) float64 {
 	var mx, m float64
 	for _, x := range u.Values() {
@@ -110,6 +130,23 @@ var (
 	AggregationIntersection Aggregation = math.Min
 )
 
+// aggregations lists the predefined aggregations by name
+var aggregations = map[string]Aggregation{
+	"union":        AggregationUnion,
+	"intersection": AggregationIntersection,
+}
+
+// AggregationByName returns the predefined aggregation matching the name
+func AggregationByName(name string) (Aggregation, bool) {
+	agg, ok := aggregations[name]
+	return agg, ok
+}
+
+// Name returns the name of a predefined aggregation, or an empty string for a custom one
+func (agg Aggregation) Name() string {
+	return nameOf(agg, aggregations)
+}
+
 // defuzzer is responsible for collecting rule's results and to defuzz
 type defuzzer struct {
 	agg Aggregation     // Aggregation of result fuzzy sets
@@ -126,17 +163,28 @@ func newDefuzzer(fct Defuzzification, agg Aggregation) defuzzer {
 
 // defuzz the values
 func (dfz defuzzer) defuzz(iss []IDSet) DataOutput {
-	// Group IDSet by IDVal parent
+	return dfz.defuzzAggregations(dfz.aggregations(iss))
+}
+
+// aggregations groups the IDSet by IDVal parent and aggregates each group
+func (dfz defuzzer) aggregations(iss []IDSet) map[*IDVal]Set {
 	groups := make(map[*IDVal][]IDSet)
 	for _, idSet := range iss {
 		idVal := idSet.parent
 		groups[idVal] = append(groups[idVal], idSet)
 	}
 
-	// For each group, apply defuzz
-	values := make(DataOutput, len(iss))
+	result := make(map[*IDVal]Set, len(groups))
 	for idVal, group := range groups {
-		aggregation := dfz.aggregate(group)
+		result[idVal] = dfz.aggregate(group)
+	}
+	return result
+}
+
+// defuzzAggregations applies the defuzzification on each aggregated set
+func (dfz defuzzer) defuzzAggregations(sets map[*IDVal]Set) DataOutput {
+	values := make(DataOutput, len(sets))
+	for idVal, aggregation := range sets {
 		values[idVal] = dfz.fct(aggregation, idVal.u)
 	}
 	return values
@@ -24,6 +24,30 @@ func fsUnion(fs1 Set, fs ...Set) Set {
 	}
 }
 
+func TestDefuzzerNames(t *testing.T) {
+	Convey("aggregation names", t, func() {
+		for _, name := range []string{"union", "intersection"} {
+			agg, ok := AggregationByName(name)
+			So(ok, ShouldBeTrue)
+			So(agg.Name(), ShouldEqual, name)
+		}
+		_, ok := AggregationByName("unknown")
+		So(ok, ShouldBeFalse)
+		So(Aggregation(math.Pow).Name(), ShouldBeEmpty)
+	})
+
+	Convey("defuzzification names", t, func() {
+		for _, name := range []string{"centroid", "bisector", "smallest-of-max", "middle-of-max", "largest-of-max"} {
+			defuzz, ok := DefuzzificationByName(name)
+			So(ok, ShouldBeTrue)
+			So(defuzz.Name(), ShouldEqual, name)
+		}
+		_, ok := DefuzzificationByName("unknown")
+		So(ok, ShouldBeFalse)
+		So(defuzzificationNone.Name(), ShouldBeEmpty)
+	})
+}
+
 func TestDefuzzerDefuzz(t *testing.T) {
 	setA, _ := crisp.NewSet(1, 4, 0.1)
 	fsA1, _ := Triangular{1, 2, 3}.New()
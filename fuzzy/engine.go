@@ -2,6 +2,8 @@ package fuzzy
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/sbiemont/fugologic/id"
 
@@ -10,31 +12,163 @@ import (
 
 // Engine is responsible for evaluating all rules and defuzzing
 type Engine struct {
-	uuid   id.ID // optional
-	rules  []Rule
-	agg    Aggregation
-	defuzz Defuzzification
+	uuid        id.ID    // optional
+	description string   // optional
+	history     *History // optional
+	rules       []Rule
+	agg         Aggregation
+	defuzz      Defuzzification
+	ensemble    *ensemble // optional, replaces the rules (see. NewEnsembleAggregation)
+}
+
+// EngineOption sets an optional property of an engine
+type EngineOption func(eng *Engine)
+
+// WithID sets the identifier of the engine (used in system errors)
+func WithID(uuid id.ID) EngineOption {
+	return func(eng *Engine) {
+		eng.uuid = uuid
+	}
+}
+
+// WithDescription sets the description of the engine
+func WithDescription(description string) EngineOption {
+	return func(eng *Engine) {
+		eng.description = description
+	}
+}
+
+// WithHistory sets the history recording the inputs of the engine before each evaluation (see. temporal premises)
+// An engine with a history shall not be evaluated concurrently
+func WithHistory(hist *History) EngineOption {
+	return func(eng *Engine) {
+		eng.history = hist
+	}
 }
 
 // NewEngine builds a new Engine instance
 //   - The Aggregation merges all result sets together
 //   - The Defuzzification extracts one value from the aggregation
-func NewEngine(r []Rule, agg Aggregation, defuzz Defuzzification) (Engine, error) {
+//   - The options set the identifier, the description and the history of the engine (see. WithID, WithDescription, WithHistory)
+func NewEngine(r []Rule, agg Aggregation, defuzz Defuzzification, opts ...EngineOption) (Engine, error) {
 	// Check
 	inputs, outputs := rules(r).io()
 	if err := checkIDs(append(inputs, outputs...)); err != nil {
 		return Engine{}, err
 	}
 
-	return Engine{
+	eng := Engine{
 		rules:  r,
 		defuzz: defuzz,
 		agg:    agg,
-	}, nil
+	}
+	for _, opt := range opts {
+		opt(&eng)
+	}
+	return eng, nil
+}
+
+// ID returns the identifier of the engine (empty if not defined)
+func (eng Engine) ID() id.ID {
+	return eng.uuid
+}
+
+// Description returns the description of the engine (empty if not defined)
+func (eng Engine) Description() string {
+	return eng.description
 }
 
 // Evalute rules (in parallel) and defuzz result
+// The input is recorded first if the engine has a history
 func (eng Engine) Evaluate(input DataInput) (DataOutput, error) {
+	eng.record(input)
+	return eng.evaluate(input)
+}
+
+// evaluate rules without recording the input
+func (eng Engine) evaluate(input DataInput) (DataOutput, error) {
+	if eng.ensemble != nil {
+		trace, err := eng.trace(input)
+		return trace.Output, err
+	}
+
+	flattenIDSets, err := eng.implications(input)
+	if err != nil {
+		return nil, err
+	}
+
+	// Apply defuzzification
+	dfz := newDefuzzer(eng.defuzz, eng.agg)
+	return dfz.defuzz(flattenIDSets), nil
+}
+
+// Evaluator is implemented by Engine and System
+type Evaluator interface {
+	Evaluate(input DataInput) (DataOutput, error)
+}
+
+// Tracer is an evaluator that also returns the aggregated result sets, implemented by Engine and System
+type Tracer interface {
+	Evaluator
+	Trace(input DataInput) (Trace, error)
+}
+
+// Trace details an engine evaluation
+type Trace struct {
+	Output     DataOutput     // defuzzified value of each output
+	Aggregated map[*IDVal]Set // aggregated result set of each output
+}
+
+// Trace evaluates the rules and keeps the aggregated result sets used by the defuzzification
+func (eng Engine) Trace(input DataInput) (Trace, error) {
+	eng.record(input)
+	return eng.trace(input)
+}
+
+// record appends the input to the histories of the engine and of its members (if any)
+func (eng Engine) record(input DataInput) {
+	for _, hist := range eng.histories(nil) {
+		hist.Record(input)
+	}
+}
+
+// histories appends the distinct histories of the engine and of its members (if any)
+func (eng Engine) histories(result []*History) []*History {
+	if eng.history != nil && !slices.Contains(result, eng.history) {
+		result = append(result, eng.history)
+	}
+	for _, member := range eng.Members() {
+		result = member.Engine.histories(result)
+	}
+	return result
+}
+
+// History returns the history of the engine (nil if not defined)
+func (eng Engine) History() *History {
+	return eng.history
+}
+
+// trace evaluates the rules without recording the input, and keeps the aggregated result sets
+func (eng Engine) trace(input DataInput) (Trace, error) {
+	if eng.ensemble != nil {
+		return eng.ensemble.trace(input, eng.agg, eng.defuzz)
+	}
+
+	flattenIDSets, err := eng.implications(input)
+	if err != nil {
+		return Trace{}, err
+	}
+
+	dfz := newDefuzzer(eng.defuzz, eng.agg)
+	aggregated := dfz.aggregations(flattenIDSets)
+	return Trace{
+		Output:     dfz.defuzzAggregations(aggregated),
+		Aggregated: aggregated,
+	}, nil
+}
+
+// implications evaluates rules (in parallel) and returns all implied result sets
+func (eng Engine) implications(input DataInput) ([]IDSet, error) {
 	evaluatedIDSets := make([][]IDSet, len(eng.rules)) // prepare results for go routines
 	var grp errgroup.Group
 	for i, rule := range eng.rules {
@@ -58,18 +192,42 @@ func (eng Engine) Evaluate(input DataInput) (DataOutput, error) {
 	for _, idSets := range evaluatedIDSets {
 		flattenIDSets = append(flattenIDSets, idSets...)
 	}
-
-	// Apply defuzzification
-	dfz := newDefuzzer(eng.defuzz, eng.agg)
-	return dfz.defuzz(flattenIDSets), nil
+	return flattenIDSets, nil
 }
 
 // IO gather and flatten all IDSet from rules' expressions
 // Return inputs and outputs IDSet
 func (eng Engine) IO() ([]IDSet, []IDSet) {
+	if eng.ensemble != nil {
+		return eng.ensemble.io()
+	}
 	return rules(eng.rules).io()
 }
 
+// Rules returns the rules of the engine
+func (eng Engine) Rules() []Rule {
+	return eng.rules
+}
+
+// Aggregation returns the aggregation method of the engine
+func (eng Engine) Aggregation() Aggregation {
+	return eng.agg
+}
+
+// Defuzzification returns the defuzzification method of the engine
+func (eng Engine) Defuzzification() Defuzzification {
+	return eng.defuzz
+}
+
+// String describes the rules of the engine, one rule per line
+func (eng Engine) String() string {
+	texts := make([]string, len(eng.rules))
+	for i, rule := range eng.rules {
+		texts[i] = rule.String()
+	}
+	return strings.Join(texts, "\n")
+}
+
 // checkIDs of a list of IDSet
 // Get all unique IDVal, check them and their whole IDSet
 func checkIDs(idSets []IDSet) error {
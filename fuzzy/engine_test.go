@@ -208,6 +208,24 @@ func TestEngineEvaluate(t *testing.T) {
 		So(result, ShouldResemble, DataOutput{
 			fvValve: 35.73264090043862,
 		})
+
+		// Trace engine
+		trace, errTrace := engine.Trace(DataInput{
+			fvPressure:    2.5,
+			fvTemperature: 17,
+		})
+		So(errTrace, ShouldBeNil)
+		So(trace.Output, ShouldResemble, result)
+		So(trace.Aggregated, ShouldHaveLength, 1)
+		aggregated := trace.Aggregated[fvValve]
+		So(aggregated(10), ShouldEqual, 0)
+		So(aggregated(30), ShouldAlmostEqual, 0.25)    // average: min(0.25, 1)
+		So(aggregated(40), ShouldAlmostEqual, 1.0/3.0) // wide: min(1/3, 1)
+		So(aggregated(35), ShouldAlmostEqual, 1.0/3.0) // max(average, wide)
+		So(fvValve.Get("wide").Set()(40), ShouldEqual, 1)
+
+		_, errTrace = engine.Trace(DataInput{})
+		So(errTrace, ShouldNotBeNil)
 	})
 
 	Convey("custom minimalistic test", t, func() {
@@ -425,6 +443,45 @@ func TestEngineIO(t *testing.T) {
 			})
 		})
 	})
+
+	Convey("accessors", t, func() {
+		eng, _, _, _, err := customEngine()
+		So(err, ShouldBeNil)
+		So(eng.Rules(), ShouldHaveLength, 25)
+		So(eng.Aggregation().Name(), ShouldEqual, "union")
+		So(eng.Defuzzification().Name(), ShouldEqual, "centroid")
+		So(eng.ID(), ShouldBeEmpty)
+		So(eng.Description(), ShouldBeEmpty)
+
+		named, err := NewEngine(eng.Rules(), eng.Aggregation(), eng.Defuzzification(), WithID("force"), WithDescription("force control"))
+		So(err, ShouldBeNil)
+		So(named.ID(), ShouldEqual, id.ID("force"))
+		So(named.Description(), ShouldEqual, "force control")
+	})
+
+	Convey("string", t, func() {
+		_, fsA1 := newTestVal("a", "a1")
+		_, fsB1 := newTestVal("b", "b1")
+		_, fsC2 := newTestVal("c", "c2")
+		_, fsD1 := newTestVal("d", "d1")
+		_, fsE1 := newTestVal("e", "e1")
+		optr := OperatorZadeh{}
+
+		eng, err := NewEngine([]Rule{
+			NewRule(
+				NewOperatorExpression([]Premise{
+					NewOperatorExpression([]Premise{fsA1, fsB1}, optr, ConnectiveAnd),
+					NewExpression([]Premise{fsC2}, nil).Not(),
+				}, optr, ConnectiveOr),
+				ImplicationMin,
+				[]IDSet{fsD1},
+			),
+			NewRule(fsA1, ImplicationMin, []IDSet{fsE1}),
+		}, AggregationUnion, DefuzzificationCentroid)
+		So(err, ShouldBeNil)
+		So(eng.String(), ShouldEqual, "IF (a1 AND b1) OR NOT c2 THEN d1\nIF a1 THEN e1")
+		So(Engine{}.String(), ShouldBeEmpty)
+	})
 }
 
 func BenchmarkEngineNTimes(b *testing.B) {
@@ -0,0 +1,225 @@
+package fuzzy
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Member is an engine of an ensemble, with its weight
+type Member struct {
+	Engine Engine
+	Weight float64 // strictly positive
+}
+
+// combination defines how the results of the members of an ensemble are combined
+type combination int
+
+const (
+	combineAggregation combination = iota
+	combineAverage
+	combineVote
+)
+
+// ensemble evaluates several engines producing the same outputs and combines their results
+type ensemble struct {
+	members     []Member
+	combination combination
+}
+
+// NewEnsembleAggregation creates an engine combining the fuzzy results of several engines producing the same outputs
+// The aggregated result sets of the members are weighted (relatively to the highest weight),
+// then aggregated and defuzzified using the given methods
+func NewEnsembleAggregation(members []Member, agg Aggregation, defuzz Defuzzification, opts ...EngineOption) (Engine, error) {
+	return newEnsemble(members, combineAggregation, agg, defuzz, opts)
+}
+
+// NewEnsembleAverage creates an engine computing the weighted average of the crisp results of several engines producing the same outputs
+func NewEnsembleAverage(members []Member, opts ...EngineOption) (Engine, error) {
+	return newEnsemble(members, combineAverage, nil, nil, opts)
+}
+
+// NewEnsembleVote creates an engine where several engines producing the same outputs vote for an output term
+// For each output, each member votes (with its weight) for the term of highest membership of its crisp result,
+// the result is the weighted average of the crisp results of the members having voted for the winning term
+// Ties are won by the first term in the order of the value (its declared order, else sorted by identifier, see. IDVal.Terms)
+func NewEnsembleVote(members []Member, opts ...EngineOption) (Engine, error) {
+	return newEnsemble(members, combineVote, nil, nil, opts)
+}
+
+// newEnsemble checks the members and creates a new engine
+func newEnsemble(members []Member, cmb combination, agg Aggregation, defuzz Defuzzification, opts []EngineOption) (Engine, error) {
+	if len(members) == 0 {
+		return Engine{}, errors.New("ensemble: no engine")
+	}
+
+	var reference map[*IDVal]struct{}
+	for i, member := range members {
+		if member.Weight <= 0 {
+			return Engine{}, fmt.Errorf("ensemble: %s: positive weight expected (found: %g)", memberName(members, i), member.Weight)
+		}
+		_, outputs := member.Engine.IO()
+		idVals := IDSets(outputs).IDVals()
+		if reference == nil {
+			reference = idVals
+			continue
+		}
+		if len(idVals) != len(reference) {
+			return Engine{}, fmt.Errorf("ensemble: %s: outputs differ from the first engine", memberName(members, i))
+		}
+		for idVal := range idVals {
+			if _, exists := reference[idVal]; !exists {
+				return Engine{}, fmt.Errorf("ensemble: %s: outputs differ from the first engine", memberName(members, i))
+			}
+		}
+	}
+
+	eng := Engine{
+		agg:    agg,
+		defuzz: defuzz,
+		ensemble: &ensemble{
+			members:     append([]Member{}, members...),
+			combination: cmb,
+		},
+	}
+	for _, opt := range opts {
+		opt(&eng)
+	}
+	return eng, nil
+}
+
+// Members returns the members of an ensemble (nil for a rule based engine)
+func (eng Engine) Members() []Member {
+	if eng.ensemble == nil {
+		return nil
+	}
+	return eng.ensemble.members
+}
+
+// memberName describes the member at the position: its identifier if defined, else its position
+func memberName(members []Member, i int) string {
+	if uuid := members[i].Engine.uuid; uuid != "" {
+		return fmt.Sprintf("engine `%s`", uuid)
+	}
+	return fmt.Sprintf("engine #%d", i)
+}
+
+// io gathers the inputs and outputs of all members
+func (ens ensemble) io() ([]IDSet, []IDSet) {
+	var inputs, outputs []IDSet
+	for _, member := range ens.members {
+		in, out := member.Engine.IO()
+		inputs = append(inputs, in...)
+		outputs = append(outputs, out...)
+	}
+	return inputs, outputs
+}
+
+// trace evaluates all members and combines their results
+// For a crisp combination (average or vote), no aggregated set is returned
+func (ens ensemble) trace(input DataInput, agg Aggregation, defuzz Defuzzification) (Trace, error) {
+	traces := make([]Trace, len(ens.members))
+	for i, member := range ens.members {
+		var err error
+		traces[i], err = member.Engine.trace(input)
+		if err != nil {
+			return Trace{}, fmt.Errorf("ensemble: %s: %w", memberName(ens.members, i), err)
+		}
+	}
+
+	switch ens.combination {
+	case combineAggregation:
+		return ens.aggregate(traces, agg, defuzz), nil
+	case combineAverage:
+		return Trace{Output: ens.average(traces, nil)}, nil
+	default: // combineVote
+		return Trace{Output: ens.vote(traces)}, nil
+	}
+}
+
+// aggregate weights and aggregates the result sets of the members, then defuzzifies them
+func (ens ensemble) aggregate(traces []Trace, agg Aggregation, defuzz Defuzzification) Trace {
+	var maxWeight float64
+	for _, member := range ens.members {
+		maxWeight = max(maxWeight, member.Weight)
+	}
+
+	aggregated := make(map[*IDVal]Set)
+	for i, trace := range traces {
+		k := ens.members[i].Weight / maxWeight
+		for idVal, set := range trace.Aggregated {
+			weighted := set.Multiply(k)
+			if current, exists := aggregated[idVal]; exists {
+				weighted = current.aggregate(weighted, agg)
+			}
+			aggregated[idVal] = weighted
+		}
+	}
+
+	return Trace{
+		Output:     newDefuzzer(defuzz, agg).defuzzAggregations(aggregated),
+		Aggregated: aggregated,
+	}
+}
+
+// average computes the weighted average of the crisp results of the members
+// Only the selected members are used for an output (all members if nil)
+func (ens ensemble) average(traces []Trace, selected func(idVal *IDVal, i int) bool) DataOutput {
+	sums := make(map[*IDVal]float64)
+	weights := make(map[*IDVal]float64)
+	for i, trace := range traces {
+		for idVal, value := range trace.Output {
+			if selected != nil && !selected(idVal, i) {
+				continue
+			}
+			sums[idVal] += ens.members[i].Weight * value
+			weights[idVal] += ens.members[i].Weight
+		}
+	}
+
+	result := make(DataOutput, len(sums))
+	for idVal, sum := range sums {
+		result[idVal] = sum / weights[idVal]
+	}
+	return result
+}
+
+// vote elects the output term of highest total weight, then averages the results of its voters
+func (ens ensemble) vote(traces []Trace) DataOutput {
+	// Vote of each member for each output
+	votes := make([]map[*IDVal]int, len(traces))
+	winners := make(map[*IDVal]int)
+	for i, trace := range traces {
+		votes[i] = make(map[*IDVal]int)
+		for idVal, value := range trace.Output {
+			votes[i][idVal] = bestTerm(idVal, value)
+		}
+	}
+	for idVal := range traces[0].Output {
+		scores := make(map[int]float64)
+		for i := range traces {
+			scores[votes[i][idVal]] += ens.members[i].Weight
+		}
+		winner := -1
+		for term, score := range scores {
+			if winner < 0 || score > scores[winner] || (score == scores[winner] && term < winner) {
+				winner = term
+			}
+		}
+		winners[idVal] = winner
+	}
+
+	return ens.average(traces, func(idVal *IDVal, i int) bool {
+		return votes[i][idVal] == winners[idVal]
+	})
+}
+
+// bestTerm returns the position of the term of highest membership, in the order of the terms of the value (see. IDVal.Terms)
+func bestTerm(idVal *IDVal, x float64) int {
+	best, bestValue := 0, -1.0
+	for i, idSet := range idVal.Terms() {
+		if value := idSet.set(x); value > bestValue {
+			best, bestValue = i, value
+		}
+	}
+	return best
+}
@@ -0,0 +1,164 @@
+package fuzzy
+
+import (
+	"testing"
+
+	"github.com/sbiemont/fugologic/crisp"
+	"github.com/sbiemont/fugologic/id"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestEnsemble(t *testing.T) {
+	u, _ := crisp.NewSet(0, 10, 0.1)
+	newVal := func(name id.ID) *IDVal {
+		fv, _ := NewIDValBuilders(name, u, map[id.ID]SetBuilder{
+			"low":  StepDown{A: 0, B: 10},
+			"high": StepUp{A: 0, B: 10},
+		})
+		return fv
+	}
+	fvA, fvB, fvC := newVal("a"), newVal("b"), newVal("c")
+
+	newEngine := func(uuid id.ID, rules ...Rule) Engine {
+		eng, err := NewEngine(rules, AggregationUnion, DefuzzificationCentroid, WithID(uuid))
+		So(err, ShouldBeNil)
+		return eng
+	}
+	evaluate := func(eng Engine, a float64) float64 {
+		out, err := eng.Evaluate(DataInput{fvA: a})
+		So(err, ShouldBeNil)
+		return out[fvB]
+	}
+
+	Convey("ensemble", t, func() {
+		// a => b, and a => not b
+		eng1 := newEngine("direct",
+			NewRule(fvA.Get("low"), ImplicationMin, []IDSet{fvB.Get("low")}),
+			NewRule(fvA.Get("high"), ImplicationMin, []IDSet{fvB.Get("high")}),
+		)
+		eng2 := newEngine("inverse",
+			NewRule(fvA.Get("low"), ImplicationMin, []IDSet{fvB.Get("high")}),
+			NewRule(fvA.Get("high"), ImplicationMin, []IDSet{fvB.Get("low")}),
+		)
+		b1, b2 := evaluate(eng1, 2), evaluate(eng2, 2)
+		So(b1, ShouldBeLessThan, 5)
+		So(b2, ShouldBeGreaterThan, 5)
+
+		Convey("when aggregation", func() {
+			ens, err := NewEnsembleAggregation([]Member{{eng1, 1}, {eng2, 1}}, AggregationUnion, DefuzzificationCentroid)
+			So(err, ShouldBeNil)
+			So(ens.Members(), ShouldHaveLength, 2)
+
+			// Symmetric sets
+			trace, err := ens.Trace(DataInput{fvA: 2})
+			So(err, ShouldBeNil)
+			So(trace.Output[fvB], ShouldAlmostEqual, 5)
+			So(trace.Aggregated[fvB](0), ShouldAlmostEqual, 0.8)
+			So(trace.Aggregated[fvB](10), ShouldAlmostEqual, 0.8)
+
+			// Weighted sets
+			ens, err = NewEnsembleAggregation([]Member{{eng1, 4}, {eng2, 1}}, AggregationUnion, DefuzzificationCentroid)
+			So(err, ShouldBeNil)
+			trace, err = ens.Trace(DataInput{fvA: 2})
+			So(err, ShouldBeNil)
+			So(trace.Output[fvB], ShouldBeLessThan, 5)
+			So(trace.Aggregated[fvB](0), ShouldAlmostEqual, 0.8)
+			So(trace.Aggregated[fvB](10), ShouldAlmostEqual, 0.2)
+		})
+
+		Convey("when average", func() {
+			ens, err := NewEnsembleAverage([]Member{{eng1, 1}, {eng2, 3}})
+			So(err, ShouldBeNil)
+			So(evaluate(ens, 2), ShouldAlmostEqual, (b1+3*b2)/4)
+
+			trace, err := ens.Trace(DataInput{fvA: 2})
+			So(err, ShouldBeNil)
+			So(trace.Aggregated, ShouldBeNil)
+		})
+
+		Convey("when vote", func() {
+			eng3 := newEngine("direct bis",
+				NewRule(fvA.Get("low"), ImplicationMin, []IDSet{fvB.Get("low")}),
+				NewRule(fvA.Get("high"), ImplicationProd, []IDSet{fvB.Get("high")}),
+			)
+			b3 := evaluate(eng3, 2)
+			So(b3, ShouldBeLessThan, 5)
+
+			// Majority: low
+			ens, err := NewEnsembleVote([]Member{{eng1, 1}, {eng2, 1}, {eng3, 1}})
+			So(err, ShouldBeNil)
+			So(evaluate(ens, 2), ShouldAlmostEqual, (b1+b3)/2)
+
+			// Weighted majority: high
+			ens, err = NewEnsembleVote([]Member{{eng1, 1}, {eng2, 3}, {eng3, 1}})
+			So(err, ShouldBeNil)
+			So(evaluate(ens, 2), ShouldAlmostEqual, b2)
+
+			// Tie: first term (high, sorted by identifier)
+			ens, err = NewEnsembleVote([]Member{{eng1, 1}, {eng2, 1}})
+			So(err, ShouldBeNil)
+			So(evaluate(ens, 2), ShouldAlmostEqual, b2)
+
+			// Tie: first term in the declared order (low)
+			fvD, err := NewIDValBuildersOrdered("d", u, []id.ID{"low", "high"}, map[id.ID]SetBuilder{
+				"low":  StepDown{A: 0, B: 10},
+				"high": StepUp{A: 0, B: 10},
+			})
+			So(err, ShouldBeNil)
+			engD1 := newEngine("direct d",
+				NewRule(fvA.Get("low"), ImplicationMin, []IDSet{fvD.Get("low")}),
+				NewRule(fvA.Get("high"), ImplicationMin, []IDSet{fvD.Get("high")}),
+			)
+			engD2 := newEngine("inverse d",
+				NewRule(fvA.Get("low"), ImplicationMin, []IDSet{fvD.Get("high")}),
+				NewRule(fvA.Get("high"), ImplicationMin, []IDSet{fvD.Get("low")}),
+			)
+			d1, err := engD1.Evaluate(DataInput{fvA: 2})
+			So(err, ShouldBeNil)
+			ens, err = NewEnsembleVote([]Member{{engD1, 1}, {engD2, 1}})
+			So(err, ShouldBeNil)
+			out, err := ens.Evaluate(DataInput{fvA: 2})
+			So(err, ShouldBeNil)
+			So(out[fvD], ShouldAlmostEqual, d1[fvD])
+			So(out[fvD], ShouldBeLessThan, 5)
+		})
+
+		Convey("when system node", func() {
+			// ensemble(a => b), b => c
+			hist := NewHistory(3)
+			eng1.history = hist
+			ens, err := NewEnsembleAverage([]Member{{eng1, 1}, {eng2, 1}}, WithID("ensemble"))
+			So(err, ShouldBeNil)
+			eng3 := newEngine("next", NewRule(fvB.Get("high"), ImplicationMin, []IDSet{fvC.Get("high")}))
+			sys, err := NewSystem([]Engine{eng3, ens})
+			So(err, ShouldBeNil)
+			So(sys.Levels(), ShouldHaveLength, 2)
+
+			out, err := sys.Evaluate(DataInput{fvA: 2})
+			So(err, ShouldBeNil)
+			So(out[fvB], ShouldAlmostEqual, (b1+b2)/2)
+			So(out, ShouldContainKey, fvC)
+
+			// The history of the member is recorded
+			So(hist.Values(fvA, 3), ShouldResemble, []float64{2})
+			So(hist.Values(fvB, 3), ShouldResemble, []float64{out[fvB]})
+
+			// Errors of the members
+			_, err = sys.Evaluate(DataInput{})
+			So(err.Error(), ShouldStartWith, "engine `ensemble`: ensemble: engine `direct`: input: cannot find data for id val `a`")
+		})
+
+		Convey("when error", func() {
+			_, err := NewEnsembleAverage(nil)
+			So(err, ShouldBeError, "ensemble: no engine")
+
+			_, err = NewEnsembleVote([]Member{{eng1, 1}, {Engine{}, 0}})
+			So(err, ShouldBeError, "ensemble: engine #1: positive weight expected (found: 0)")
+
+			eng3 := newEngine("other", NewRule(fvA.Get("low"), ImplicationMin, []IDSet{fvC.Get("low")}))
+			_, err = NewEnsembleAggregation([]Member{{eng1, 1}, {eng3, 1}}, AggregationUnion, DefuzzificationCentroid)
+			So(err, ShouldBeError, "ensemble: engine `other`: outputs differ from the first engine")
+		})
+	})
+}
@@ -2,6 +2,8 @@ package fuzzy
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 )
 
 // Premise is an Expression or an IDSet
@@ -19,12 +21,17 @@ type Connector func(a, b float64) float64
 //   - Expression2 = D or E
 //   - Expression3 = Expression1 and Expression2 = (A or B or C) and (D or E)
 type Expression struct {
-	premises   []Premise // List all premises to be connected
-	connect    Connector // Connector to be applied on the premises
-	complement bool      // Complement (false by default)
+	premises   []Premise  // List all premises to be connected
+	connect    Connector  // Connector to be applied on the premises
+	optr       Operator   // Operator of the connector (nil if unknown)
+	connective Connective // Connective of the connector (empty if unknown)
+	hedge      Hedge      // Hedge applied before the complement (nil by default)
+	complement bool       // Complement (false by default)
 }
 
 // NewExpression initialise a fully evaluable expression
+// The connector is opaque: the expression has no operator nor connective, even for a connector of a predefined operator
+// Use NewOperatorExpression to describe or save the expression
 func NewExpression(premises []Premise, connect Connector) Expression {
 	return Expression{
 		premises: premises,
@@ -32,7 +39,18 @@ func NewExpression(premises []Premise, connect Connector) Expression {
 	}
 }
 
+// NewOperatorExpression initialise an expression connected with a connective of an operator
+func NewOperatorExpression(premises []Premise, optr Operator, connective Connective) Expression {
+	return Expression{
+		premises:   premises,
+		connect:    connective.Connector(optr),
+		optr:       optr,
+		connective: connective,
+	}
+}
+
 // Connect the current expression, using the connector and the given premise
+// The connector is opaque (see. NewExpression and ConnectOperator)
 // Returns <new exp> = <exp> <connect> <premise>
 // E.g:    <A and B> = <A>   <and>     <B>
 func (exp Expression) Connect(premise Premise, connect Connector) Expression {
@@ -45,13 +63,56 @@ func (exp Expression) Connect(premise Premise, connect Connector) Expression {
 	return NewExpression([]Premise{exp, premise}, connect)
 }
 
+// ConnectOperator connects the current expression, using a connective of an operator and the given premise
+// See. Connect
+func (exp Expression) ConnectOperator(premise Premise, optr Operator, connective Connective) Expression {
+	if exp.connect == nil {
+		// Direct connection
+		return NewOperatorExpression(append(exp.premises, premise), optr, connective)
+	}
+
+	// Connect both premises in a new expression
+	return NewOperatorExpression([]Premise{exp, premise}, optr, connective)
+}
+
 // Not complements the current expression
 func (exp Expression) Not() Expression {
-	return Expression{
-		premises:   exp.premises,
-		connect:    exp.connect,
-		complement: !exp.complement,
-	}
+	result := exp
+	result.complement = !exp.complement
+	return result
+}
+
+// WithHedge modifies the current expression with a hedge. Eg.: "very (A or B)"
+// The hedge is applied before the complement
+func (exp Expression) WithHedge(hedge Hedge) Expression {
+	result := exp
+	result.hedge = hedge
+	return result
+}
+
+// Premises returns the list of connected premises
+func (exp Expression) Premises() []Premise {
+	return exp.premises
+}
+
+// Operator returns the operator of the connector (nil if unknown)
+func (exp Expression) Operator() Operator {
+	return exp.optr
+}
+
+// Connective returns the connective of the connector (empty if unknown)
+func (exp Expression) Connective() Connective {
+	return exp.connective
+}
+
+// Hedge returns the hedge of the expression (nil if none)
+func (exp Expression) Hedge() Hedge {
+	return exp.hedge
+}
+
+// Complement returns true if the expression is complemented
+func (exp Expression) Complement() bool {
+	return exp.complement
 }
 
 // Evaluate the expression content
@@ -79,10 +140,71 @@ func (exp Expression) Evaluate(input DataInput) (float64, error) {
 		}
 	}
 
-	// Apply complement
+	// Apply hedge and complement
+	if exp.hedge != nil {
+		y = exp.hedge(y)
+	}
 	if exp.complement {
 		y = 1 - y
 	}
 
 	return y, nil
 }
+
+// String describes the expression. Eg.: (a1 AND b1) OR NOT c2
+// Unknown connectors and hedges are written "?"
+func (exp Expression) String() string {
+	text, _ := exp.format()
+	return text
+}
+
+// format describes the expression and returns true if the text is a list of several connected premises
+func (exp Expression) format() (string, bool) {
+	texts := make([]string, len(exp.premises))
+	compound := len(exp.premises) > 1
+	for i, premise := range exp.premises {
+		var text string
+		var subCompound bool
+		if sub, ok := premise.(Expression); ok {
+			text, subCompound = sub.format()
+		} else {
+			text = fmt.Sprint(premise)
+		}
+
+		// A single premise keeps its own status
+		if !compound {
+			return exp.modify(text, subCompound)
+		}
+		if subCompound {
+			text = "(" + text + ")"
+		}
+		texts[i] = text
+	}
+
+	connective := "?"
+	if exp.connective != "" {
+		connective = strings.ToUpper(string(exp.connective))
+	}
+	return exp.modify(strings.Join(texts, " "+connective+" "), compound)
+}
+
+// modify adds the hedge and the complement to the text of the expression
+func (exp Expression) modify(text string, compound bool) (string, bool) {
+	if exp.hedge == nil && !exp.complement {
+		return text, compound
+	}
+	if compound {
+		text = "(" + text + ")"
+	}
+	if exp.hedge != nil {
+		name := exp.hedge.Name()
+		if name == "" {
+			name = "?"
+		}
+		text = strings.ToUpper(name) + " " + text
+	}
+	if exp.complement {
+		text = "NOT " + text
+	}
+	return text, false
+}
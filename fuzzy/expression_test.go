@@ -63,6 +63,25 @@ func TestExpression(t *testing.T) {
 			})
 		})
 
+		Convey("when hedge", func() {
+			exp := NewExpression([]Premise{fsA1}, nil).WithHedge(HedgeVery)
+			result, err := exp.Evaluate(DataInput{
+				fvA: 0.2,
+			})
+			So(err, ShouldBeNil)
+			So(result, ShouldAlmostEqual, 0.16) // (0.2*2)²
+
+			Convey("when hedge and complement", func() {
+				exp := exp.Not()
+				result, err := exp.Evaluate(DataInput{
+					fvA: 0.2,
+				})
+				So(err, ShouldBeNil)
+				So(result, ShouldAlmostEqual, 0.84) // 1-(0.2*2)²
+				So(exp.Hedge().Name(), ShouldEqual, "very")
+			})
+		})
+
 		Convey("when several premises", func() {
 			dataIn := DataInput{
 				fvA: 1,
@@ -192,5 +211,74 @@ func TestExpression(t *testing.T) {
 			So(err, ShouldBeNil)
 			So(result, ShouldEqual, 8) // 2*min(max(min(max(1, 2), 3), 4), 5)
 		})
+
+		Convey("when connect operator", func() {
+			exp := NewExpression([]Premise{fsA1}, nil).
+				ConnectOperator(fsB1, OperatorZadeh{}, ConnectiveOr).
+				ConnectOperator(fsC1, OperatorHyperbolic{}, ConnectiveAnd)
+			result, err := exp.Evaluate(DataInput{
+				fvA: 0.1,
+				fvB: 0.2,
+				fvC: 0.25,
+			})
+			So(err, ShouldBeNil)
+			So(result, ShouldAlmostEqual, 0.2) // 2*max(0.1, 0.2) * 2*0.25
+			So(exp.Operator(), ShouldEqual, OperatorHyperbolic{})
+			So(exp.Connective(), ShouldEqual, ConnectiveAnd)
+			So(exp.Premises(), ShouldHaveLength, 2)
+			So(exp.Premises()[0].(Expression).Connective(), ShouldEqual, ConnectiveOr)
+		})
+	})
+
+	Convey("accessors", t, func() {
+		Convey("when operator expression", func() {
+			exp := NewOperatorExpression([]Premise{fsA1, fsB1}, OperatorZadeh{}, ConnectiveOr)
+			So(exp.Premises(), ShouldHaveLength, 2)
+			So(exp.Premises()[0].(IDSet).ID(), ShouldEqual, fsA1.ID())
+			So(exp.Premises()[1].(IDSet).ID(), ShouldEqual, fsB1.ID())
+			So(exp.Operator(), ShouldEqual, OperatorZadeh{})
+			So(exp.Connective(), ShouldEqual, ConnectiveOr)
+			So(exp.Complement(), ShouldBeFalse)
+
+			not := exp.Not()
+			So(not.Operator(), ShouldEqual, OperatorZadeh{})
+			So(not.Connective(), ShouldEqual, ConnectiveOr)
+			So(not.Complement(), ShouldBeTrue)
+		})
+
+		Convey("when predefined connector", func() {
+			// The connector is opaque, whatever the way it is given
+			var optr Operator = OperatorHyperbolic{}
+			for _, connect := range []Connector{OperatorHyperbolic{}.Or, optr.Or} {
+				exp := NewExpression([]Premise{fsA1, fsB1}, connect)
+				So(exp.Operator(), ShouldBeNil)
+				So(exp.Connective(), ShouldBeEmpty)
+				So(exp.String(), ShouldEqual, "a1 ? b1")
+			}
+		})
+
+		Convey("when custom connector", func() {
+			exp := NewExpression([]Premise{fsA1, fsB1}, math.Max)
+			So(exp.Operator(), ShouldBeNil)
+			So(exp.Connective(), ShouldBeEmpty)
+		})
+	})
+
+	Convey("string", t, func() {
+		optr := OperatorZadeh{}
+		and := func(premises ...Premise) Expression { return NewOperatorExpression(premises, optr, ConnectiveAnd) }
+		or := func(premises ...Premise) Expression { return NewOperatorExpression(premises, optr, ConnectiveOr) }
+		single := func(premise Premise) Expression { return NewExpression([]Premise{premise}, nil) }
+
+		So(single(fsA1).String(), ShouldEqual, "a1")
+		So(and(fsA1, fsB1, fsC1).String(), ShouldEqual, "a1 AND b1 AND c1")
+		So(or(and(fsA1, fsB1), single(fsC1).Not()).String(), ShouldEqual, "(a1 AND b1) OR NOT c1")
+		So(and(fsA1, or(fsB1, fsC1)).Not().String(), ShouldEqual, "NOT (a1 AND (b1 OR c1))")
+		So(and(single(or(fsA1, fsB1)), fsC1).String(), ShouldEqual, "(a1 OR b1) AND c1")
+		So(or(fsA1, single(fsB1).WithHedge(HedgeVery).Not()).String(), ShouldEqual, "a1 OR NOT VERY b1")
+		So(or(fsA1, fsB1).WithHedge(HedgeSomewhat).String(), ShouldEqual, "SOMEWHAT (a1 OR b1)")
+		So(NewOperatorExpression([]Premise{fsD1, fsE1}, optr, ConnectiveXOr).String(), ShouldEqual, "d1 XOR e1")
+		So(NewExpression([]Premise{fsA1, fsB1}, math.Max).String(), ShouldEqual, "a1 ? b1")
+		So(single(fsA1).WithHedge(func(y float64) float64 { return y }).String(), ShouldEqual, "? a1")
 	})
 }
@@ -0,0 +1,35 @@
+package fuzzy
+
+import "math"
+
+// Hedge modifies the result of a premise. Eg.: "very hot"
+type Hedge func(y float64) float64
+
+var (
+	// HedgeVery concentrates the result (y²)
+	HedgeVery Hedge = func(y float64) float64 { return y * y }
+
+	// HedgeSomewhat dilates the result (√y)
+	HedgeSomewhat Hedge = math.Sqrt
+
+	// HedgeExtremely strongly concentrates the result (y³)
+	HedgeExtremely Hedge = func(y float64) float64 { return y * y * y }
+)
+
+// hedges lists the predefined hedges by name
+var hedges = map[string]Hedge{
+	"very":      HedgeVery,
+	"somewhat":  HedgeSomewhat,
+	"extremely": HedgeExtremely,
+}
+
+// HedgeByName returns the predefined hedge matching the name
+func HedgeByName(name string) (Hedge, bool) {
+	hedge, ok := hedges[name]
+	return hedge, ok
+}
+
+// Name returns the name of a predefined hedge, or an empty string for a custom one
+func (hedge Hedge) Name() string {
+	return nameOf(hedge, hedges)
+}
@@ -0,0 +1,28 @@
+package fuzzy
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestHedge(t *testing.T) {
+	Convey("hedges", t, func() {
+		So(HedgeVery(0.5), ShouldEqual, 0.25)
+		So(HedgeSomewhat(0.25), ShouldEqual, 0.5)
+		So(HedgeExtremely(0.5), ShouldEqual, 0.125)
+	})
+
+	Convey("hedge names", t, func() {
+		for _, name := range []string{"very", "somewhat", "extremely"} {
+			hedge, ok := HedgeByName(name)
+			So(ok, ShouldBeTrue)
+			So(hedge.Name(), ShouldEqual, name)
+		}
+
+		_, ok := HedgeByName("unknown")
+		So(ok, ShouldBeFalse)
+		So(Hedge(func(y float64) float64 { return y }).Name(), ShouldBeEmpty)
+		So(Hedge(nil).Name(), ShouldBeEmpty)
+	})
+}
@@ -0,0 +1,167 @@
+package fuzzy
+
+import (
+	"fmt"
+	"sync"
+)
+
+// History keeps the last values of the fuzzy values, it is the evaluation context of temporal premises
+// An engine using a history records its inputs before each evaluation (see WithHistory)
+// A system records its inputs and the outputs of its engines once per evaluation
+type History struct {
+	size   int
+	mu     sync.RWMutex
+	values map[*IDVal][]float64 // last values, the oldest first
+}
+
+// NewHistory creates an empty history keeping at most "size" values for each fuzzy value
+func NewHistory(size int) *History {
+	return &History{
+		size:   max(size, 1),
+		values: make(map[*IDVal][]float64),
+	}
+}
+
+// Record appends the values of the input
+func (hist *History) Record(input DataInput) {
+	hist.mu.Lock()
+	defer hist.mu.Unlock()
+	for idVal, value := range input {
+		values := append(hist.values[idVal], value)
+		if len(values) > hist.size {
+			values = values[len(values)-hist.size:]
+		}
+		hist.values[idVal] = values
+	}
+}
+
+// Values returns a copy of the last n recorded values (or less if not available) of a fuzzy value, the oldest first
+func (hist *History) Values(idVal *IDVal, n int) []float64 {
+	hist.mu.RLock()
+	defer hist.mu.RUnlock()
+	values := hist.values[idVal]
+	return append([]float64{}, values[max(len(values)-n, 0):]...)
+}
+
+// Reset removes all recorded values
+func (hist *History) Reset() {
+	hist.mu.Lock()
+	defer hist.mu.Unlock()
+	hist.values = make(map[*IDVal][]float64)
+}
+
+// temporalKind defines how the recorded values are evaluated
+type temporalKind string
+
+const (
+	temporalDuring  temporalKind = "DURING"
+	temporalTrend   temporalKind = "TREND"
+	temporalMin     temporalKind = "MIN"
+	temporalMax     temporalKind = "MAX"
+	temporalAverage temporalKind = "AVERAGE"
+)
+
+// Temporal is a premise evaluated over the last values of a fuzzy value
+type Temporal struct {
+	hist   *History
+	kind   temporalKind
+	idSet  IDSet // term applied (its parent is the fuzzy value whose values are recorded)
+	window int   // number of values (or of steps for a trend)
+}
+
+// During checks that the value has been in the term for the last n values (minimum of the memberships)
+// Evaluates to 0 if less than n values have been recorded
+func (hist *History) During(idSet IDSet, n int) Temporal {
+	return Temporal{hist: hist, kind: temporalDuring, idSet: idSet, window: max(n, 1)}
+}
+
+// Trend applies a term describing a variation (eg.: "rising") to the slope of the value over the last n steps
+// The slope is the mean variation per recorded value, it is 0 if only one value has been recorded
+func (hist *History) Trend(idVal *IDVal, trend IDSet, n int) Temporal {
+	// The term is attached to the recorded value
+	idSet := IDSet{set: trend.set, uuid: trend.uuid, parent: idVal, builder: trend.builder}
+	return Temporal{hist: hist, kind: temporalTrend, idSet: idSet, window: max(n, 1)}
+}
+
+// Min applies the term to the minimum of the last n values (or less if not available)
+func (hist *History) Min(idSet IDSet, n int) Temporal {
+	return Temporal{hist: hist, kind: temporalMin, idSet: idSet, window: max(n, 1)}
+}
+
+// Max applies the term to the maximum of the last n values (or less if not available)
+func (hist *History) Max(idSet IDSet, n int) Temporal {
+	return Temporal{hist: hist, kind: temporalMax, idSet: idSet, window: max(n, 1)}
+}
+
+// Average applies the term to the average of the last n values (or less if not available)
+func (hist *History) Average(idSet IDSet, n int) Temporal {
+	return Temporal{hist: hist, kind: temporalAverage, idSet: idSet, window: max(n, 1)}
+}
+
+// IDSet returns the term applied, its parent is the fuzzy value whose values are recorded
+func (tmp Temporal) IDSet() IDSet {
+	return tmp.idSet
+}
+
+// Window returns the number of values (or of steps for a trend) used
+func (tmp Temporal) Window() int {
+	return tmp.window
+}
+
+// Evaluate computes the membership of the recorded values
+// The input is not used: the values shall have been recorded in the history before
+func (tmp Temporal) Evaluate(DataInput) (float64, error) {
+	if tmp.idSet.parent == nil {
+		return 0, fmt.Errorf("history: cannot find parent for id set `%s`", tmp.idSet.uuid)
+	}
+	n := tmp.window
+	if tmp.kind == temporalTrend {
+		n++
+	}
+	values := tmp.hist.Values(tmp.idSet.parent, n)
+	if len(values) == 0 {
+		return 0, fmt.Errorf("history: no data for id val `%s` (id set `%s`)", tmp.idSet.parent.uuid, tmp.idSet.uuid)
+	}
+
+	switch tmp.kind {
+	case temporalDuring:
+		if len(values) < n {
+			return 0, nil
+		}
+		result := 1.0
+		for _, value := range values {
+			result = min(result, tmp.idSet.set(value))
+		}
+		return result, nil
+
+	case temporalTrend:
+		var slope float64
+		if len(values) > 1 {
+			slope = (values[len(values)-1] - values[0]) / float64(len(values)-1)
+		}
+		return tmp.idSet.set(slope), nil
+
+	case temporalMin, temporalMax:
+		x := values[0]
+		for _, value := range values[1:] {
+			if tmp.kind == temporalMin {
+				x = min(x, value)
+			} else {
+				x = max(x, value)
+			}
+		}
+		return tmp.idSet.set(x), nil
+
+	default: // temporalAverage
+		var sum float64
+		for _, value := range values {
+			sum += value
+		}
+		return tmp.idSet.set(sum / float64(len(values))), nil
+	}
+}
+
+// String describes the premise. Eg.: DURING(high, 5)
+func (tmp Temporal) String() string {
+	return fmt.Sprintf("%s(%s, %d)", tmp.kind, tmp.idSet.uuid, tmp.window)
+}
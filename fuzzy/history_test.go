@@ -0,0 +1,172 @@
+package fuzzy
+
+import (
+	"testing"
+
+	"github.com/sbiemont/fugologic/crisp"
+	"github.com/sbiemont/fugologic/id"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestHistory(t *testing.T) {
+	fvA, _ := newTestVal("a", "a1")
+	fvB, _ := newTestVal("b", "b1")
+
+	Convey("history", t, func() {
+		hist := NewHistory(3)
+		So(hist.Values(fvA, 3), ShouldBeEmpty)
+
+		hist.Record(DataInput{fvA: 1})
+		hist.Record(DataInput{fvA: 2, fvB: 10})
+		So(hist.Values(fvA, 3), ShouldResemble, []float64{1, 2})
+		So(hist.Values(fvB, 3), ShouldResemble, []float64{10})
+
+		// Keep the last values
+		hist.Record(DataInput{fvA: 3})
+		hist.Record(DataInput{fvA: 4})
+		So(hist.Values(fvA, 5), ShouldResemble, []float64{2, 3, 4})
+		So(hist.Values(fvA, 2), ShouldResemble, []float64{3, 4})
+
+		// Copy
+		values := hist.Values(fvA, 1)
+		values[0] = 0
+		So(hist.Values(fvA, 1), ShouldResemble, []float64{4})
+
+		hist.Reset()
+		So(hist.Values(fvA, 3), ShouldBeEmpty)
+	})
+}
+
+func TestTemporal(t *testing.T) {
+	u, _ := crisp.NewSet(0, 10, 1)
+	fvTemp, _ := NewIDValBuilders("temp", u, map[id.ID]SetBuilder{
+		"low":  StepDown{A: 0, B: 10},
+		"high": StepUp{A: 0, B: 10},
+	})
+	uTrend, _ := crisp.NewSet(-1, 1, 0.1)
+	fvTrend, _ := NewIDValBuilders("trend", uTrend, map[id.ID]SetBuilder{
+		"falling": StepDown{A: -1, B: 0},
+		"rising":  StepUp{A: 0, B: 1},
+	})
+	fvAlarm, _ := NewIDValBuilders("alarm", u, map[id.ID]SetBuilder{
+		"off": StepDown{A: 0, B: 10},
+		"on":  StepUp{A: 0, B: 10},
+	})
+
+	Convey("temporal", t, func() {
+		hist := NewHistory(5)
+		evaluate := func(premise Premise) float64 {
+			y, err := premise.Evaluate(nil)
+			So(err, ShouldBeNil)
+			return y
+		}
+
+		Convey("when evaluate", func() {
+			for _, value := range []float64{4, 8, 6} {
+				hist.Record(DataInput{fvTemp: value})
+			}
+
+			So(evaluate(hist.During(fvTemp.Get("high"), 3)), ShouldAlmostEqual, 0.4)
+			So(evaluate(hist.During(fvTemp.Get("high"), 2)), ShouldAlmostEqual, 0.6)
+			So(evaluate(hist.During(fvTemp.Get("high"), 4)), ShouldEqual, 0) // not enough values
+			So(evaluate(hist.Min(fvTemp.Get("high"), 3)), ShouldAlmostEqual, 0.4)
+			So(evaluate(hist.Max(fvTemp.Get("high"), 2)), ShouldAlmostEqual, 0.8)
+			So(evaluate(hist.Max(fvTemp.Get("high"), 10)), ShouldAlmostEqual, 0.8)
+			So(evaluate(hist.Average(fvTemp.Get("high"), 3)), ShouldAlmostEqual, 0.6)
+			So(evaluate(hist.Average(fvTemp.Get("low"), 1)), ShouldAlmostEqual, 0.4)
+
+			// Slope over 2 steps: (6 - 4) / 2
+			So(evaluate(hist.Trend(fvTemp, fvTrend.Get("rising"), 2)), ShouldAlmostEqual, 1)
+			So(evaluate(hist.Trend(fvTemp, fvTrend.Get("falling"), 1)), ShouldAlmostEqual, 1)
+			So(evaluate(hist.Trend(fvTemp, fvTrend.Get("rising"), 1)), ShouldEqual, 0)
+		})
+
+		Convey("when trend with one value", func() {
+			hist.Record(DataInput{fvTemp: 4})
+			So(evaluate(hist.Trend(fvTemp, fvTrend.Get("rising"), 3)), ShouldEqual, 0)
+		})
+
+		Convey("when no data", func() {
+			_, err := hist.During(fvTemp.Get("high"), 3).Evaluate(DataInput{fvTemp: 1})
+			So(err, ShouldBeError, "history: no data for id val `temp` (id set `high`)")
+
+			_, err = hist.Average(IDSet{uuid: "x"}, 3).Evaluate(nil)
+			So(err, ShouldBeError, "history: cannot find parent for id set `x`")
+		})
+
+		Convey("when description", func() {
+			So(hist.During(fvTemp.Get("high"), 3).String(), ShouldEqual, "DURING(high, 3)")
+			So(hist.Trend(fvTemp, fvTrend.Get("rising"), 2).String(), ShouldEqual, "TREND(rising, 2)")
+			So(hist.Average(fvTemp.Get("low"), 4).String(), ShouldEqual, "AVERAGE(low, 4)")
+
+			rule := NewRule(NewOperatorExpression([]Premise{hist.During(fvTemp.Get("high"), 3), fvTemp.Get("high")}, OperatorZadeh{}, ConnectiveAnd), ImplicationMin, []IDSet{fvAlarm.Get("on")})
+			So(rule.String(), ShouldEqual, "IF DURING(high, 3) AND high THEN on")
+
+			// The recorded value is an input of the rule
+			inputs, _ := rule.IO()
+			So(inputs[0].Parent(), ShouldEqual, fvTemp)
+			inputs, _ = NewRule(hist.Trend(fvTemp, fvTrend.Get("rising"), 2), ImplicationMin, []IDSet{fvAlarm.Get("on")}).IO()
+			So(inputs[0].Parent(), ShouldEqual, fvTemp)
+			So(inputs[0].ID(), ShouldEqual, id.ID("rising"))
+		})
+
+		Convey("when engine", func() {
+			eng, err := NewEngine([]Rule{
+				NewRule(hist.During(fvTemp.Get("high"), 3), ImplicationMin, []IDSet{fvAlarm.Get("on")}),
+				NewRule(NewExpression([]Premise{hist.During(fvTemp.Get("high"), 3)}, OperatorZadeh{}.And).Not(), ImplicationMin, []IDSet{fvAlarm.Get("off")}),
+			}, AggregationUnion, DefuzzificationCentroid, WithHistory(hist))
+			So(err, ShouldBeNil)
+			So(eng.History(), ShouldEqual, hist)
+
+			var alarms []float64
+			for _, value := range []float64{10, 10, 10, 10} {
+				out, err := eng.Evaluate(DataInput{fvTemp: value})
+				So(err, ShouldBeNil)
+				alarms = append(alarms, out[fvAlarm])
+			}
+			So(alarms[0], ShouldEqual, alarms[1])
+			So(alarms[1], ShouldBeLessThan, alarms[2])
+			So(alarms[2], ShouldEqual, alarms[3])
+			So(hist.Values(fvTemp, 5), ShouldResemble, []float64{10, 10, 10, 10})
+
+			// Reset
+			hist.Reset()
+			out, err := eng.Evaluate(DataInput{fvTemp: 10})
+			So(err, ShouldBeNil)
+			So(out[fvAlarm], ShouldEqual, alarms[0])
+
+			// Trace also records the input
+			_, err = eng.Trace(DataInput{fvTemp: 10})
+			So(err, ShouldBeNil)
+			So(hist.Values(fvTemp, 5), ShouldHaveLength, 2)
+		})
+
+		Convey("when system", func() {
+			// temp => alarm, alarm was on => temp2
+			fvTemp2, _ := NewIDValBuilders("temp2", u, map[id.ID]SetBuilder{"high": StepUp{A: 0, B: 10}})
+			eng1, err := NewEngine([]Rule{
+				NewRule(fvTemp.Get("high"), ImplicationMin, []IDSet{fvAlarm.Get("on")}),
+			}, AggregationUnion, DefuzzificationCentroid, WithHistory(hist))
+			So(err, ShouldBeNil)
+			eng2, err := NewEngine([]Rule{
+				NewRule(hist.Max(fvAlarm.Get("on"), 2), ImplicationMin, []IDSet{fvTemp2.Get("high")}),
+			}, AggregationUnion, DefuzzificationCentroid, WithHistory(hist))
+			So(err, ShouldBeNil)
+			sys, err := NewSystem([]Engine{eng2, eng1})
+			So(err, ShouldBeNil)
+
+			output, err := sys.Evaluate(DataInput{fvTemp: 5})
+			So(err, ShouldBeNil)
+
+			// Recorded once, outputs included
+			So(hist.Values(fvTemp, 5), ShouldResemble, []float64{5})
+			So(hist.Values(fvAlarm, 5), ShouldResemble, []float64{output[fvAlarm]})
+			So(hist.Values(fvTemp2, 5), ShouldResemble, []float64{output[fvTemp2]})
+
+			_, err = sys.Trace(DataInput{fvTemp: 6})
+			So(err, ShouldBeNil)
+			So(hist.Values(fvTemp, 5), ShouldResemble, []float64{5, 6})
+		})
+	})
+}
@@ -0,0 +1,174 @@
+package fuzzy
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Inputs returns the external inputs of the system (sorted by id)
+// An input is external if it is not produced by an engine placed before the engine using it
+func (sys System) Inputs() []*IDVal {
+	inputs, _, _ := sys.introspect()
+	return inputs
+}
+
+// Variables returns the internal variables of the system (sorted by id): values produced by an engine and used by a next one
+func (sys System) Variables() []*IDVal {
+	_, variables, _ := sys.introspect()
+	return variables
+}
+
+// Outputs returns the final outputs of the system (sorted by id): values produced by an engine and not used by a next one
+func (sys System) Outputs() []*IDVal {
+	_, _, outputs := sys.introspect()
+	return outputs
+}
+
+// Produced returns all values produced by the engines of the system (sorted by id): internal variables and final outputs
+func (sys System) Produced() []*IDVal {
+	produced := make(map[*IDVal]struct{})
+	for _, eng := range sys {
+		_, out := eng.IO()
+		for idVal := range IDSets(out).IDVals() {
+			produced[idVal] = struct{}{}
+		}
+	}
+	return sortedIDVals(produced)
+}
+
+// introspect splits the values of the system into external inputs, internal variables and final outputs
+func (sys System) introspect() ([]*IDVal, []*IDVal, []*IDVal) {
+	producers := make(map[*IDVal]int)
+	for pos, eng := range sys {
+		_, out := eng.IO()
+		for idVal := range IDSets(out).IDVals() {
+			producers[idVal] = pos
+		}
+	}
+
+	inputs := make(map[*IDVal]struct{})
+	variables := make(map[*IDVal]struct{})
+	for pos, eng := range sys {
+		in, _ := eng.IO()
+		for idVal := range IDSets(in).IDVals() {
+			if producer, exists := producers[idVal]; exists && producer < pos {
+				variables[idVal] = struct{}{}
+			} else {
+				inputs[idVal] = struct{}{}
+			}
+		}
+	}
+
+	outputs := make(map[*IDVal]struct{})
+	for idVal := range producers {
+		if _, exists := variables[idVal]; !exists {
+			outputs[idVal] = struct{}{}
+		}
+	}
+	return sortedIDVals(inputs), sortedIDVals(variables), sortedIDVals(outputs)
+}
+
+// sortedIDVals returns the values sorted by id
+func sortedIDVals(idVals map[*IDVal]struct{}) []*IDVal {
+	result := make([]*IDVal, 0, len(idVals))
+	for idVal := range idVals {
+		result = append(result, idVal)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].uuid < result[j].uuid
+	})
+	return result
+}
+
+// computed returns the values computed by the system, with the position of the producing engine
+// A value produced by an engine is computed if it is not used by this engine nor by a previous one (else it is an input)
+func (sys System) computed() map[*IDVal]int {
+	result := make(map[*IDVal]int)
+	used := make(map[*IDVal]struct{})
+	for pos, eng := range sys {
+		in, out := eng.IO()
+		for _, idSet := range in {
+			used[idSet.parent] = struct{}{}
+		}
+		for _, idSet := range out {
+			if _, isInput := used[idSet.parent]; !isInput {
+				result[idSet.parent] = pos
+			}
+		}
+	}
+	return result
+}
+
+// checkInput controls that the input does not define a value computed by the system (see. Pin)
+// The first engine producing such a value is reported
+func (sys System) checkInput(input DataInput) error {
+	computed := sys.computed()
+	var found *IDVal
+	for idVal := range input {
+		pos, isComputed := computed[idVal]
+		if !isComputed {
+			continue
+		}
+		if found == nil || pos < computed[found] || (pos == computed[found] && idVal.uuid < found.uuid) {
+			found = idVal
+		}
+	}
+	if found != nil {
+		return fmt.Errorf("input: value `%s` is produced by %s (pin it to force its value)", found.uuid, sys.engineName(computed[found]))
+	}
+	return nil
+}
+
+// EvaluationOption sets an optional behaviour of a system evaluation
+type EvaluationOption func(pinned DataInput)
+
+// Pin forces a value produced by the system instead of computing it
+// An engine is not evaluated if all its outputs are pinned, and the next engines use the pinned value
+func Pin(idVal *IDVal, value float64) EvaluationOption {
+	return func(pinned DataInput) {
+		pinned[idVal] = value
+	}
+}
+
+// EvaluateWith evaluates all engines like Evaluate, using the options (see. Pin)
+func (sys System) EvaluateWith(input DataInput, opts ...EvaluationOption) (DataOutput, error) {
+	if err := sys.checkInput(input); err != nil {
+		return nil, err
+	}
+	pinned := DataInput{}
+	for _, opt := range opts {
+		opt(pinned)
+	}
+
+	// Check the pinned values and skip the engines whose outputs are all pinned
+	produced := make(map[*IDVal]struct{})
+	skipped := make([]bool, len(sys))
+	for pos, eng := range sys {
+		_, out := eng.IO()
+		skipped[pos] = true
+		for idVal := range IDSets(out).IDVals() {
+			produced[idVal] = struct{}{}
+			if _, isPinned := pinned[idVal]; !isPinned {
+				skipped[pos] = false
+			}
+		}
+	}
+	delayed := make(map[*IDVal]struct{}, len(pinned))
+	for idVal := range pinned {
+		if idVal == nil {
+			return nil, fmt.Errorf("pin: nil value")
+		}
+		if _, exists := produced[idVal]; !exists {
+			return nil, fmt.Errorf("pin: value `%s` is not produced by the system", idVal.uuid)
+		}
+		delayed[idVal] = struct{}{}
+	}
+
+	// The pinned values are kept during the whole evaluation, like delayed values
+	levels := sys.levelsOf(func(pos int) bool { return !skipped[pos] })
+	trace, err := sys.run(input.merge(DataOutput(pinned)), levels, delayed, evaluateEngine)
+	if err != nil {
+		return nil, err
+	}
+	return trace.Output.merge(DataOutput(pinned)), nil
+}
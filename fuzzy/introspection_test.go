@@ -0,0 +1,90 @@
+package fuzzy
+
+import (
+	"testing"
+
+	"github.com/sbiemont/fugologic/crisp"
+	"github.com/sbiemont/fugologic/id"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestIntrospection(t *testing.T) {
+	fvA, fsA1 := newTestVal("a", "a1")
+	fvB, fsB1 := newTestVal("b", "b1")
+	fvC, fsC1 := newTestVal("c", "c1")
+	fvD, fsD1 := newTestVal("d", "d1")
+	fvE, fsE1 := newTestVal("e", "e1")
+	fvF, fsF1 := newTestVal("f", "f1")
+	fvG, fsG1 := newTestVal("g", "g1")
+
+	// Membership of the result set at x=1: the outputs are the minimum of the premises
+	defuzz := func(fs Set, _ crisp.Set) float64 { return fs(1) }
+	and := func(premises ...Premise) Expression {
+		return NewOperatorExpression(premises, OperatorZadeh{}, ConnectiveAnd)
+	}
+	so := ImplicationMin
+	newEngine := func(rule Rule) Engine {
+		eng, err := NewEngine([]Rule{rule}, AggregationUnion, defuzz, WithID(id.ID(rule.String())))
+		So(err, ShouldBeNil)
+		return eng
+	}
+
+	Convey("introspection", t, func() {
+		// A and B => C ; D => E, F ; C and E => G
+		sys, err := NewSystem([]Engine{
+			newEngine(NewRule(and(fsC1, fsE1), so, []IDSet{fsG1})),
+			newEngine(NewRule(NewOperatorExpression([]Premise{fsA1, fsB1}, OperatorZadeh{}, ConnectiveAnd), so, []IDSet{fsC1})),
+			newEngine(NewRule(fsD1, so, []IDSet{fsE1, fsF1})),
+		})
+		So(err, ShouldBeNil)
+
+		Convey("when values", func() {
+			So(sys.Inputs(), ShouldResemble, []*IDVal{fvA, fvB, fvD})
+			So(sys.Variables(), ShouldResemble, []*IDVal{fvC, fvE})
+			So(sys.Outputs(), ShouldResemble, []*IDVal{fvF, fvG})
+			So(sys.Produced(), ShouldResemble, []*IDVal{fvC, fvE, fvF, fvG})
+
+			// Computed once, copies returned
+			So(sys.computed(), ShouldResemble, map[*IDVal]int{fvC: 0, fvE: 1, fvF: 1, fvG: 2})
+			sys.Inputs()[0] = fvG
+			So(sys.Inputs(), ShouldResemble, []*IDVal{fvA, fvB, fvD})
+		})
+
+		Convey("when pin", func() {
+			output, err := sys.Evaluate(DataInput{fvA: 0.9, fvB: 0.8, fvD: 0.5})
+			So(err, ShouldBeNil)
+			So(output, ShouldResemble, DataOutput{fvC: 0.8, fvE: 0.5, fvF: 0.5, fvG: 0.5})
+
+			// All outputs of the engine are pinned: a and b are not required
+			output, err = sys.EvaluateWith(DataInput{fvD: 0.5}, Pin(fvC, 0.3))
+			So(err, ShouldBeNil)
+			So(output, ShouldResemble, DataOutput{fvC: 0.3, fvE: 0.5, fvF: 0.5, fvG: 0.3})
+
+			// Only one output of the engine is pinned
+			output, err = sys.EvaluateWith(DataInput{fvA: 0.9, fvB: 0.8, fvD: 0.5}, Pin(fvE, 0.2))
+			So(err, ShouldBeNil)
+			So(output, ShouldResemble, DataOutput{fvC: 0.8, fvE: 0.2, fvF: 0.5, fvG: 0.2})
+
+			// No pin
+			output, err = sys.EvaluateWith(DataInput{fvA: 0.9, fvB: 0.8, fvD: 0.5})
+			So(err, ShouldBeNil)
+			So(output[fvG], ShouldEqual, 0.5)
+		})
+
+		Convey("when error", func() {
+			_, err := sys.Evaluate(DataInput{fvA: 0.9, fvB: 0.8, fvC: 0.3, fvD: 0.5})
+			So(err, ShouldBeError, "input: value `c` is produced by engine `IF a1 AND b1 THEN c1` (pin it to force its value)")
+
+			// The first producing engine is reported
+			_, err = sys.Evaluate(DataInput{fvD: 0.5, fvG: 0.1, fvF: 0.2, fvE: 0.3})
+			So(err, ShouldBeError, "input: value `e` is produced by engine `IF d1 THEN e1, f1` (pin it to force its value)")
+
+			_, err = sys.EvaluateWith(DataInput{fvD: 0.5}, Pin(fvA, 0.3))
+			So(err, ShouldBeError, "pin: value `a` is not produced by the system")
+
+			_, err = sys.EvaluateWith(DataInput{fvD: 0.5}, Pin(nil, 0.3))
+			So(err, ShouldBeError, "pin: nil value")
+		})
+	})
+}
@@ -0,0 +1,28 @@
+package fuzzy
+
+import "reflect"
+
+// funcPointer returns the code pointer of a function (false if the function is nil)
+func funcPointer(fct any) (uintptr, bool) {
+	value := reflect.ValueOf(fct)
+	if value.Kind() != reflect.Func || value.IsNil() {
+		return 0, false
+	}
+	return value.Pointer(), true
+}
+
+// nameOf returns the name of a predefined function, or an empty string if the function is unknown
+// Functions are compared using their code pointer
+func nameOf[F any](fct F, known map[string]F) string {
+	ptr, ok := funcPointer(fct)
+	if !ok {
+		return ""
+	}
+
+	for name, k := range known {
+		if kPtr, _ := funcPointer(k); kPtr == ptr {
+			return name
+		}
+	}
+	return ""
+}
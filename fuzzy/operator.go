@@ -1,6 +1,9 @@
 package fuzzy
 
-import "math"
+import (
+	"math"
+	"reflect"
+)
 
 // Operator defines the connectors for a predefined family
 // https://commons.wikimedia.org/wiki/Fuzzy_operator
@@ -24,3 +27,52 @@ type OperatorHyperbolic struct{}
 func (OperatorHyperbolic) And(a, b float64) float64 { return a * b }
 func (OperatorHyperbolic) Or(a, b float64) float64  { return a + b - a*b }
 func (OperatorHyperbolic) XOr(a, b float64) float64 { return a + b - 2*a*b }
+
+// operators lists the predefined operators by name
+var operators = map[string]Operator{
+	"zadeh":      OperatorZadeh{},
+	"hyperbolic": OperatorHyperbolic{},
+}
+
+// OperatorByName returns the predefined operator matching the name
+func OperatorByName(name string) (Operator, bool) {
+	optr, ok := operators[name]
+	return optr, ok
+}
+
+// OperatorName returns the name of a predefined operator, or an empty string for a custom one
+func OperatorName(optr Operator) string {
+	if optr == nil {
+		return ""
+	}
+	for name, known := range operators {
+		if reflect.TypeOf(optr) == reflect.TypeOf(known) {
+			return name
+		}
+	}
+	return ""
+}
+
+// Connective identifies a connector of an Operator
+type Connective string
+
+const (
+	ConnectiveAnd Connective = "and"
+	ConnectiveOr  Connective = "or"
+	ConnectiveXOr Connective = "xor"
+)
+
+// Connector returns the connector of the operator matching the connective
+// Returns nil for an unknown connective
+func (cnt Connective) Connector(optr Operator) Connector {
+	switch cnt {
+	case ConnectiveAnd:
+		return optr.And
+	case ConnectiveOr:
+		return optr.Or
+	case ConnectiveXOr:
+		return optr.XOr
+	default:
+		return nil
+	}
+}
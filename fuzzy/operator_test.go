@@ -19,3 +19,31 @@ func TestOperator(t *testing.T) {
 		So(OperatorHyperbolic{}.XOr(42, 43), ShouldEqual, -3527) // 42+43-2*42*43
 	})
 }
+
+func TestOperatorNames(t *testing.T) {
+	Convey("operator by name", t, func() {
+		optr, ok := OperatorByName("zadeh")
+		So(ok, ShouldBeTrue)
+		So(optr, ShouldEqual, OperatorZadeh{})
+
+		optr, ok = OperatorByName("hyperbolic")
+		So(ok, ShouldBeTrue)
+		So(optr, ShouldEqual, OperatorHyperbolic{})
+
+		_, ok = OperatorByName("unknown")
+		So(ok, ShouldBeFalse)
+	})
+
+	Convey("operator name", t, func() {
+		So(OperatorName(OperatorZadeh{}), ShouldEqual, "zadeh")
+		So(OperatorName(OperatorHyperbolic{}), ShouldEqual, "hyperbolic")
+		So(OperatorName(nil), ShouldBeEmpty)
+	})
+
+	Convey("connective", t, func() {
+		So(ConnectiveAnd.Connector(OperatorHyperbolic{})(2, 3), ShouldEqual, 6)
+		So(ConnectiveOr.Connector(OperatorZadeh{})(2, 3), ShouldEqual, 3)
+		So(ConnectiveXOr.Connector(OperatorZadeh{})(2, 3), ShouldEqual, 1)
+		So(Connective("unknown").Connector(OperatorZadeh{}), ShouldBeNil)
+	})
+}
@@ -0,0 +1,111 @@
+package fuzzy
+
+import (
+	"errors"
+	"fmt"
+)
+
+// EvaluateOutputs only evaluates the engines needed to compute the wanted outputs, level by level
+// The result only contains the wanted outputs
+func (sys System) EvaluateOutputs(input DataInput, wanted ...*IDVal) (DataOutput, error) {
+	if err := sys.checkInput(input); err != nil {
+		return nil, err
+	}
+	needed, err := sys.needed(wanted)
+	if err != nil {
+		return nil, err
+	}
+
+	levels := sys.levelsOf(func(pos int) bool { return needed[pos] })
+	trace, err := sys.run(input, levels, nil, evaluateEngine)
+	if err != nil {
+		return nil, err
+	}
+	result := make(DataOutput, len(wanted))
+	for _, idVal := range wanted {
+		result[idVal] = trace.Output[idVal]
+	}
+	return result, nil
+}
+
+// RequiredInputs returns the external inputs needed to compute the outputs (sorted by id)
+// An input is external if it is not produced by an engine placed before the engine using it
+func (sys System) RequiredInputs(outputs ...*IDVal) ([]*IDVal, error) {
+	needed, err := sys.needed(outputs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Inputs of each engine produced by the engines placed before it
+	internal := make(map[int]map[*IDVal]struct{})
+	for _, dep := range sys.dependencies(nil) {
+		if dep.From >= dep.To {
+			continue
+		}
+		if internal[dep.To] == nil {
+			internal[dep.To] = make(map[*IDVal]struct{})
+		}
+		for _, idVal := range dep.Values {
+			internal[dep.To][idVal] = struct{}{}
+		}
+	}
+
+	found := make(map[*IDVal]struct{})
+	for pos, eng := range sys {
+		if !needed[pos] {
+			continue
+		}
+		inputs, _ := eng.IO()
+		for idVal := range IDSets(inputs).IDVals() {
+			if _, exists := internal[pos][idVal]; !exists {
+				found[idVal] = struct{}{}
+			}
+		}
+	}
+
+	return sortedIDVals(found), nil
+}
+
+// needed walks the dependency graph backwards from the engines producing the outputs
+// It returns, for each engine, whether it shall be evaluated
+func (sys System) needed(outputs []*IDVal) ([]bool, error) {
+	// Producer of each output
+	producers := make(map[*IDVal]int)
+	for pos, eng := range sys {
+		_, out := eng.IO()
+		for idVal := range IDSets(out).IDVals() {
+			producers[idVal] = pos
+		}
+	}
+
+	// Producers used by each engine (placed before it)
+	uses := make(map[int][]int)
+	for _, dep := range sys.dependencies(nil) {
+		if dep.From < dep.To {
+			uses[dep.To] = append(uses[dep.To], dep.From)
+		}
+	}
+
+	result := make([]bool, len(sys))
+	var stack []int
+	for _, idVal := range outputs {
+		if idVal == nil {
+			return nil, errors.New("output: nil value")
+		}
+		pos, exists := producers[idVal]
+		if !exists {
+			return nil, fmt.Errorf("output: value `%s` is not produced by the system", idVal.uuid)
+		}
+		stack = append(stack, pos)
+	}
+	for len(stack) > 0 {
+		pos := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if result[pos] {
+			continue
+		}
+		result[pos] = true
+		stack = append(stack, uses[pos]...)
+	}
+	return result, nil
+}
@@ -0,0 +1,79 @@
+package fuzzy
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestPartialEvaluation(t *testing.T) {
+	fvA, fsA1 := newTestVal("a", "a1")
+	fvB, fsB1 := newTestVal("b", "b1")
+	fvC, fsC1 := newTestVal("c", "c1")
+	fvD, fsD1 := newTestVal("d", "d1")
+	fvE, fsE1 := newTestVal("e", "e1")
+	fvF, fsF1 := newTestVal("f", "f1")
+	fvG, fsG1 := newTestVal("g", "g1")
+
+	and := OperatorZadeh{}.And
+	so := ImplicationMin
+	newEngine := func(rule Rule) Engine {
+		eng, err := NewEngine([]Rule{rule}, AggregationUnion, defuzzificationNone)
+		So(err, ShouldBeNil)
+		return eng
+	}
+
+	Convey("partial evaluation", t, func() {
+		// A and B => C ; D => E, F ; C and E => G
+		sys, err := NewSystem([]Engine{
+			newEngine(NewRule(NewExpression([]Premise{fsC1, fsE1}, and), so, []IDSet{fsG1})),
+			newEngine(NewRule(NewExpression([]Premise{fsA1, fsB1}, and), so, []IDSet{fsC1})),
+			newEngine(NewRule(fsD1, so, []IDSet{fsE1, fsF1})),
+		})
+		So(err, ShouldBeNil)
+
+		Convey("when evaluate outputs", func() {
+			// D is not required
+			output, err := sys.EvaluateOutputs(DataInput{fvA: 1, fvB: 1}, fvC)
+			So(err, ShouldBeNil)
+			So(output, ShouldResemble, DataOutput{fvC: 0})
+
+			output, err = sys.EvaluateOutputs(DataInput{fvA: 1, fvB: 1, fvD: 1}, fvG, fvF)
+			So(err, ShouldBeNil)
+			So(output, ShouldResemble, DataOutput{fvG: 0, fvF: 0})
+
+			output, err = sys.EvaluateOutputs(DataInput{})
+			So(err, ShouldBeNil)
+			So(output, ShouldBeEmpty)
+
+			_, err = sys.EvaluateOutputs(DataInput{fvA: 1, fvB: 1}, fvG)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("when required inputs", func() {
+			inputs, err := sys.RequiredInputs(fvC)
+			So(err, ShouldBeNil)
+			So(inputs, ShouldResemble, []*IDVal{fvA, fvB})
+
+			inputs, err = sys.RequiredInputs(fvE)
+			So(err, ShouldBeNil)
+			So(inputs, ShouldResemble, []*IDVal{fvD})
+
+			inputs, err = sys.RequiredInputs(fvG)
+			So(err, ShouldBeNil)
+			So(inputs, ShouldResemble, []*IDVal{fvA, fvB, fvD})
+
+			inputs, err = sys.RequiredInputs()
+			So(err, ShouldBeNil)
+			So(inputs, ShouldBeEmpty)
+		})
+
+		Convey("when error", func() {
+			_, err := sys.EvaluateOutputs(DataInput{fvA: 1}, fvA)
+			So(err, ShouldBeError, "output: value `a` is not produced by the system")
+
+			_, err = sys.RequiredInputs(fvC, nil)
+			So(err, ShouldBeError, "output: nil value")
+		})
+	})
+}
@@ -0,0 +1,137 @@
+package fuzzy
+
+import (
+	"fmt"
+)
+
+// Delay declares a delayed value of a recurrent system
+// When used as an input, the value is the one computed at the previous step
+type Delay struct {
+	Value   *IDVal  // output of an engine, used as an input at the next step
+	Initial float64 // value used before the first step (and after a reset)
+}
+
+// Recurrent is a stateful system: cycles between engines are allowed through delayed values
+// At each step, the engines using a delayed value receive its previous value
+// A recurrent system shall not be used concurrently
+type Recurrent struct {
+	sys     System
+	delays  []Delay
+	delayed map[*IDVal]struct{}
+	levels  [][]int
+	state   DataInput // current values of the delayed values
+}
+
+// NewRecurrent checks for errors, reorder the engines and creates a new recurrent system
+// Only cycles through delayed values are allowed
+func NewRecurrent(engines []Engine, delays ...Delay) (*Recurrent, error) {
+	tmp := System(engines)
+	if err := tmp.checkDuplicatedIDs(); err != nil {
+		return nil, err
+	}
+	if err := tmp.checkDuplicatedOutputs(); err != nil {
+		return nil, err
+	}
+
+	// Check delayed values
+	outputs := make(map[*IDVal]struct{})
+	for _, eng := range tmp {
+		_, out := eng.IO()
+		for idVal := range IDSets(out).IDVals() {
+			outputs[idVal] = struct{}{}
+		}
+	}
+	delayed := make(map[*IDVal]struct{})
+	for _, delay := range delays {
+		if delay.Value == nil {
+			return nil, fmt.Errorf("delay: nil value")
+		}
+		if _, exists := delayed[delay.Value]; exists {
+			return nil, fmt.Errorf("delay: value `%s` defined twice", delay.Value.uuid)
+		}
+		if _, exists := outputs[delay.Value]; !exists {
+			return nil, fmt.Errorf("delay: value `%s` is not an output", delay.Value.uuid)
+		}
+		delayed[delay.Value] = struct{}{}
+	}
+
+	sys, err := tmp.reorder(delayed)
+	if err != nil {
+		return nil, err
+	}
+
+	rec := &Recurrent{
+		sys:     sys,
+		delays:  delays,
+		delayed: delayed,
+		levels:  sys.levels(delayed),
+	}
+	rec.Reset()
+	return rec, nil
+}
+
+// System returns the engines of the recurrent system, in their order of evaluation
+func (rec *Recurrent) System() System {
+	return rec.sys
+}
+
+// Step evaluates all engines once and keeps the new values of the delayed values for the next step
+// The input shall not define the delayed values, they are provided by the state
+// The state is unchanged if an error occurs
+func (rec *Recurrent) Step(input DataInput) (DataOutput, error) {
+	trace, err := rec.run(input, evaluateEngine)
+	return trace.Output, err
+}
+
+// Trace evaluates all engines like Step, and also returns the aggregated result sets of all outputs
+func (rec *Recurrent) Trace(input DataInput) (Trace, error) {
+	return rec.run(input, Engine.trace)
+}
+
+// run evaluates one step using the evaluation function and updates the state
+func (rec *Recurrent) run(input DataInput, eval func(Engine, DataInput) (Trace, error)) (Trace, error) {
+	for idVal := range input {
+		if _, exists := rec.delayed[idVal]; exists {
+			return Trace{}, fmt.Errorf("input: delayed value `%s` cannot be defined", idVal.uuid)
+		}
+	}
+
+	trace, err := rec.sys.run(input.merge(DataOutput(rec.state)), rec.levels, rec.delayed, eval)
+	if err != nil {
+		return Trace{}, err
+	}
+
+	// Keep the new values
+	for _, delay := range rec.delays {
+		if value, exists := trace.Output[delay.Value]; exists {
+			rec.state[delay.Value] = value
+		}
+	}
+	return trace, nil
+}
+
+// State returns a copy of the current values of the delayed values
+func (rec *Recurrent) State() DataInput {
+	return DataInput(dataIO(rec.state).merge(nil))
+}
+
+// SetState overrides the current values of some delayed values
+func (rec *Recurrent) SetState(state DataInput) error {
+	for idVal := range state {
+		if _, exists := rec.delayed[idVal]; !exists {
+			return fmt.Errorf("state: value `%s` is not delayed", idVal.uuid)
+		}
+	}
+	for idVal, value := range state {
+		rec.state[idVal] = value
+	}
+	return nil
+}
+
+// Reset restores the initial values of the delayed values
+func (rec *Recurrent) Reset() {
+	rec.state = DataInput{}
+	for _, delay := range rec.delays {
+		rec.state[delay.Value] = delay.Initial
+	}
+}
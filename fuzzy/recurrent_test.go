@@ -0,0 +1,118 @@
+package fuzzy
+
+import (
+	"testing"
+
+	"github.com/sbiemont/fugologic/crisp"
+	"github.com/sbiemont/fugologic/id"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRecurrent(t *testing.T) {
+	newVal := func(name id.ID) *IDVal {
+		u, err := crisp.NewSet(0, 1, 0.1)
+		So(err, ShouldBeNil)
+		fv, err := NewIDValBuilders(name, u, map[id.ID]SetBuilder{
+			"low":  StepDown{A: 0, B: 1},
+			"high": StepUp{A: 0, B: 1},
+		})
+		So(err, ShouldBeNil)
+		return fv
+	}
+
+	Convey("recurrent", t, func() {
+		fvX, fvY, fvZ := newVal("x"), newVal("y"), newVal("z")
+
+		// x, z => y
+		engA, err := NewEngine([]Rule{
+			NewRule(NewExpression([]Premise{fvX.Get("low"), fvZ.Get("low")}, OperatorZadeh{}.And), ImplicationMin, []IDSet{fvY.Get("low")}),
+			NewRule(fvX.Get("high"), ImplicationMin, []IDSet{fvY.Get("high")}),
+		}, AggregationUnion, DefuzzificationCentroid, WithID("A"))
+		So(err, ShouldBeNil)
+
+		// y => x (inverted)
+		engB, err := NewEngine([]Rule{
+			NewRule(fvY.Get("low"), ImplicationMin, []IDSet{fvX.Get("high")}),
+			NewRule(fvY.Get("high"), ImplicationMin, []IDSet{fvX.Get("low")}),
+		}, AggregationUnion, DefuzzificationCentroid, WithID("B"))
+		So(err, ShouldBeNil)
+
+		// Expected values, step by step
+		manual := func(x0 float64, steps int) []DataOutput {
+			var result []DataOutput
+			x := x0
+			for i := 0; i < steps; i++ {
+				outA, err := engA.Evaluate(DataInput{fvX: x, fvZ: 0.2})
+				So(err, ShouldBeNil)
+				outB, err := engB.Evaluate(DataInput{fvY: outA[fvY]})
+				So(err, ShouldBeNil)
+				result = append(result, DataOutput{fvX: outB[fvX], fvY: outA[fvY]})
+				x = outB[fvX]
+			}
+			return result
+		}
+
+		Convey("when cycle without delay", func() {
+			_, err := NewSystem([]Engine{engA, engB})
+			So(err, ShouldBeError, "cycle detected: engine `A` -> `y` -> engine `B` -> `x` -> engine `A`")
+
+			_, err = NewRecurrent([]Engine{engA, engB})
+			So(err, ShouldBeError, "cycle detected: engine `A` -> `y` -> engine `B` -> `x` -> engine `A`")
+		})
+
+		Convey("when step", func() {
+			rec, err := NewRecurrent([]Engine{engB, engA}, Delay{Value: fvX, Initial: 0.9})
+			So(err, ShouldBeNil)
+			So(rec.System()[0].ID(), ShouldEqual, id.ID("A"))
+			So(rec.State(), ShouldResemble, DataInput{fvX: 0.9})
+
+			expected := manual(0.9, 5)
+			for i := range expected {
+				output, err := rec.Step(DataInput{fvZ: 0.2})
+				So(err, ShouldBeNil)
+				So(output, ShouldResemble, expected[i])
+				So(rec.State(), ShouldResemble, DataInput{fvX: expected[i][fvX]})
+			}
+
+			// Reset
+			rec.Reset()
+			So(rec.State(), ShouldResemble, DataInput{fvX: 0.9})
+			output, err := rec.Step(DataInput{fvZ: 0.2})
+			So(err, ShouldBeNil)
+			So(output, ShouldResemble, expected[0])
+
+			// Set another state
+			So(rec.SetState(DataInput{fvX: 0.1}), ShouldBeNil)
+			trace, err := rec.Trace(DataInput{fvZ: 0.2})
+			So(err, ShouldBeNil)
+			So(trace.Output, ShouldResemble, manual(0.1, 1)[0])
+			So(trace.Aggregated, ShouldContainKey, fvX)
+			So(trace.Aggregated, ShouldContainKey, fvY)
+		})
+
+		Convey("when errors", func() {
+			_, err := NewRecurrent([]Engine{engA, engB}, Delay{Value: fvZ})
+			So(err, ShouldBeError, "delay: value `z` is not an output")
+
+			_, err = NewRecurrent([]Engine{engA, engB}, Delay{Value: fvX}, Delay{Value: fvX})
+			So(err, ShouldBeError, "delay: value `x` defined twice")
+
+			_, err = NewRecurrent([]Engine{engA, engB}, Delay{})
+			So(err, ShouldBeError, "delay: nil value")
+
+			rec, err := NewRecurrent([]Engine{engA, engB}, Delay{Value: fvX})
+			So(err, ShouldBeNil)
+
+			_, err = rec.Step(DataInput{fvX: 1, fvZ: 0.2})
+			So(err, ShouldBeError, "input: delayed value `x` cannot be defined")
+
+			So(rec.SetState(DataInput{fvY: 1}), ShouldBeError, "state: value `y` is not delayed")
+
+			// Missing input: the state is unchanged
+			_, err = rec.Step(DataInput{})
+			So(err, ShouldBeError, "engine `A`: input: cannot find data for id val `z` (id set `low`)")
+			So(rec.State(), ShouldResemble, DataInput{fvX: 0})
+		})
+	})
+}
@@ -1,5 +1,11 @@
 package fuzzy
 
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
 // flattenIDSets extracts the IDSets from a list of premises
 func flattenIDSets(init []IDSet, premises []Premise) []IDSet {
 	for _, premise := range premises {
@@ -8,6 +14,8 @@ func flattenIDSets(init []IDSet, premises []Premise) []IDSet {
 			init = append(init, p)
 		case Expression:
 			init = flattenIDSets(init, p.premises)
+		case Temporal:
+			init = append(init, p.idSet)
 		}
 	}
 	return init
@@ -24,11 +32,29 @@ var (
 	ImplicationMin Implication = func(set Set, k float64) Set { return set.Min(k) }
 )
 
+// implications lists the predefined implications by name
+var implications = map[string]Implication{
+	"min":  ImplicationMin,
+	"prod": ImplicationProd,
+}
+
+// ImplicationByName returns the predefined implication matching the name
+func ImplicationByName(name string) (Implication, bool) {
+	impl, ok := implications[name]
+	return impl, ok
+}
+
+// Name returns the name of a predefined implication, or an empty string for a custom one
+func (impl Implication) Name() string {
+	return nameOf(impl, implications)
+}
+
 // Rule evaluates the input expression + implication + fuzzy output
 type Rule struct {
 	inputs      Premise
 	implication Implication
 	outputs     []IDSet
+	weight      float64
 }
 
 // NewRule builds a new Rule instance
@@ -39,7 +65,49 @@ func NewRule(inputs Premise, implication Implication, outputs []IDSet) Rule {
 		inputs:      inputs,
 		implication: implication,
 		outputs:     outputs,
+		weight:      1,
+	}
+}
+
+// WithWeight returns a copy of the rule with a weight applied to the premise result
+// The weight shall be in [0, 1]
+func (rule Rule) WithWeight(weight float64) Rule {
+	result := rule
+	result.weight = weight
+	return result
+}
+
+// Premise returns the input expression of the rule
+func (rule Rule) Premise() Premise {
+	return rule.inputs
+}
+
+// Implication returns the implication of the rule
+func (rule Rule) Implication() Implication {
+	return rule.implication
+}
+
+// Outputs returns the consequences of the rule
+func (rule Rule) Outputs() []IDSet {
+	return rule.outputs
+}
+
+// Weight returns the weight of the rule (1 by default)
+func (rule Rule) Weight() float64 {
+	return rule.weight
+}
+
+// String describes the rule. Eg.: IF a1 AND b1 THEN c1, d1 WITH 0.5
+func (rule Rule) String() string {
+	outputs := make([]string, len(rule.outputs))
+	for i, output := range rule.outputs {
+		outputs[i] = output.String()
+	}
+	text := fmt.Sprintf("IF %v THEN %s", rule.inputs, strings.Join(outputs, ", "))
+	if rule.weight != 1 {
+		text += " WITH " + strconv.FormatFloat(rule.weight, 'g', -1, 64)
 	}
+	return text
 }
 
 // evaluate and return the fuzzy output using crisp input
@@ -52,6 +120,7 @@ func (rule Rule) evaluate(input DataInput) ([]IDSet, error) {
 	if err != nil {
 		return nil, err
 	}
+	y *= rule.weight
 
 	// Evaluate outputs => create a NEW fuzzy Set with the same output ID
 	result := make([]IDSet, len(rule.outputs))
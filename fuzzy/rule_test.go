@@ -25,6 +25,21 @@ func TestImplication(t *testing.T) {
 		So(ImplicationMin(plusOne, 1)(2), ShouldEqual, 1) // min(2+1, 1)
 		So(ImplicationMin(plusOne, 5)(2), ShouldEqual, 3) // min(2+1, 5)
 	})
+
+	Convey("implication names", t, func() {
+		impl, ok := ImplicationByName("min")
+		So(ok, ShouldBeTrue)
+		So(impl.Name(), ShouldEqual, "min")
+
+		impl, ok = ImplicationByName("prod")
+		So(ok, ShouldBeTrue)
+		So(impl.Name(), ShouldEqual, "prod")
+
+		_, ok = ImplicationByName("unknown")
+		So(ok, ShouldBeFalse)
+		So(Implication(func(set Set, _ float64) Set { return set }).Name(), ShouldBeEmpty)
+		So(Implication(nil).Name(), ShouldBeEmpty)
+	})
 }
 
 func TestFlattenIDSets(t *testing.T) {
@@ -113,6 +128,23 @@ func TestRule(t *testing.T) {
 			So(output[0].set, ShouldNotEqual, setB) // Membership function should have been replaced
 			So(output[0].uuid, ShouldEqual, id.ID("b1"))
 		})
+
+		Convey("when string", func() {
+			So(rule.String(), ShouldEqual, "IF a1 THEN b1")
+			So(NewRule(fsA1, ImplicationProd, []IDSet{fsB1, fsA1}).WithWeight(0.5).String(), ShouldEqual, "IF a1 THEN b1, a1 WITH 0.5")
+		})
+
+		Convey("when weight", func() {
+			So(rule.Weight(), ShouldEqual, 1)
+			weighted := rule.WithWeight(0.5)
+			So(weighted.Weight(), ShouldEqual, 0.5)
+			So(rule.Weight(), ShouldEqual, 1)
+
+			output, err := weighted.evaluate(DataInput{fvA: 0.8})
+			So(err, ShouldBeNil)
+			So(output, ShouldHaveLength, 1)
+			So(output[0].set(1), ShouldAlmostEqual, 0.4) // b1(1)*0.8*0.5
+		})
 	})
 
 	Convey("inputs", t, func() {
@@ -139,4 +171,14 @@ func TestRule(t *testing.T) {
 			So(ids(outputs), ShouldResemble, []id.ID{fsD1.ID()})
 		})
 	})
+
+	Convey("accessors", t, func() {
+		_, fsA1 := newTestVal("a", "a1")
+		_, fsB1 := newTestVal("b", "b1")
+
+		rule := NewRule(fsA1, ImplicationProd, []IDSet{fsB1})
+		So(rule.Premise().(IDSet).ID(), ShouldEqual, fsA1.ID())
+		So(rule.Implication().Name(), ShouldEqual, "prod")
+		So(ids(rule.Outputs()), ShouldResemble, []id.ID{fsB1.ID()})
+	})
 }
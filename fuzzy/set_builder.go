@@ -3,6 +3,7 @@ package fuzzy
 import (
 	"fmt"
 	"math"
+	"reflect"
 
 	"github.com/sbiemont/fugologic/id"
 )
@@ -207,6 +208,69 @@ func (set StepDown) New() (Set, error) {
 	}, nil
 }
 
+// NewSetBuilder creates a set builder from its name (see. GAUSS, GBELL, TRAP, ...)
+// Parameters are given in the order of the builder fields (eg.: A, B, C for a triangular set)
+func NewSetBuilder(name string, params ...float64) (SetBuilder, error) {
+	expected := map[string]int{GAUSS: 2, GBELL: 3, TRAP: 4, TRI: 3, STEPUP: 2, STEPDOWN: 2, SIG: 2}
+	n, ok := expected[name]
+	if !ok {
+		return nil, fmt.Errorf("set builder `%s` unknown", name)
+	}
+	if len(params) != n {
+		return nil, fmt.Errorf("%s: %d parameters expected (found: %d)", name, n, len(params))
+	}
+
+	p := params
+	switch name {
+	case GAUSS:
+		return Gauss{Sigma: p[0], C: p[1]}, nil
+	case GBELL:
+		return Gbell{A: p[0], B: p[1], C: p[2]}, nil
+	case TRAP:
+		return Trapezoid{A: p[0], B: p[1], C: p[2], D: p[3]}, nil
+	case TRI:
+		return Triangular{A: p[0], B: p[1], C: p[2]}, nil
+	case STEPUP:
+		return StepUp{A: p[0], B: p[1]}, nil
+	case STEPDOWN:
+		return StepDown{A: p[0], B: p[1]}, nil
+	default: // SIG
+		return Sigmoid{A: p[0], C: p[1]}, nil
+	}
+}
+
+// SetBuilderParams returns the name and the parameters of a predefined set builder (see. NewSetBuilder)
+func SetBuilderParams(builder SetBuilder) (string, []float64, error) {
+	// Also accept pointers to builders
+	value := reflect.ValueOf(builder)
+	if value.Kind() == reflect.Pointer && !value.IsNil() {
+		if elem, ok := value.Elem().Interface().(SetBuilder); ok {
+			builder = elem
+		}
+	}
+
+	switch b := builder.(type) {
+	case Gauss:
+		return GAUSS, []float64{b.Sigma, b.C}, nil
+	case Gbell:
+		return GBELL, []float64{b.A, b.B, b.C}, nil
+	case Trapezoid:
+		return TRAP, []float64{b.A, b.B, b.C, b.D}, nil
+	case Triangular:
+		return TRI, []float64{b.A, b.B, b.C}, nil
+	case StepUp:
+		return STEPUP, []float64{b.A, b.B}, nil
+	case StepDown:
+		return STEPDOWN, []float64{b.A, b.B}, nil
+	case Sigmoid:
+		return SIG, []float64{b.A, b.C}, nil
+	case nil:
+		return "", nil, fmt.Errorf("set builder expected")
+	default:
+		return "", nil, fmt.Errorf("set builder %T not supported", builder)
+	}
+}
+
 // NewIDSets builds a list of named fuzzy sets
 func NewIDSets(fsets map[id.ID]SetBuilder) (map[id.ID]Set, error) {
 	sets := make(map[id.ID]Set, len(fsets))
@@ -304,3 +304,46 @@ func TestNewIDSets(t *testing.T) {
 		})
 	})
 }
+
+func TestSetBuilderParams(t *testing.T) {
+	Convey("set builder by name", t, func() {
+		Convey("when ok", func() {
+			builders := map[string]SetBuilder{
+				GAUSS:    Gauss{Sigma: 1, C: 2},
+				GBELL:    Gbell{A: 1, B: 2, C: 3},
+				TRAP:     Trapezoid{A: 1, B: 2, C: 3, D: 4},
+				TRI:      Triangular{A: 1, B: 2, C: 3},
+				STEPUP:   StepUp{A: 1, B: 2},
+				STEPDOWN: StepDown{A: 1, B: 2},
+				SIG:      Sigmoid{A: 1, C: 2},
+			}
+			for name, builder := range builders {
+				n, params, err := SetBuilderParams(builder)
+				So(err, ShouldBeNil)
+				So(n, ShouldEqual, name)
+
+				newBuilder, err := NewSetBuilder(name, params...)
+				So(err, ShouldBeNil)
+				So(newBuilder, ShouldResemble, builder)
+			}
+		})
+
+		Convey("when pointer", func() {
+			name, params, err := SetBuilderParams(&Triangular{A: 1, B: 2, C: 3})
+			So(err, ShouldBeNil)
+			So(name, ShouldEqual, TRI)
+			So(params, ShouldResemble, []float64{1, 2, 3})
+		})
+
+		Convey("when ko", func() {
+			_, err := NewSetBuilder("unknown")
+			So(err, ShouldBeError, "set builder `unknown` unknown")
+
+			_, err = NewSetBuilder(TRI, 1, 2)
+			So(err, ShouldBeError, "tri: 3 parameters expected (found: 2)")
+
+			_, _, err = SetBuilderParams(nil)
+			So(err, ShouldBeError, "set builder expected")
+		})
+	})
+}
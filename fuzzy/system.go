@@ -1,136 +1,358 @@
 package fuzzy
 
 import (
+	"cmp"
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
+	"sync"
 
 	"github.com/sbiemont/fugologic/graph"
 	"github.com/sbiemont/fugologic/id"
 )
 
 // System groups engines and evaluate them all
-// All engines are evaluated sequentially
+// Independent engines are evaluated concurrently, level by level (see Levels)
 type System []Engine
 
 // NewSystem checks for errors, reorder the engines and creates a new system
 func NewSystem(engines []Engine) (System, error) {
 	tmp := System(engines)
+	if err := tmp.checkDuplicatedIDs(); err != nil {
+		return nil, err
+	}
 	if err := tmp.checkDuplicatedOutputs(); err != nil {
 		return nil, err
 	}
 
-	return tmp.reorder()
+	sys, err := tmp.reorder(nil)
+	if err != nil {
+		return nil, err
+	}
+	return sys, nil
 }
 
-// Evaluate all engines one by one
-// The output of the first one is injected into the global input
+// Evaluate all engines, level by level
+// The input shall not define a value computed by the system (see. EvaluateWith and Pin)
+// The engines of a level are evaluated concurrently, their outputs are injected into the input of the next levels
 // The global output is the result of merge of all outputs
 func (sys System) Evaluate(input DataInput) (DataOutput, error) {
-	newOutput := DataOutput{}
-	newInput := input
+	if err := sys.checkInput(input); err != nil {
+		return nil, err
+	}
+	trace, err := sys.run(input, sys.levels(nil), nil, evaluateEngine)
+	return trace.Output, err
+}
 
-	for _, eng := range sys {
-		output, err := eng.Evaluate(newInput)
-		if err != nil {
-			return nil, err
+// EvaluateSequential evaluates all engines one by one, in the order of the system
+// The result is the same as Evaluate, it is intended for debugging
+func (sys System) EvaluateSequential(input DataInput) (DataOutput, error) {
+	if err := sys.checkInput(input); err != nil {
+		return nil, err
+	}
+	trace, err := sys.run(input, sys.sequence(), nil, evaluateEngine)
+	return trace.Output, err
+}
+
+// Trace evaluates all engines like Evaluate, and also returns the aggregated result sets of all outputs
+func (sys System) Trace(input DataInput) (Trace, error) {
+	if err := sys.checkInput(input); err != nil {
+		return Trace{}, err
+	}
+	return sys.run(input, sys.levels(nil), nil, Engine.trace)
+}
+
+// evaluateEngine evaluates an engine without keeping its aggregated sets
+func evaluateEngine(eng Engine, input DataInput) (Trace, error) {
+	output, err := eng.evaluate(input)
+	return Trace{Output: output}, err
+}
+
+// sequence returns one level for each engine, in the order of the system
+func (sys System) sequence() [][]int {
+	result := make([][]int, len(sys))
+	for i := range sys {
+		result[i] = []int{i}
+	}
+	return result
+}
+
+// run evaluates all engines using the evaluation function, level by level
+// Results are merged in the order of the system, and the reported error is the one of the first failing engine
+// The input values of the delayed (or pinned) values are kept during the whole evaluation (their new values are only outputs)
+// The histories of the engines record the input and the outputs of each level (once per history)
+func (sys System) run(input DataInput, levels [][]int, delayed map[*IDVal]struct{}, eval func(Engine, DataInput) (Trace, error)) (Trace, error) {
+	histories := sys.histories()
+	record := func(data DataInput) {
+		for _, hist := range histories {
+			hist.Record(data)
+		}
+	}
+	record(input)
+
+	result := Trace{
+		Output:     DataOutput{},
+		Aggregated: make(map[*IDVal]Set),
+	}
+	newInput := input
+	for _, level := range levels {
+		traces := make([]Trace, len(level))
+		errs := make([]error, len(level))
+		if len(level) == 1 {
+			traces[0], errs[0] = eval(sys[level[0]], newInput)
+		} else {
+			var wg sync.WaitGroup
+			for i, pos := range level {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					traces[i], errs[i] = eval(sys[pos], newInput)
+				}()
+			}
+			wg.Wait()
 		}
 
-		newOutput = newOutput.merge(output)
-		newInput = newInput.merge(output)
+		// Merge the results of the level
+		levelOutput := DataOutput{}
+		for i, pos := range level {
+			if errs[i] != nil {
+				return Trace{}, fmt.Errorf("%s: %w", sys.engineName(pos), errs[i])
+			}
+			levelOutput = levelOutput.merge(traces[i].Output)
+			for idVal, set := range traces[i].Aggregated {
+				result.Aggregated[idVal] = set
+			}
+		}
+		result.Output = result.Output.merge(levelOutput)
+		newInput = newInput.merge(levelOutput)
+		record(DataInput(levelOutput))
+		for idVal := range delayed {
+			if value, exists := input[idVal]; exists {
+				newInput[idVal] = value
+			} else {
+				delete(newInput, idVal)
+			}
+		}
 	}
 
-	return newOutput, nil
+	return result, nil
 }
 
-// reorder builds a graph of engines, check the presence of cycles and flattens the created graph
-func (sys System) reorder() (System, error) {
-	// To nodes
-	nodes := make([]*Engine, len(sys))
-	for i, eng := range sys {
-		nodes[i] = &eng
+// histories returns the distinct histories of the engines
+func (sys System) histories() []*History {
+	var result []*History
+	for _, eng := range sys {
+		result = eng.histories(result)
 	}
+	return result
+}
 
-	// Init graph
-	dg := graph.New[*Engine]()
-	addEdge := func(i, j int) {
-		dg.Add(nodes[i], nodes[j])
+// Levels groups the positions of the engines by level of evaluation
+// An engine only depends on engines of the previous levels, so that the engines of a level can be evaluated concurrently
+// Like the sequential evaluation, an engine only uses the outputs of the engines placed before it in the system
+func (sys System) Levels() [][]int {
+	return sys.levels(nil)
+}
+
+// levelsOf returns the levels of evaluation, only keeping the positions of the selected engines
+func (sys System) levelsOf(selected func(pos int) bool) [][]int {
+	var result [][]int
+	for _, level := range sys.levels(nil) {
+		var kept []int
+		for _, pos := range level {
+			if selected(pos) {
+				kept = append(kept, pos)
+			}
+		}
+		if len(kept) > 0 {
+			result = append(result, kept)
+		}
 	}
+	return result
+}
 
-	// Returns true if a common IDVal is found
-	hasCommon := func(a, b map[*IDVal]struct{}) bool {
-		for b1 := range b {
-			if _, exists := a[b1]; exists {
-				return true
+// levels groups the positions of the engines by level of evaluation, ignoring the links made by the delayed values
+func (sys System) levels(delayed map[*IDVal]struct{}) [][]int {
+	deps := sys.dependencies(delayed)
+	levelOf := make([]int, len(sys))
+	var result [][]int
+	for j := range sys {
+		for _, dep := range deps {
+			switch {
+			case dep.To == j && dep.From < j:
+				// j uses an output of a previous engine: evaluate it after
+				levelOf[j] = max(levelOf[j], levelOf[dep.From]+1)
+			case dep.From == j && dep.To < j:
+				// A previous engine reads an output of j: do not evaluate j before
+				levelOf[j] = max(levelOf[j], levelOf[dep.To])
 			}
 		}
-		return false
+		if levelOf[j] == len(result) {
+			result = append(result, nil)
+		}
+		result[levelOf[j]] = append(result[levelOf[j]], j)
 	}
+	return result
+}
+
+// Dependency links two engines of a system: values produced by the first one are used by the second one
+type Dependency struct {
+	From   int      // position of the producing engine
+	To     int      // position of the using engine
+	Values []*IDVal // shared values, sorted by id
+}
+
+// Dependencies returns all links between the engines of the system (ordered by producing engine, then using engine)
+// An engine using its own output depends on itself
+func (sys System) Dependencies() []Dependency {
+	return sys.dependencies(nil)
+}
 
+// dependencies returns all links between the engines of the system, ignoring the links made by the delayed values
+func (sys System) dependencies(delayed map[*IDVal]struct{}) []Dependency {
 	// Compute inputs / outputs of all engines
 	type inouts struct {
 		inputs  map[*IDVal]struct{}
 		outputs map[*IDVal]struct{}
 	}
-	savedIO := make(map[id.ID]inouts)
-	for _, eng := range sys {
+	savedIO := make([]inouts, len(sys))
+	for i, eng := range sys {
 		in, out := eng.IO()
-		savedIO[eng.uuid] = inouts{
+		savedIO[i] = inouts{
 			inputs:  IDSets(in).IDVals(),
 			outputs: IDSets(out).IDVals(),
 		}
 	}
 
-	// Add edges
-	for i, iEng := range sys {
-		// Edge at the current engine
-		iIO := savedIO[iEng.uuid]
-		if hasCommon(iIO.outputs, iIO.inputs) {
-			addEdge(i, i)
+	// Returns the common IDVal, sorted by id
+	common := func(a, b map[*IDVal]struct{}) []*IDVal {
+		var result []*IDVal
+		for b1 := range b {
+			if _, isDelayed := delayed[b1]; isDelayed {
+				continue
+			}
+			if _, exists := a[b1]; exists {
+				result = append(result, b1)
+			}
 		}
+		sort.Slice(result, func(i, j int) bool {
+			return result[i].uuid < result[j].uuid
+		})
+		return result
+	}
 
-		// Edges with the other engines
-		for j := i + 1; j < len(sys); j++ {
-			jEng := sys[j]
-			jIO := savedIO[jEng.uuid]
-			if hasCommon(iIO.outputs, jIO.inputs) {
-				addEdge(i, j)
-			}
-			if hasCommon(jIO.outputs, iIO.inputs) {
-				addEdge(j, i)
+	var result []Dependency
+	for i := range sys {
+		for j := range sys {
+			if values := common(savedIO[i].outputs, savedIO[j].inputs); len(values) > 0 {
+				result = append(result, Dependency{From: i, To: j, Values: values})
 			}
 		}
 	}
+	return result
+}
+
+// Graph returns the dependency graph of the system, nodes are the positions of the engines
+// All engines are nodes of the graph (even engines without dependency)
+func (sys System) Graph() graph.Graph[int] {
+	return sys.graph(nil)
+}
+
+// graph returns the dependency graph of the system, ignoring the links made by the delayed values
+func (sys System) graph(delayed map[*IDVal]struct{}) graph.Graph[int] {
+	dg := graph.New[int]()
+	for i := range sys {
+		dg[i] = nil
+	}
+	for _, dep := range sys.dependencies(delayed) {
+		dg.Add(dep.From, dep.To)
+	}
+	return dg
+}
 
-	// To flat engines list (and check for cycles)
-	flat, err := dg.TopologicalSort()
+// reorder flattens the dependency graph of engines and checks the presence of cycles
+// The order is stable: the initial order of the engines is kept whenever possible
+// The links made by the delayed values are ignored
+func (sys System) reorder(delayed map[*IDVal]struct{}) (System, error) {
+	flat, err := sys.graph(delayed).TopologicalSortFunc(cmp.Compare[int])
 	if err != nil {
+		var cycleErr *graph.CycleError[int]
+		if errors.As(err, &cycleErr) {
+			return nil, fmt.Errorf("%w: %s", graph.ErrCyclicGraph, sys.describeCycle(cycleErr.Path, delayed))
+		}
 		return nil, err
 	}
 	result := make([]Engine, len(flat))
-	for i, eng := range flat {
-		result[i] = *eng
+	for i, pos := range flat {
+		result[i] = sys[pos]
 	}
 	return result, nil
 }
 
-// outputs flatten all outputs of the system
-func (sys System) outputs() []IDSet {
-	var result []IDSet
-	for _, eng := range sys {
+// checkDuplicatedOutputs controls that an output is not produced by two engines
+func (sys System) checkDuplicatedOutputs() error {
+	found := make(map[*IDVal]int) // output => index of the engine
+	for i, eng := range sys {
 		_, outputs := eng.IO()
-		result = append(result, outputs...)
+		for _, out := range outputs {
+			if j, exists := found[out.parent]; exists && j != i {
+				return fmt.Errorf("output `%s` produced by %s and %s", out.parent.uuid, sys.engineName(j), sys.engineName(i))
+			}
+			found[out.parent] = i
+		}
 	}
-	return result
+	return nil
 }
 
-// checkDuplicatedOutputs controls that an output is not produced twice
-func (sys System) checkDuplicatedOutputs() error {
-	outputs := sys.outputs()
-	for i, outI := range outputs {
-		for _, outJ := range outputs[i+1:] {
-			if outI.parent == outJ.parent {
-				return fmt.Errorf("output `%s` detected twice", outI.parent.uuid)
-			}
+// checkDuplicatedIDs controls that two engines do not share the same identifier
+func (sys System) checkDuplicatedIDs() error {
+	found := make(map[id.ID]struct{})
+	for _, eng := range sys {
+		if eng.uuid == "" {
+			continue
+		}
+		if _, exists := found[eng.uuid]; exists {
+			return fmt.Errorf("engine `%s` defined twice", eng.uuid)
 		}
+		found[eng.uuid] = struct{}{}
 	}
 	return nil
 }
+
+// engineName describes the engine at the position: its identifier if defined, else its position
+func (sys System) engineName(i int) string {
+	if uuid := sys[i].uuid; uuid != "" {
+		return fmt.Sprintf("engine `%s`", uuid)
+	}
+	return fmt.Sprintf("engine #%d", i)
+}
+
+// describeCycle describes the engines of the cycle and the values linking them
+// Eg.: "engine `A` -> `x` -> engine `B` -> `y` -> engine `A`"
+func (sys System) describeCycle(cycle []int, delayed map[*IDVal]struct{}) string {
+	// Start from the first engine of the system
+	start := 0
+	for i, pos := range cycle[:len(cycle)-1] {
+		if pos < cycle[start] {
+			start = i
+		}
+	}
+	cycle = append(append([]int{}, cycle[start:len(cycle)-1]...), cycle[:start+1]...)
+
+	// Values linking the engines
+	shared := make(map[[2]int][]*IDVal)
+	for _, dep := range sys.dependencies(delayed) {
+		shared[[2]int{dep.From, dep.To}] = dep.Values
+	}
+
+	parts := []string{sys.engineName(cycle[0])}
+	for i := 1; i < len(cycle); i++ {
+		var ids []string
+		for _, idVal := range shared[[2]int{cycle[i-1], cycle[i]}] {
+			ids = append(ids, fmt.Sprintf("`%s`", idVal.uuid))
+		}
+		parts = append(parts, strings.Join(ids, ", "), sys.engineName(cycle[i]))
+	}
+	return strings.Join(parts, " -> ")
+}
@@ -1,9 +1,11 @@
 package fuzzy
 
 import (
+	"errors"
 	"sort"
 	"testing"
 
+	"github.com/sbiemont/fugologic/graph"
 	"github.com/sbiemont/fugologic/id"
 
 	. "github.com/smartystreets/goconvey/convey"
@@ -37,17 +39,13 @@ func TestSystem(t *testing.T) {
 	}
 
 	Convey("evaluate", t, func() {
-		eng1, err1 := NewEngine(rulesEng1, agg, defuzz)
+		eng1, err1 := NewEngine(rulesEng1, agg, defuzz, WithID("A"))
 		So(err1, ShouldBeNil)
-		eng2, err2 := NewEngine(rulesEng2, agg, defuzz)
+		eng2, err2 := NewEngine(rulesEng2, agg, defuzz, WithID("B"), WithDescription("D => E, F"))
 		So(err2, ShouldBeNil)
-		eng3, err3 := NewEngine(rulesEng3, agg, defuzz)
+		eng3, err3 := NewEngine(rulesEng3, agg, defuzz, WithID("C"))
 		So(err3, ShouldBeNil)
 
-		eng1.uuid = "Engine #A"
-		eng2.uuid = "Engine #B"
-		eng3.uuid = "Engine #C"
-
 		Convey("when ok", func() {
 			var system System = []Engine{eng1, eng2, eng3}
 			output, errOut := system.Evaluate(DataInput{
@@ -62,6 +60,18 @@ func TestSystem(t *testing.T) {
 				fvF: 0,
 				fvG: 0,
 			})
+
+			trace, errTrace := system.Trace(DataInput{
+				fvA: 1,
+				fvB: 1,
+				fvD: 1,
+			})
+			So(errTrace, ShouldBeNil)
+			So(trace.Output, ShouldResemble, output)
+			So(trace.Aggregated, ShouldHaveLength, 4)
+			for _, idVal := range []*IDVal{fvC, fvE, fvF, fvG} {
+				So(trace.Aggregated, ShouldContainKey, idVal)
+			}
 		})
 
 		Convey("when missing input", func() {
@@ -70,8 +80,93 @@ func TestSystem(t *testing.T) {
 				fvA: 1,
 				fvB: 1,
 			})
-			So(errOut, ShouldBeError, "input: cannot find data for id val `d` (id set `d1`)")
+			So(errOut, ShouldBeError, "engine `B`: input: cannot find data for id val `d` (id set `d1`)")
 			So(output, ShouldBeEmpty)
+
+			_, errTrace := system.Trace(DataInput{
+				fvA: 1,
+				fvB: 1,
+			})
+			So(errTrace, ShouldBeError, "engine `B`: input: cannot find data for id val `d` (id set `d1`)")
+		})
+
+		Convey("dependencies", func() {
+			var system System = []Engine{eng3, eng1, eng2}
+			So(system.Dependencies(), ShouldResemble, []Dependency{
+				{From: 1, To: 0, Values: []*IDVal{fvC}},
+				{From: 2, To: 0, Values: []*IDVal{fvE}},
+			})
+			So(system.Graph(), ShouldResemble, graph.Graph[int]{
+				0: nil,
+				1: {0},
+				2: {0},
+			})
+		})
+
+		Convey("levels", func() {
+			Convey("when independent engines", func() {
+				var system System = []Engine{eng1, eng2, eng3}
+				So(system.Levels(), ShouldResemble, [][]int{{0, 1}, {2}})
+			})
+
+			Convey("when chained engines", func() {
+				var system System = []Engine{eng2, eng3, eng1}
+				So(system.Levels(), ShouldResemble, [][]int{{0}, {1, 2}})
+			})
+
+			Convey("when an engine uses the output of a next one", func() {
+				// Like the sequential evaluation, C cannot see the result of A
+				var system System = []Engine{eng3, eng1, eng2}
+				So(system.Levels(), ShouldResemble, [][]int{{0, 1, 2}})
+			})
+
+			Convey("when empty", func() {
+				So(System{}.Levels(), ShouldBeEmpty)
+			})
+
+			Convey("when engines changed after NewSystem", func() {
+				system, err := NewSystem([]Engine{eng3, eng1, eng2})
+				So(err, ShouldBeNil)
+				So(system.Levels(), ShouldResemble, [][]int{{0, 1}, {2}})
+
+				// The levels are computed for each call
+				system.Levels()[0][0] = 2
+				So(system.Levels(), ShouldResemble, [][]int{{0, 1}, {2}})
+
+				// Levels follow the engines
+				changed := System{system[2], system[1], system[0]}
+				So(changed.Levels(), ShouldResemble, [][]int{{0, 1, 2}})
+			})
+		})
+
+		Convey("sequential", func() {
+			var system System = []Engine{eng1, eng2, eng3}
+			input := DataInput{fvA: 1, fvB: 1, fvD: 1}
+			output, err := system.Evaluate(input)
+			So(err, ShouldBeNil)
+			outputSeq, errSeq := system.EvaluateSequential(input)
+			So(errSeq, ShouldBeNil)
+			So(outputSeq, ShouldResemble, output)
+
+			// Same error
+			for i := 0; i < 20; i++ {
+				_, err = system.Evaluate(DataInput{fvD: 1})
+				So(err, ShouldBeError, "engine `A`: input: cannot find data for id val `a` (id set `a1`)")
+			}
+			_, errSeq = system.EvaluateSequential(DataInput{fvD: 1})
+			So(errSeq, ShouldBeError, "engine `A`: input: cannot find data for id val `a` (id set `a1`)")
+		})
+
+		Convey("stable order", func() {
+			for i := 0; i < 10; i++ {
+				system, err := NewSystem([]Engine{eng3, eng2, eng1})
+				So(err, ShouldBeNil)
+				var ids []id.ID
+				for _, eng := range system {
+					ids = append(ids, eng.ID())
+				}
+				So(ids, ShouldResemble, []id.ID{"B", "A", "C"})
+			}
 		})
 
 		Convey("check", func() {
@@ -81,17 +176,44 @@ func TestSystem(t *testing.T) {
 					rulesEng2Bis := []Rule{
 						NewRule(fsD1, ImplicationProd, []IDSet{fsE1, fsF1, fsG1}),
 					}
-					eng2Bis, err2Bis := NewEngine(rulesEng2Bis, agg, defuzz)
+					eng2Bis, err2Bis := NewEngine(rulesEng2Bis, agg, defuzz, WithID("B'"))
 					So(err2Bis, ShouldBeNil)
 
 					var system System = []Engine{eng1, eng2Bis, eng3}
-					So(system.checkDuplicatedOutputs(), ShouldBeError, "output `g` detected twice")
+					So(system.checkDuplicatedOutputs(), ShouldBeError, "output `g` produced by engine `B'` and engine `C`")
+
+					sys, errSys := NewSystem([]Engine{eng1, eng2Bis, eng3})
+					So(errSys, ShouldBeError, "output `g` produced by engine `B'` and engine `C`")
+					So(sys, ShouldBeEmpty)
+
+					// Without identifiers
+					eng2Bis.uuid, eng3.uuid = "", ""
+					_, errSys = NewSystem([]Engine{eng1, eng2Bis, eng3})
+					So(errSys, ShouldBeError, "output `g` produced by engine #1 and engine #2")
+				})
+
+				Convey("when engine defined twice", func() {
+					eng2Bis, err2Bis := NewEngine(rulesEng2, agg, defuzz, WithID("A"))
+					So(err2Bis, ShouldBeNil)
 
 					sys, errSys := NewSystem([]Engine{eng1, eng2Bis, eng3})
-					So(errSys, ShouldBeError, "output `g` detected twice")
+					So(errSys, ShouldBeError, "engine `A` defined twice")
 					So(sys, ShouldBeEmpty)
 				})
 
+				Convey("when output defined twice in the same engine", func() {
+					// D => E, F and C => E
+					rulesEng2Bis := []Rule{
+						NewRule(fsD1, ImplicationProd, []IDSet{fsE1, fsF1}),
+						NewRule(fsC1, ImplicationProd, []IDSet{fsE1}),
+					}
+					eng2Bis, err2Bis := NewEngine(rulesEng2Bis, agg, defuzz)
+					So(err2Bis, ShouldBeNil)
+
+					var system System = []Engine{eng1, eng2Bis, eng3}
+					So(system.checkDuplicatedOutputs(), ShouldBeNil)
+				})
+
 				Convey("when ok", func() {
 					var system System = []Engine{eng1, eng2, eng3}
 					So(system.checkDuplicatedOutputs(), ShouldBeNil)
@@ -106,11 +228,25 @@ func TestSystem(t *testing.T) {
 				Convey("when cycles", func() {
 					// G => E, F
 					rulesEng2Bis := []Rule{NewRule(fsG1, ImplicationProd, []IDSet{fsE1, fsF1})}
-					eng2Bis, err2Bis := NewEngine(rulesEng2Bis, agg, defuzz)
+					eng2Bis, err2Bis := NewEngine(rulesEng2Bis, agg, defuzz, WithID("B'"))
 					So(err2Bis, ShouldBeNil)
 
 					system, err := NewSystem([]Engine{eng1, eng2Bis, eng3})
-					So(err, ShouldBeError, "cycle detected")
+					So(err, ShouldBeError, "cycle detected: engine `B'` -> `e` -> engine `C` -> `g` -> engine `B'`")
+					So(errors.Is(err, graph.ErrCyclicGraph), ShouldBeTrue)
+					So(system, ShouldBeNil)
+				})
+
+				Convey("when engine depends on itself", func() {
+					// C and E => C
+					rulesEng3Bis := []Rule{
+						NewRule(NewExpression([]Premise{fsC1, fsE1}, and), so, []IDSet{fsC1}),
+					}
+					eng3Bis, err3Bis := NewEngine(rulesEng3Bis, agg, defuzz)
+					So(err3Bis, ShouldBeNil)
+
+					system, err := NewSystem([]Engine{eng2, eng3Bis})
+					So(err, ShouldBeError, "cycle detected: engine #1 -> `c` -> engine #1")
 					So(system, ShouldBeNil)
 				})
 
@@ -119,6 +255,25 @@ func TestSystem(t *testing.T) {
 					So(err, ShouldBeNil)
 					So(enginesIDs(system), ShouldResemble, []id.ID{eng1.uuid, eng2.uuid, eng3.uuid})
 				})
+
+				Convey("when independent engines", func() {
+					system, err := NewSystem([]Engine{eng1})
+					So(err, ShouldBeNil)
+					So(enginesIDs(system), ShouldResemble, []id.ID{eng1.uuid})
+
+					system, err = NewSystem([]Engine{eng1, eng2})
+					So(err, ShouldBeNil)
+					So(enginesIDs(system), ShouldResemble, []id.ID{eng1.uuid, eng2.uuid})
+				})
+
+				Convey("when engines without identifier", func() {
+					eng1.uuid, eng2.uuid, eng3.uuid = "", "", ""
+					system, err := NewSystem([]Engine{eng3, eng2, eng1})
+					So(err, ShouldBeNil)
+					So(system, ShouldHaveLength, 3)
+					_, outputs := system[2].IO()
+					So(outputs[0].parent, ShouldEqual, fvG)
+				})
 			})
 		})
 	})
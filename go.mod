@@ -6,6 +6,7 @@ require (
 	github.com/google/uuid v1.6.0
 	github.com/smartystreets/goconvey v1.8.1
 	golang.org/x/sync v0.12.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -0,0 +1,181 @@
+package graph
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// CycleError is returned when a cycle prevents from sorting a graph
+// It matches ErrCyclicGraph (see errors.Is)
+type CycleError[T comparable] struct {
+	Path []T // nodes of the cycle, the first node being repeated at the end (eg.: [a b c a])
+}
+
+// Error describes the cycle. Eg.: "cycle detected: a -> b -> a"
+func (err *CycleError[T]) Error() string {
+	if len(err.Path) == 0 {
+		return ErrCyclicGraph.Error()
+	}
+	nodes := make([]string, len(err.Path))
+	for i, node := range err.Path {
+		nodes[i] = fmt.Sprint(node)
+	}
+	return fmt.Sprintf("%s: %s", ErrCyclicGraph, strings.Join(nodes, " -> "))
+}
+
+// Unwrap returns ErrCyclicGraph
+func (err *CycleError[T]) Unwrap() error {
+	return ErrCyclicGraph
+}
+
+// StronglyConnectedComponents returns the strongly connected components of the graph (Tarjan's algorithm)
+// Each node belongs to exactly one component, components are returned in reverse topological order
+// Nodes are visited in their order in the graph (see StronglyConnectedComponentsFunc for a stable order)
+func (g Graph[T]) StronglyConnectedComponents() [][]T {
+	return g.tarjan(g.Nodes())
+}
+
+// StronglyConnectedComponentsFunc returns the strongly connected components of the graph (Tarjan's algorithm)
+// Nodes are visited in the order defined by the comparison function (see slices.SortFunc)
+func (g Graph[T]) StronglyConnectedComponentsFunc(cmp func(a, b T) int) [][]T {
+	return g.tarjan(g.sortedNodes(cmp))
+}
+
+// tarjan computes the strongly connected components, visiting the nodes in their order
+func (g Graph[T]) tarjan(nodes []T) [][]T {
+	type state struct {
+		index   int
+		lowLink int
+		onStack bool
+	}
+	states := make(map[T]*state)
+	var stack []T
+	var result [][]T
+
+	var visit func(from T)
+	visit = func(from T) {
+		current := &state{index: len(states), lowLink: len(states), onStack: true}
+		states[from] = current
+		stack = append(stack, from)
+
+		for _, to := range g[from] {
+			next, visited := states[to]
+			switch {
+			case !visited:
+				visit(to)
+				current.lowLink = min(current.lowLink, states[to].lowLink)
+			case next.onStack:
+				current.lowLink = min(current.lowLink, next.index)
+			}
+		}
+
+		// Root of a component: pop all its nodes
+		if current.lowLink == current.index {
+			var component []T
+			for {
+				node := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				states[node].onStack = false
+				component = append(component, node)
+				if node == from {
+					break
+				}
+			}
+			result = append(result, component)
+		}
+	}
+
+	for _, node := range nodes {
+		if _, visited := states[node]; !visited {
+			visit(node)
+		}
+	}
+	return result
+}
+
+// Cycles returns all elementary cycles of the graph (Johnson's algorithm)
+// Each cycle is a path without repeated node, its first node being repeated at the end (eg.: [a b c a]) ;
+// a node linked to itself is a cycle (eg.: [d d]). The number of cycles can grow exponentially with the size of the graph
+// Nodes are visited in their order in the graph (see CyclesFunc for a stable order)
+func (g Graph[T]) Cycles() [][]T {
+	return g.cycles(nil)
+}
+
+// CyclesFunc returns all elementary cycles of the graph, in a stable order
+// Nodes and successors are visited in the order defined by the comparison function (see slices.SortFunc):
+// each cycle starts with its smallest node, cycles are grouped by starting node
+func (g Graph[T]) CyclesFunc(cmp func(a, b T) int) [][]T {
+	return g.cycles(cmp)
+}
+
+// cycles enumerates the elementary cycles, visiting the nodes and their successors sorted using the comparison function (if defined)
+// The cycles starting with a node only use this node and the next ones
+func (g Graph[T]) cycles(cmp func(a, b T) int) [][]T {
+	nodes := g.sortedNodes(cmp)
+	order := make(map[T]int, len(nodes))
+	for i, node := range nodes {
+		order[node] = i
+	}
+
+	var result [][]T
+	for first, start := range nodes {
+		successors := func(from T) []T {
+			var next []T
+			for _, to := range g.successors(from) {
+				if order[to] >= first {
+					next = append(next, to)
+				}
+			}
+			if cmp != nil {
+				slices.SortStableFunc(next, cmp)
+			}
+			return next
+		}
+
+		// A node stays blocked while it cannot lead back to the start node
+		blocked := make(map[T]bool)
+		blockers := make(map[T][]T) // node => blocked nodes to release with it
+		var unblock func(node T)
+		unblock = func(node T) {
+			blocked[node] = false
+			for _, other := range blockers[node] {
+				if blocked[other] {
+					unblock(other)
+				}
+			}
+			delete(blockers, node)
+		}
+
+		var path []T
+		var circuit func(from T) bool
+		circuit = func(from T) bool {
+			found := false
+			path = append(path, from)
+			blocked[from] = true
+			next := successors(from)
+			for _, to := range next {
+				switch {
+				case to == start:
+					result = append(result, append(slices.Clone(path), start))
+					found = true
+				case !blocked[to]:
+					found = circuit(to) || found
+				}
+			}
+			if found {
+				unblock(from)
+			} else {
+				for _, to := range next {
+					if !slices.Contains(blockers[to], from) {
+						blockers[to] = append(blockers[to], from)
+					}
+				}
+			}
+			path = path[:len(path)-1]
+			return found
+		}
+		circuit(start)
+	}
+	return result
+}
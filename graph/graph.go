@@ -30,7 +30,8 @@ var (
 )
 
 // TopologicalSort performs a topological sort
-// Returns an error if a cycle is detected
+// The order of independent nodes is not defined (see TopologicalSortFunc for a stable order)
+// Returns a *CycleError if a cycle is detected
 func (g Graph[T]) TopologicalSort() ([]T, error) {
 	var result []T
 	err := runDFS(g, func(node T) error {
@@ -38,7 +39,7 @@ func (g Graph[T]) TopologicalSort() ([]T, error) {
 		return nil
 	})
 	if err != nil {
-		return nil, err
+		return nil, &CycleError[T]{Path: g.FindCycle()}
 	}
 
 	// Reverse the result to get the correct topological order
@@ -46,6 +47,70 @@ func (g Graph[T]) TopologicalSort() ([]T, error) {
 	return result, nil
 }
 
+// FindCycle returns the path of a cycle, its first node being repeated at the end (eg.: [a b c a])
+// Returns nil if the graph does not contain any cycle
+func (g Graph[T]) FindCycle() []T {
+	return g.findCycle(g.Nodes(), nil)
+}
+
+// findCycle searches a cycle starting from the nodes, in their order
+// Only the kept nodes are visited (all nodes if nil)
+func (g Graph[T]) findCycle(nodes []T, keep func(T) bool) []T {
+	colors := make(map[T]color)
+	var stack []T
+	var visit func(from T) []T
+	visit = func(from T) []T {
+		colors[from] = grey
+		stack = append(stack, from)
+		for _, to := range g[from] {
+			if keep != nil && !keep(to) {
+				continue
+			}
+			switch colors[to] {
+			case grey:
+				// Back edge: the cycle starts at "to" in the current path
+				start := slices.Index(stack, to)
+				return append(slices.Clone(stack[start:]), to)
+			case white:
+				if cycle := visit(to); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		colors[from] = black
+		return nil
+	}
+
+	for _, node := range nodes {
+		if colors[node] == white {
+			if cycle := visit(node); cycle != nil {
+				return cycle
+			}
+		}
+	}
+	return nil
+}
+
+// Nodes returns all nodes of the graph (roots and adjacent nodes), in no particular order
+func (g Graph[T]) Nodes() []T {
+	found := make(map[T]struct{})
+	var result []T
+	add := func(node T) {
+		if _, exists := found[node]; !exists {
+			found[node] = struct{}{}
+			result = append(result, node)
+		}
+	}
+	for from, edge := range g {
+		add(from)
+		for _, to := range edge {
+			add(to)
+		}
+	}
+	return result
+}
+
 // IsCyclic checks if the graph contains a loop
 // * edges: list of directed edges from one node to a list of nodes
 // func IsCyclic[T comparable](edges map[T][]T) bool {
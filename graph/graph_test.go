@@ -1,6 +1,7 @@
 package graph_test
 
 import (
+	"errors"
 	"slices"
 	"testing"
 
@@ -122,6 +123,7 @@ func TestGraph(t *testing.T) {
 			So(ShouldBeOrdered(topo, b, e, g), ShouldBeTrue)
 			So(ShouldBeOrdered(topo, d, e, g), ShouldBeTrue)
 			So(ShouldBeOrdered(topo, d, f), ShouldBeTrue)
+			So(dg.FindCycle(), ShouldBeNil)
 		})
 	})
 
@@ -143,8 +145,12 @@ func TestGraph(t *testing.T) {
 				d: []node{d},
 			}
 			topo, err := dg.TopologicalSort()
-			So(err, ShouldBeError, "cycle detected")
+			So(errors.Is(err, graph.ErrCyclicGraph), ShouldBeTrue)
 			So(topo, ShouldBeEmpty)
+
+			var cycleErr *graph.CycleError[node]
+			So(errors.As(err, &cycleErr), ShouldBeTrue)
+			So(cycleErr.Path[0], ShouldEqual, cycleErr.Path[len(cycleErr.Path)-1])
 		})
 
 		Convey("custom #2", func() {
@@ -156,8 +162,28 @@ func TestGraph(t *testing.T) {
 				d: []node{a},
 			}
 			topo, err := dg.TopologicalSort()
-			So(err, ShouldBeError, "cycle detected")
+			So(err.Error(), ShouldStartWith, "cycle detected: ")
 			So(topo, ShouldBeEmpty)
+
+			var cycleErr *graph.CycleError[node]
+			So(errors.As(err, &cycleErr), ShouldBeTrue)
+			So(cycleErr.Path, ShouldHaveLength, 5)
+
+			cycle := dg.FindCycle()
+			So(cycle, ShouldHaveLength, 5)
+			So(cycle[0], ShouldEqual, cycle[4])
+			for i := range cycle[:4] {
+				So(dg[cycle[i]], ShouldContain, cycle[i+1])
+			}
+		})
+
+		Convey("self loop", func() {
+			// a -> b -> b
+			dg := graph.Graph[node]{
+				a: []node{b},
+				b: []node{b},
+			}
+			So(dg.FindCycle(), ShouldResemble, []node{b, b})
 		})
 	})
 }
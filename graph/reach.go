@@ -0,0 +1,55 @@
+package graph
+
+// Reachable returns the nodes reachable from the node, following at least one edge
+// The node itself is only included if it belongs to a cycle
+func (g Graph[T]) Reachable(from T) map[T]struct{} {
+	result := make(map[T]struct{})
+	stack := g.successors(from)
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if _, visited := result[node]; visited {
+			continue
+		}
+		result[node] = struct{}{}
+		stack = append(stack, g.successors(node)...)
+	}
+	return result
+}
+
+// IsReachable checks if a path (of at least one edge) goes from a node to another one
+func (g Graph[T]) IsReachable(from, to T) bool {
+	_, exists := g.Reachable(from)[to]
+	return exists
+}
+
+// TransitiveReduction returns a new graph with the same reachability and as few edges as possible
+// An edge a -> c is removed if another path goes from a to c (eg.: a -> b -> c)
+// Duplicated edges are removed, the order of the remaining edges is kept
+// Returns a *CycleError if the graph is cyclic (the reduction of a cyclic graph is not unique)
+func (g Graph[T]) TransitiveReduction() (Graph[T], error) {
+	if cycle := g.FindCycle(); cycle != nil {
+		return nil, &CycleError[T]{Path: cycle}
+	}
+
+	result := New[T]()
+	for from := range g {
+		successors := g.successors(from)
+		var kept []T
+		for _, to := range successors {
+			// Remove "to" if reachable through another successor
+			indirect := false
+			for _, other := range successors {
+				if other != to && g.IsReachable(other, to) {
+					indirect = true
+					break
+				}
+			}
+			if !indirect {
+				kept = append(kept, to)
+			}
+		}
+		result[from] = kept
+	}
+	return result, nil
+}
@@ -0,0 +1,145 @@
+package graph
+
+import (
+	"slices"
+)
+
+// sortedNodes returns all nodes of the graph, sorted using the comparison function (if defined)
+func (g Graph[T]) sortedNodes(cmp func(a, b T) int) []T {
+	nodes := g.Nodes()
+	if cmp != nil {
+		slices.SortStableFunc(nodes, cmp)
+	}
+	return nodes
+}
+
+// inDegrees counts the incoming edges of each node (duplicated edges are counted once)
+func (g Graph[T]) inDegrees(nodes []T) map[T]int {
+	result := make(map[T]int, len(nodes))
+	for _, node := range nodes {
+		result[node] += 0
+		for _, to := range g.successors(node) {
+			result[to]++
+		}
+	}
+	return result
+}
+
+// successors returns the distinct adjacent nodes, in their order of insertion
+func (g Graph[T]) successors(from T) []T {
+	var result []T
+	for _, to := range g[from] {
+		if !slices.Contains(result, to) {
+			result = append(result, to)
+		}
+	}
+	return result
+}
+
+// TopologicalSortFunc performs a stable topological sort (Kahn's algorithm)
+// Among the nodes ready to be sorted, the smallest one (using the comparison function) comes first
+// Eg.: with cmp.Compare on positions, the order of the positions is kept whenever possible
+// A nil comparison function appends the nodes as they get ready (the order of independent nodes is not defined)
+// Returns a *CycleError if a cycle is detected
+func (g Graph[T]) TopologicalSortFunc(cmp func(a, b T) int) ([]T, error) {
+	nodes := g.sortedNodes(cmp)
+	degrees := g.inDegrees(nodes)
+
+	var ready []T
+	for _, node := range nodes {
+		if degrees[node] == 0 {
+			ready = append(ready, node)
+		}
+	}
+
+	result := make([]T, 0, len(nodes))
+	for len(ready) > 0 {
+		node := ready[0]
+		ready = ready[1:]
+		result = append(result, node)
+		for _, to := range g.successors(node) {
+			degrees[to]--
+			if degrees[to] > 0 {
+				continue
+			}
+			if cmp == nil {
+				ready = append(ready, to)
+				continue
+			}
+			// Insert in order
+			pos, _ := slices.BinarySearchFunc(ready, to, cmp)
+			ready = slices.Insert(ready, pos, to)
+		}
+	}
+
+	if len(result) < len(nodes) {
+		return nil, g.cycleError(nodes, degrees)
+	}
+	return result, nil
+}
+
+// LayeredSort groups the nodes by layer: a node only depends on nodes of the previous layers
+// The first layer contains the nodes without incoming edge, the order of the nodes of a layer is not defined
+// Returns a *CycleError if a cycle is detected
+func (g Graph[T]) LayeredSort() ([][]T, error) {
+	return g.layeredSort(nil)
+}
+
+// LayeredSortFunc groups the nodes by layer, the nodes of a layer are sorted using the comparison function
+// Returns a *CycleError if a cycle is detected
+func (g Graph[T]) LayeredSortFunc(cmp func(a, b T) int) ([][]T, error) {
+	return g.layeredSort(cmp)
+}
+
+// layeredSort groups the nodes by layer, the nodes of a layer are sorted using the comparison function (if defined)
+func (g Graph[T]) layeredSort(cmp func(a, b T) int) ([][]T, error) {
+	nodes := g.sortedNodes(cmp)
+	degrees := g.inDegrees(nodes)
+
+	var layer []T
+	for _, node := range nodes {
+		if degrees[node] == 0 {
+			layer = append(layer, node)
+		}
+	}
+
+	var result [][]T
+	count := 0
+	for len(layer) > 0 {
+		result = append(result, layer)
+		count += len(layer)
+
+		var next []T
+		for _, node := range layer {
+			for _, to := range g.successors(node) {
+				degrees[to]--
+				if degrees[to] == 0 {
+					next = append(next, to)
+				}
+			}
+		}
+		if cmp != nil {
+			slices.SortStableFunc(next, cmp)
+		}
+		layer = next
+	}
+
+	if count < len(nodes) {
+		return nil, g.cycleError(nodes, degrees)
+	}
+	return result, nil
+}
+
+// cycleError describes a cycle among the nodes not sorted (remaining incoming edges)
+func (g Graph[T]) cycleError(nodes []T, degrees map[T]int) *CycleError[T] {
+	var remaining []T
+	for _, node := range nodes {
+		if degrees[node] > 0 {
+			remaining = append(remaining, node)
+		}
+	}
+	keep := func(node T) bool {
+		return degrees[node] > 0
+	}
+	return &CycleError[T]{Path: g.findCycle(remaining, keep)}
+}
@@ -0,0 +1,186 @@
+package graph_test
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/sbiemont/fugologic/graph"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// byID compares nodes by identifier
+func byID(a, b node) int {
+	return strings.Compare(a.id, b.id)
+}
+
+func TestStableSort(t *testing.T) {
+	a := node{id: "a"}
+	b := node{id: "b"}
+	c := node{id: "c"}
+	d := node{id: "d"}
+	e := node{id: "e"}
+
+	Convey("stable sort", t, func() {
+		Convey("when topological sort", func() {
+			// d -> b -> a
+			// e -> a, c
+			dg := graph.Graph[node]{
+				d: []node{b},
+				b: []node{a},
+				e: []node{a, c},
+			}
+			for i := 0; i < 10; i++ {
+				topo, err := dg.TopologicalSortFunc(byID)
+				So(err, ShouldBeNil)
+				So(topo, ShouldResemble, []node{d, b, e, a, c})
+			}
+
+			// Independent nodes are kept in order, duplicated edges are ignored
+			topo, err := graph.Graph[int]{3: nil, 1: []int{0, 0}, 2: nil}.TopologicalSortFunc(func(x, y int) int { return x - y })
+			So(err, ShouldBeNil)
+			So(topo, ShouldResemble, []int{1, 0, 2, 3})
+
+			// Without comparison function
+			topo, err = graph.New[int]().Add(1, 2, 3).TopologicalSortFunc(nil)
+			So(err, ShouldBeNil)
+			So(topo, ShouldResemble, []int{1, 2, 3})
+			nodes, err := dg.TopologicalSortFunc(nil)
+			So(err, ShouldBeNil)
+			So(nodes, ShouldHaveLength, 5)
+			So(slices.Index(nodes, d), ShouldBeLessThan, slices.Index(nodes, b))
+			So(slices.Index(nodes, b), ShouldBeLessThan, slices.Index(nodes, a))
+			So(slices.Index(nodes, e), ShouldBeLessThan, slices.Index(nodes, c))
+		})
+
+		Convey("when layered sort", func() {
+			// a, b -> c -> d
+			// a -> d, e
+			dg := graph.Graph[node]{
+				a: []node{c, d, e},
+				b: []node{c},
+				c: []node{d},
+			}
+			layers, err := dg.LayeredSortFunc(byID)
+			So(err, ShouldBeNil)
+			So(layers, ShouldResemble, [][]node{{a, b}, {c, e}, {d}})
+
+			layers, err = dg.LayeredSort()
+			So(err, ShouldBeNil)
+			So(layers, ShouldHaveLength, 3)
+			So(layers[2], ShouldResemble, []node{d})
+		})
+
+		Convey("when cycle", func() {
+			// a -> b -> c -> b, c -> d
+			dg := graph.Graph[node]{
+				a: []node{b},
+				b: []node{c},
+				c: []node{b, d},
+			}
+			_, err := dg.TopologicalSortFunc(byID)
+			So(err, ShouldBeError, "cycle detected: {b} -> {c} -> {b}")
+			So(errors.Is(err, graph.ErrCyclicGraph), ShouldBeTrue)
+
+			_, err = dg.LayeredSortFunc(byID)
+			So(err, ShouldBeError, "cycle detected: {b} -> {c} -> {b}")
+		})
+	})
+}
+
+func TestComponents(t *testing.T) {
+	a := node{id: "a"}
+	b := node{id: "b"}
+	c := node{id: "c"}
+	d := node{id: "d"}
+	e := node{id: "e"}
+
+	Convey("strongly connected components", t, func() {
+		// a -> b -> c -> a
+		// c -> d -> d
+		// d -> e
+		dg := graph.Graph[node]{
+			a: []node{b},
+			b: []node{c},
+			c: []node{a, d},
+			d: []node{d, e},
+		}
+
+		Convey("when components", func() {
+			components := dg.StronglyConnectedComponentsFunc(byID)
+			So(components, ShouldResemble, [][]node{{e}, {d}, {c, b, a}})
+			So(dg.StronglyConnectedComponents(), ShouldHaveLength, 3)
+		})
+
+		Convey("when cycles", func() {
+			So(dg.CyclesFunc(byID), ShouldResemble, [][]node{{a, b, c, a}, {d, d}})
+			So(dg.Cycles(), ShouldHaveLength, 2)
+			So(graph.Graph[node]{a: []node{b}}.Cycles(), ShouldBeEmpty)
+		})
+
+		Convey("when several cycles in a component", func() {
+			// a -> b -> a
+			// b -> c -> a, b
+			// c -> d -> c
+			dg := graph.Graph[node]{
+				a: []node{b},
+				b: []node{c, a},
+				c: []node{b, a, d},
+				d: []node{c},
+			}
+			So(dg.StronglyConnectedComponents(), ShouldHaveLength, 1)
+			So(dg.CyclesFunc(byID), ShouldResemble, [][]node{
+				{a, b, a},
+				{a, b, c, a},
+				{b, c, b},
+				{c, d, c},
+			})
+			So(dg.Cycles(), ShouldHaveLength, 4)
+		})
+	})
+}
+
+func TestReachability(t *testing.T) {
+	a := node{id: "a"}
+	b := node{id: "b"}
+	c := node{id: "c"}
+	d := node{id: "d"}
+
+	Convey("reachability", t, func() {
+		Convey("when reachable", func() {
+			// a -> b -> c, d -> d
+			dg := graph.Graph[node]{
+				a: []node{b},
+				b: []node{c},
+				d: []node{d},
+			}
+			So(dg.Reachable(a), ShouldResemble, map[node]struct{}{b: {}, c: {}})
+			So(dg.Reachable(c), ShouldBeEmpty)
+			So(dg.Reachable(d), ShouldResemble, map[node]struct{}{d: {}})
+			So(dg.IsReachable(a, c), ShouldBeTrue)
+			So(dg.IsReachable(c, a), ShouldBeFalse)
+			So(dg.IsReachable(a, a), ShouldBeFalse)
+		})
+
+		Convey("when transitive reduction", func() {
+			// a -> b -> c -> d
+			// a -> c, d
+			dg := graph.Graph[node]{
+				a: []node{b, c, d, b},
+				b: []node{c},
+				c: []node{d},
+			}
+			reduced, err := dg.TransitiveReduction()
+			So(err, ShouldBeNil)
+			So(reduced, ShouldResemble, graph.Graph[node]{
+				a: []node{b},
+				b: []node{c},
+				c: []node{d},
+			})
+
+			_, err = graph.Graph[node]{a: []node{b}, b: []node{a}}.TransitiveReduction()
+			So(errors.Is(err, graph.ErrCyclicGraph), ShouldBeTrue)
+		})
+	})
+}
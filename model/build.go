@@ -0,0 +1,345 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/sbiemont/fugologic/builder"
+	"github.com/sbiemont/fugologic/crisp"
+	"github.com/sbiemont/fugologic/fuzzy"
+	"github.com/sbiemont/fugologic/id"
+)
+
+// Values gathers the fuzzy values of a model by identifier
+type Values map[id.ID]*fuzzy.IDVal
+
+// Build validates the model and creates the fuzzy system and its values
+func (m Model) Build() (fuzzy.System, Values, error) {
+	if m.Version != Version {
+		return nil, nil, fmt.Errorf("model: version %d not supported (expected: %d)", m.Version, Version)
+	}
+
+	values, err := m.buildValues()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(m.Engines) == 0 {
+		return nil, nil, fmt.Errorf("model: at least 1 engine expected")
+	}
+	engines := make([]fuzzy.Engine, len(m.Engines))
+	for i, eng := range m.Engines {
+		engines[i], err = eng.build(values)
+		if err != nil {
+			return nil, nil, fmt.Errorf("model: engines[%d]%w", i, err)
+		}
+	}
+
+	sys, err := fuzzy.NewSystem(engines)
+	if err != nil {
+		return nil, nil, fmt.Errorf("model: %w", err)
+	}
+	return sys, values, nil
+}
+
+// buildValues creates all fuzzy values and checks for duplicated identifiers
+func (m Model) buildValues() (Values, error) {
+	values := make(Values, len(m.Values))
+	for i, value := range m.Values {
+		idVal, err := value.build()
+		if err != nil {
+			return nil, fmt.Errorf("model: values[%d]: %w", i, err)
+		}
+		if _, exists := values[idVal.ID()]; exists {
+			return nil, fmt.Errorf("model: values[%d]: value `%s` already defined", i, value.ID)
+		}
+		values[idVal.ID()] = idVal
+	}
+	return values, nil
+}
+
+// build creates the fuzzy value
+func (value Value) build() (*fuzzy.IDVal, error) {
+	u, err := value.Universe.build()
+	if err != nil {
+		return nil, err
+	}
+
+	builders := make(map[id.ID]fuzzy.SetBuilder, len(value.Sets))
+	names := make([]id.ID, 0, len(value.Sets))
+	for j, set := range value.Sets {
+		if set.ID == "" {
+			return nil, fmt.Errorf("sets[%d]: id set cannot be empty", j)
+		}
+		if _, exists := builders[id.ID(set.ID)]; exists {
+			return nil, fmt.Errorf("sets[%d]: set `%s` already defined", j, set.ID)
+		}
+		builder, err := fuzzy.NewSetBuilder(set.Type, set.Params...)
+		if err != nil {
+			return nil, fmt.Errorf("sets[%d]: %w", j, err)
+		}
+		builders[id.ID(set.ID)] = builder
+		names = append(names, id.ID(set.ID))
+	}
+
+	return fuzzy.NewIDValBuildersOrdered(id.ID(value.ID), u, names, builders)
+}
+
+// build creates the crisp set
+func (u Universe) build() (crisp.Set, error) {
+	switch {
+	case u.Dx != 0 && u.N != 0:
+		return crisp.Set{}, fmt.Errorf("universe: dx and n cannot be both defined")
+	case u.N != 0:
+		return crisp.NewSetN(u.XMin, u.XMax, u.N)
+	default:
+		return crisp.NewSet(u.XMin, u.XMax, u.Dx)
+	}
+}
+
+// config returns the engine configuration, using Mamdani for empty items
+func (eng Engine) config() (builder.Config, error) {
+	cfg := builder.Mamdani()
+	if eng.Operator != "" {
+		optr, ok := fuzzy.OperatorByName(eng.Operator)
+		if !ok {
+			return builder.Config{}, fmt.Errorf("operator `%s` unknown", eng.Operator)
+		}
+		cfg.Optr = optr
+	}
+	if eng.Implication != "" {
+		impl, ok := fuzzy.ImplicationByName(eng.Implication)
+		if !ok {
+			return builder.Config{}, fmt.Errorf("implication `%s` unknown", eng.Implication)
+		}
+		cfg.Impl = impl
+	}
+	if eng.Aggregation != "" {
+		agg, ok := fuzzy.AggregationByName(eng.Aggregation)
+		if !ok {
+			return builder.Config{}, fmt.Errorf("aggregation `%s` unknown", eng.Aggregation)
+		}
+		cfg.Agg = agg
+	}
+	if eng.Defuzzification != "" {
+		defuzz, ok := fuzzy.DefuzzificationByName(eng.Defuzzification)
+		if !ok {
+			return builder.Config{}, fmt.Errorf("defuzzification `%s` unknown", eng.Defuzzification)
+		}
+		cfg.Defuzz = defuzz
+	}
+	return cfg, nil
+}
+
+// build creates the engine from the explicit rules and the fuzzy associative matrices
+// Returned errors start with the path of the wrong item
+func (eng Engine) build(values Values) (fuzzy.Engine, error) {
+	cfg, err := eng.config()
+	if err != nil {
+		return fuzzy.Engine{}, fmt.Errorf(": %w", err)
+	}
+	if len(eng.Rules) == 0 && len(eng.FAMs) == 0 {
+		return fuzzy.Engine{}, fmt.Errorf(": at least 1 rule or 1 fam expected")
+	}
+
+	// Explicit rules
+	rules := make([]fuzzy.Rule, len(eng.Rules))
+	for i, rule := range eng.Rules {
+		rules[i], err = rule.build(values, cfg)
+		if err != nil {
+			return fuzzy.Engine{}, fmt.Errorf(".rules[%d]%w", i, err)
+		}
+	}
+
+	// Matrices
+	for i, fam := range eng.FAMs {
+		famRules, err := fam.build(values, cfg)
+		if err != nil {
+			return fuzzy.Engine{}, fmt.Errorf(".fams[%d]: %w", i, err)
+		}
+		rules = append(rules, famRules...)
+	}
+
+	result, err := fuzzy.NewEngine(rules, cfg.Agg, cfg.Defuzz, fuzzy.WithID(id.ID(eng.ID)), fuzzy.WithDescription(eng.Description))
+	if err != nil {
+		return fuzzy.Engine{}, fmt.Errorf(": %w", err)
+	}
+	return result, nil
+}
+
+// build creates the rule
+func (rule Rule) build(values Values, cfg builder.Config) (fuzzy.Rule, error) {
+	premise, err := rule.If.build(values, cfg.Optr)
+	if err != nil {
+		return fuzzy.Rule{}, fmt.Errorf(".if%w", err)
+	}
+
+	if len(rule.Then) == 0 {
+		return fuzzy.Rule{}, fmt.Errorf(".then: at least 1 consequence expected")
+	}
+	outputs := make([]fuzzy.IDSet, len(rule.Then))
+	for i, term := range rule.Then {
+		outputs[i], err = term.build(values)
+		if err != nil {
+			return fuzzy.Rule{}, fmt.Errorf(".then[%d]: %w", i, err)
+		}
+	}
+
+	result := fuzzy.NewRule(premise, cfg.Impl, outputs)
+	if rule.Weight != nil {
+		if *rule.Weight < 0 || *rule.Weight > 1 {
+			return fuzzy.Rule{}, fmt.Errorf(".weight: %v shall be in [0, 1]", *rule.Weight)
+		}
+		result = result.WithWeight(*rule.Weight)
+	}
+	return result, nil
+}
+
+// build fetches the fuzzy set
+func (term Term) build(values Values) (fuzzy.IDSet, error) {
+	idVal, ok := values[id.ID(term.Value)]
+	if !ok {
+		return fuzzy.IDSet{}, fmt.Errorf("unknown value `%s`", term.Value)
+	}
+	idSet, ok := idVal.Fetch(id.ID(term.Set))
+	if !ok {
+		return fuzzy.IDSet{}, fmt.Errorf("unknown set `%s` for value `%s`", term.Set, term.Value)
+	}
+	return idSet, nil
+}
+
+// build creates the premise and applies the optional hedge
+// Returned errors start with the path of the wrong item
+func (premise Premise) build(values Values, optr fuzzy.Operator) (fuzzy.Premise, error) {
+	if premise.Hedge == "" {
+		return premise.buildItem(values, optr)
+	}
+
+	hedge, ok := fuzzy.HedgeByName(premise.Hedge)
+	if !ok {
+		return nil, fmt.Errorf(".hedge: `%s` unknown", premise.Hedge)
+	}
+	result, err := premise.buildItem(values, optr)
+	if err != nil {
+		return nil, err
+	}
+	if exp, ok := result.(fuzzy.Expression); ok && exp.Hedge() == nil && !exp.Complement() {
+		return exp.WithHedge(hedge), nil
+	}
+	return fuzzy.NewExpression([]fuzzy.Premise{result}, nil).WithHedge(hedge), nil
+}
+
+// buildItem creates the premise without hedge
+// Returned errors start with the path of the wrong item
+func (premise Premise) buildItem(values Values, optr fuzzy.Operator) (fuzzy.Premise, error) {
+	// Only one item is expected
+	defined := 0
+	for _, isDefined := range []bool{
+		premise.Value != "" || premise.Set != "",
+		premise.And != nil,
+		premise.Or != nil,
+		premise.XOr != nil,
+		premise.Not != nil,
+	} {
+		if isDefined {
+			defined++
+		}
+	}
+	if defined != 1 {
+		return nil, fmt.Errorf(": only one of term, and, or, xor, not expected")
+	}
+
+	// Connect sub-premises
+	connect := func(name string, subs []Premise, cnt fuzzy.Connective) (fuzzy.Premise, error) {
+		if len(subs) < 2 {
+			return nil, fmt.Errorf(".%s: at least 2 premises expected", name)
+		}
+		premises := make([]fuzzy.Premise, len(subs))
+		for i, sub := range subs {
+			var err error
+			premises[i], err = sub.build(values, optr)
+			if err != nil {
+				return nil, fmt.Errorf(".%s[%d]%w", name, i, err)
+			}
+		}
+		return fuzzy.NewOperatorExpression(premises, optr, cnt), nil
+	}
+
+	switch {
+	case premise.And != nil:
+		return connect("and", premise.And, fuzzy.ConnectiveAnd)
+	case premise.Or != nil:
+		return connect("or", premise.Or, fuzzy.ConnectiveOr)
+	case premise.XOr != nil:
+		return connect("xor", premise.XOr, fuzzy.ConnectiveXOr)
+	case premise.Not != nil:
+		sub, err := premise.Not.build(values, optr)
+		if err != nil {
+			return nil, fmt.Errorf(".not%w", err)
+		}
+		if exp, ok := sub.(fuzzy.Expression); ok {
+			return exp.Not(), nil
+		}
+		return fuzzy.NewExpression([]fuzzy.Premise{sub}, nil).Not(), nil
+	default:
+		idSet, err := Term{Value: premise.Value, Set: premise.Set}.build(values)
+		if err != nil {
+			return nil, fmt.Errorf(": %w", err)
+		}
+		return idSet, nil
+	}
+}
+
+// build creates the rules of the matrix
+func (fam FAM) build(values Values, cfg builder.Config) ([]fuzzy.Rule, error) {
+	fetch := func(name, value string) (*fuzzy.IDVal, error) {
+		idVal, ok := values[id.ID(value)]
+		if !ok {
+			return nil, fmt.Errorf("'%s' statement, unknown value `%s`", name, value)
+		}
+		return idVal, nil
+	}
+
+	ifVal, err := fetch("if", fam.If)
+	if err != nil {
+		return nil, err
+	}
+	andVal, err := fetch("and", fam.And)
+	if err != nil {
+		return nil, err
+	}
+	thenVal, err := fetch("then", fam.Then)
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make([]id.ID, len(fam.Columns))
+	for i, column := range fam.Columns {
+		columns[i] = id.ID(column)
+	}
+
+	// Rows sorted by header, for a reproducible order of the rules
+	headers := make([]string, 0, len(fam.Rows))
+	for header := range fam.Rows {
+		headers = append(headers, header)
+	}
+	sort.Strings(headers)
+	rows := make([]builder.FamRow, len(headers))
+	for i, header := range headers {
+		cells := make([]id.ID, len(fam.Rows[header]))
+		for j, cell := range fam.Rows[header] {
+			cells[j] = id.ID(cell)
+		}
+		rows[i] = builder.FamRow{Header: id.ID(header), Cells: cells}
+	}
+
+	bld := cfg.FuzzyAssoMatrix()
+	if err := bld.Asso(ifVal, andVal, thenVal).Rows(columns, rows...); err != nil {
+		return nil, err
+	}
+	eng, err := bld.Engine()
+	if err != nil {
+		return nil, err
+	}
+	return eng.Rules(), nil
+}
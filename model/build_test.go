@@ -0,0 +1,328 @@
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sbiemont/fugologic/builder"
+	"github.com/sbiemont/fugologic/crisp"
+	"github.com/sbiemont/fugologic/fuzzy"
+	"github.com/sbiemont/fugologic/id"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const testJSON = `{
+  "version": 1,
+  "values": [
+    {
+      "id": "a",
+      "universe": {"xmin": 0, "xmax": 1, "n": 11},
+      "sets": [
+        {"id": "a1", "type": "step-down", "params": [0, 0.5]},
+        {"id": "a2", "type": "tri", "params": [0, 0.5, 1]},
+        {"id": "a3", "type": "step-up", "params": [0.5, 1]}
+      ]
+    },
+    {
+      "id": "b",
+      "universe": {"xmin": 0, "xmax": 1, "dx": 0.1},
+      "sets": [
+        {"id": "b1", "type": "step-down", "params": [0, 1]},
+        {"id": "b2", "type": "step-up", "params": [0, 1]}
+      ]
+    },
+    {
+      "id": "c",
+      "universe": {"xmin": 0, "xmax": 1, "dx": 0.1},
+      "sets": [
+        {"id": "c1", "type": "step-down", "params": [0, 1]},
+        {"id": "c2", "type": "step-up", "params": [0, 1]}
+      ]
+    },
+    {
+      "id": "d",
+      "universe": {"xmin": 0, "xmax": 1, "dx": 0.1},
+      "sets": [
+        {"id": "d1", "type": "gauss", "params": [0.2, 0]},
+        {"id": "d2", "type": "gauss", "params": [0.2, 1]}
+      ]
+    }
+  ],
+  "engines": [
+    {
+      "rules": [
+        {"if": {"value": "c", "set": "c1"}, "then": [{"value": "d", "set": "d1"}]},
+        {"if": {"not": {"value": "c", "set": "c1"}}, "then": [{"value": "d", "set": "d2"}]}
+      ]
+    },
+    {
+      "operator": "zadeh",
+      "implication": "min",
+      "aggregation": "union",
+      "defuzzification": "centroid",
+      "rules": [
+        {
+          "if": {"or": [
+            {"value": "a", "set": "a1"},
+            {"and": [{"value": "a", "set": "a2"}, {"value": "b", "set": "b1"}]}
+          ]},
+          "then": [{"value": "c", "set": "c1"}]
+        }
+      ],
+      "fams": [
+        {
+          "if": "a",
+          "and": "b",
+          "then": "c",
+          "columns": ["a1", "a2", "a3"],
+          "rows": {
+            "b1": ["c1", "c1", "c2"],
+            "b2": ["c1", "c2", "c2"]
+          }
+        }
+      ]
+    }
+  ]
+}`
+
+const testYAML = `
+version: 1
+values:
+  - id: a
+    universe: {xmin: 0, xmax: 1, n: 11}
+    sets:
+      - {id: a1, type: step-down, params: [0, 0.5]}
+      - {id: a2, type: tri, params: [0, 0.5, 1]}
+      - {id: a3, type: step-up, params: [0.5, 1]}
+  - id: b
+    universe: {xmin: 0, xmax: 1, dx: 0.1}
+    sets:
+      - {id: b1, type: step-down, params: [0, 1]}
+      - {id: b2, type: step-up, params: [0, 1]}
+  - id: c
+    universe: {xmin: 0, xmax: 1, dx: 0.1}
+    sets:
+      - {id: c1, type: step-down, params: [0, 1]}
+      - {id: c2, type: step-up, params: [0, 1]}
+  - id: d
+    universe: {xmin: 0, xmax: 1, dx: 0.1}
+    sets:
+      - {id: d1, type: gauss, params: [0.2, 0]}
+      - {id: d2, type: gauss, params: [0.2, 1]}
+engines:
+  - rules:
+      - if: {value: c, set: c1}
+        then: [{value: d, set: d1}]
+      - if: {not: {value: c, set: c1}}
+        then: [{value: d, set: d2}]
+  - operator: zadeh
+    implication: min
+    aggregation: union
+    defuzzification: centroid
+    rules:
+      - if:
+          or:
+            - {value: a, set: a1}
+            - and: [{value: a, set: a2}, {value: b, set: b1}]
+        then: [{value: c, set: c1}]
+    fams:
+      - if: a
+        and: b
+        then: c
+        columns: [a1, a2, a3]
+        rows:
+          b1: [c1, c1, c2]
+          b2: [c1, c2, c2]
+`
+
+func TestRead(t *testing.T) {
+	Convey("read", t, func() {
+		Convey("when json and yaml", func() {
+			mJSON, err := ReadJSON(strings.NewReader(testJSON))
+			So(err, ShouldBeNil)
+			mYAML, err := ReadYAML(strings.NewReader(testYAML))
+			So(err, ShouldBeNil)
+			So(mYAML, ShouldResemble, mJSON)
+			So(mJSON.Values, ShouldHaveLength, 4)
+			So(mJSON.Engines, ShouldHaveLength, 2)
+		})
+
+		Convey("when unknown field", func() {
+			_, err := ReadJSON(strings.NewReader(`{"version": 1, "unknown": 0}`))
+			So(err, ShouldBeError, `model: json: unknown field "unknown"`)
+
+			_, err = ReadYAML(strings.NewReader("version: 1\nunknown: 0\n"))
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, "field unknown not found")
+		})
+	})
+}
+
+func TestBuild(t *testing.T) {
+	Convey("build", t, func() {
+		Convey("when ok", func() {
+			m, err := ReadJSON(strings.NewReader(testJSON))
+			So(err, ShouldBeNil)
+			sys, values, err := m.Build()
+			So(err, ShouldBeNil)
+			So(sys, ShouldHaveLength, 2)
+			So(values, ShouldHaveLength, 4)
+			So(values["a"].U().Values(), ShouldHaveLength, 11)
+
+			// Rules of the matrix, rows sorted by header
+			var famRules []string
+			for _, rule := range sys[0].Rules()[1:] {
+				famRules = append(famRules, rule.String())
+			}
+			So(famRules, ShouldResemble, []string{
+				"IF a1 AND b1 THEN c1",
+				"IF a1 AND b2 THEN c1",
+				"IF a2 AND b1 THEN c1",
+				"IF a2 AND b2 THEN c2",
+				"IF a3 AND b1 THEN c2",
+				"IF a3 AND b2 THEN c2",
+			})
+
+			// Same engines using builders
+			newVal := func(name id.ID, builders map[id.ID]fuzzy.SetBuilder) *fuzzy.IDVal {
+				u, err := crisp.NewSet(0, 1, 0.1)
+				So(err, ShouldBeNil)
+				idVal, err := fuzzy.NewIDValBuilders(name, u, builders)
+				So(err, ShouldBeNil)
+				return idVal
+			}
+			fvA := newVal("a", map[id.ID]fuzzy.SetBuilder{
+				"a1": fuzzy.StepDown{A: 0, B: 0.5},
+				"a2": fuzzy.Triangular{A: 0, B: 0.5, C: 1},
+				"a3": fuzzy.StepUp{A: 0.5, B: 1},
+			})
+			fvB := newVal("b", map[id.ID]fuzzy.SetBuilder{"b1": fuzzy.StepDown{A: 0, B: 1}, "b2": fuzzy.StepUp{A: 0, B: 1}})
+			fvC := newVal("c", map[id.ID]fuzzy.SetBuilder{"c1": fuzzy.StepDown{A: 0, B: 1}, "c2": fuzzy.StepUp{A: 0, B: 1}})
+			fvD := newVal("d", map[id.ID]fuzzy.SetBuilder{"d1": fuzzy.Gauss{Sigma: 0.2, C: 0}, "d2": fuzzy.Gauss{Sigma: 0.2, C: 1}})
+
+			bld1 := builder.Mamdani().FuzzyLogic()
+			bld1.If(fvC.Get("c1")).Then(fvD.Get("d1"))
+			bld1.If(fvC.Get("c1")).Not().Then(fvD.Get("d2"))
+			eng1, err := bld1.Engine()
+			So(err, ShouldBeNil)
+
+			bld2 := builder.Mamdani().FuzzyAssoMatrix()
+			So(bld2.Asso(fvA, fvB, fvC).Matrix(
+				[]id.ID{"a1", "a2", "a3"},
+				map[id.ID][]id.ID{
+					"b1": {"c1", "c1", "c2"},
+					"b2": {"c1", "c2", "c2"},
+				},
+			), ShouldBeNil)
+			eng2, err := bld2.Engine()
+			So(err, ShouldBeNil)
+			bld3 := builder.Mamdani().FuzzyLogic()
+			bld3.If(fvA.Get("a1")).Or(bld3.If(fvA.Get("a2")).And(fvB.Get("b1"))).Then(fvC.Get("c1"))
+			eng3, err := bld3.Engine()
+			So(err, ShouldBeNil)
+			eng23, err := fuzzy.NewEngine(append(eng3.Rules(), eng2.Rules()...), fuzzy.AggregationUnion, fuzzy.DefuzzificationCentroid)
+			So(err, ShouldBeNil)
+			expectedSys, err := fuzzy.NewSystem([]fuzzy.Engine{eng1, eng23})
+			So(err, ShouldBeNil)
+
+			for _, in := range [][2]float64{{0, 0}, {0.2, 0.7}, {0.5, 0.5}, {0.9, 0.1}, {1, 1}} {
+				result, err := sys.Evaluate(fuzzy.DataInput{values["a"]: in[0], values["b"]: in[1]})
+				So(err, ShouldBeNil)
+				expected, err := expectedSys.Evaluate(fuzzy.DataInput{fvA: in[0], fvB: in[1]})
+				So(err, ShouldBeNil)
+				So(result[values["c"]], ShouldAlmostEqual, expected[fvC])
+				So(result[values["d"]], ShouldAlmostEqual, expected[fvD])
+			}
+		})
+
+		build := func(text string) error {
+			m, err := ReadJSON(strings.NewReader(text))
+			So(err, ShouldBeNil)
+			_, _, err = m.Build()
+			return err
+		}
+
+		const value = `{"id": "a", "universe": {"xmin": 0, "xmax": 1, "dx": 0.1}, "sets": [{"id": "a1", "type": "tri", "params": [0, 0.5, 1]}]}`
+		const term = `{"value": "a", "set": "a1"}`
+
+		Convey("when ordered terms", func() {
+			idVal, err := Value{
+				ID:       "t",
+				Universe: Universe{XMin: 0, XMax: 1, N: 11},
+				Sets: []Set{
+					{ID: "low", Type: "step-down", Params: []float64{0, 0.5}},
+					{ID: "medium", Type: "tri", Params: []float64{0, 0.5, 1}},
+					{ID: "high", Type: "step-up", Params: []float64{0.5, 1}},
+				},
+			}.build()
+			So(err, ShouldBeNil)
+			So(idVal.Index("low"), ShouldEqual, 0)
+			So(idVal.Index("medium"), ShouldEqual, 1)
+			So(idVal.Index("high"), ShouldEqual, 2)
+		})
+
+		Convey("when version", func() {
+			So(build(`{"version": 2}`), ShouldBeError, "model: version 2 not supported (expected: 1)")
+		})
+
+		Convey("when values", func() {
+			So(build(`{"version": 1, "values": [`+value+`, `+value+`]}`), ShouldBeError,
+				"model: values[1]: value `a` already defined")
+			So(build(`{"version": 1, "values": [{"id": "a", "universe": {"xmin": 0, "xmax": 1, "dx": 0.1, "n": 5}}]}`), ShouldBeError,
+				"model: values[0]: universe: dx and n cannot be both defined")
+			So(build(`{"version": 1, "values": [{"id": "a", "universe": {"xmin": 0, "xmax": 1}}]}`), ShouldBeError,
+				"model: values[0]: crisp set: dx shall be > 0")
+			So(build(`{"version": 1, "values": [{"id": "a", "universe": {"xmin": 0, "xmax": 1, "n": 5}, "sets": [{"id": "a1", "type": "unknown"}]}]}`), ShouldBeError,
+				"model: values[0]: sets[0]: set builder `unknown` unknown")
+			So(build(`{"version": 1, "values": [{"id": "a", "universe": {"xmin": 0, "xmax": 1, "n": 5}, "sets": [{"id": "a1", "type": "tri", "params": [0, 1]}]}]}`), ShouldBeError,
+				"model: values[0]: sets[0]: tri: 3 parameters expected (found: 2)")
+			So(build(`{"version": 1, "values": [{"id": "a", "universe": {"xmin": 0, "xmax": 1, "n": 5}, "sets": [{"id": "a1", "type": "tri", "params": [0, 0.5, 1]}, {"id": "a1", "type": "tri", "params": [0, 0.5, 1]}]}]}`), ShouldBeError,
+				"model: values[0]: sets[1]: set `a1` already defined")
+		})
+
+		Convey("when engines", func() {
+			So(build(`{"version": 1, "values": [`+value+`]}`), ShouldBeError, "model: at least 1 engine expected")
+			So(build(`{"version": 1, "values": [`+value+`], "engines": [{}]}`), ShouldBeError,
+				"model: engines[0]: at least 1 rule or 1 fam expected")
+			So(build(`{"version": 1, "values": [`+value+`], "engines": [{"operator": "unknown"}]}`), ShouldBeError,
+				"model: engines[0]: operator `unknown` unknown")
+			So(build(`{"version": 1, "values": [`+value+`], "engines": [{"defuzzification": "unknown"}]}`), ShouldBeError,
+				"model: engines[0]: defuzzification `unknown` unknown")
+
+			// System errors use the engine identifiers
+			engine := `{"id": "loop", "rules": [{"if": ` + term + `, "then": [` + term + `]}]}`
+			So(build(`{"version": 1, "values": [`+value+`], "engines": [`+engine+`]}`), ShouldBeError,
+				"model: cycle detected: engine `loop` -> `a` -> engine `loop`")
+			So(build(`{"version": 1, "values": [`+value+`], "engines": [`+engine+`, `+engine+`]}`), ShouldBeError,
+				"model: engine `loop` defined twice")
+		})
+
+		Convey("when rules", func() {
+			rule := func(premise, then string) string {
+				return `{"version": 1, "values": [` + value + `], "engines": [{"rules": [{"if": ` + premise + `, "then": ` + then + `}]}]}`
+			}
+			So(build(rule(`{"value": "x", "set": "a1"}`, `[`+term+`]`)), ShouldBeError,
+				"model: engines[0].rules[0].if: unknown value `x`")
+			So(build(rule(`{"and": [`+term+`, {"not": {"value": "a", "set": "x"}}]}`, `[`+term+`]`)), ShouldBeError,
+				"model: engines[0].rules[0].if.and[1].not: unknown set `x` for value `a`")
+			So(build(rule(`{"or": [`+term+`]}`, `[`+term+`]`)), ShouldBeError,
+				"model: engines[0].rules[0].if.or: at least 2 premises expected")
+			So(build(rule(`{"value": "a", "set": "a1", "xor": [`+term+`, `+term+`]}`, `[`+term+`]`)), ShouldBeError,
+				"model: engines[0].rules[0].if: only one of term, and, or, xor, not expected")
+			So(build(rule(`{"hedge": "unknown", "value": "a", "set": "a1"}`, `[`+term+`]`)), ShouldBeError,
+				"model: engines[0].rules[0].if.hedge: `unknown` unknown")
+			So(build(`{"version": 1, "values": [`+value+`], "engines": [{"rules": [{"if": `+term+`, "then": [`+term+`], "weight": 1.5}]}]}`), ShouldBeError,
+				"model: engines[0].rules[0].weight: 1.5 shall be in [0, 1]")
+			So(build(rule(term, `[]`)), ShouldBeError,
+				"model: engines[0].rules[0].then: at least 1 consequence expected")
+			So(build(rule(term, `[{"value": "b", "set": "b1"}]`)), ShouldBeError,
+				"model: engines[0].rules[0].then[0]: unknown value `b`")
+		})
+
+		Convey("when fams", func() {
+			So(build(`{"version": 1, "values": [`+value+`], "engines": [{"fams": [{"if": "a", "and": "b", "then": "a"}]}]}`), ShouldBeError,
+				"model: engines[0].fams[0]: 'and' statement, unknown value `b`")
+		})
+	})
+}
@@ -0,0 +1,146 @@
+// Package model describes fuzzy systems as data (JSON or YAML)
+//
+// A model is versioned and gathers:
+//   - the fuzzy values: identifier, crisp universe and fuzzy sets
+//   - the engines: configuration, explicit rules and fuzzy associative matrices
+//
+// All engines are grouped into one fuzzy system
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Version is the current version of the model schema
+const Version = 1
+
+// Model is the declarative description of a fuzzy system
+type Model struct {
+	Version int      `json:"version" yaml:"version"`
+	Values  []Value  `json:"values" yaml:"values"`
+	Engines []Engine `json:"engines" yaml:"engines"`
+}
+
+// Value describes a fuzzy value
+type Value struct {
+	ID       string   `json:"id" yaml:"id"`
+	Universe Universe `json:"universe" yaml:"universe"`
+	Sets     []Set    `json:"sets" yaml:"sets"`
+}
+
+// Universe describes a crisp set using either a step (dx) or a number of values (n)
+type Universe struct {
+	XMin float64 `json:"xmin" yaml:"xmin"`
+	XMax float64 `json:"xmax" yaml:"xmax"`
+	Dx   float64 `json:"dx,omitempty" yaml:"dx,omitempty"`
+	N    int     `json:"n,omitempty" yaml:"n,omitempty"`
+}
+
+// Set describes a fuzzy set using a set builder name (see. fuzzy.GAUSS, fuzzy.TRI, ...) and its parameters
+type Set struct {
+	ID     string    `json:"id" yaml:"id"`
+	Type   string    `json:"type" yaml:"type"`
+	Params []float64 `json:"params" yaml:"params"`
+}
+
+// Engine describes an engine configuration and its rules
+// Empty configuration items use the Mamdani configuration (see. builder.Mamdani)
+type Engine struct {
+	ID              string `json:"id,omitempty" yaml:"id,omitempty"`                           // identifier used in errors (optional)
+	Description     string `json:"description,omitempty" yaml:"description,omitempty"`         // free text (optional)
+	Operator        string `json:"operator,omitempty" yaml:"operator,omitempty"`               // "zadeh", "hyperbolic"
+	Implication     string `json:"implication,omitempty" yaml:"implication,omitempty"`         // "min", "prod"
+	Aggregation     string `json:"aggregation,omitempty" yaml:"aggregation,omitempty"`         // "union", "intersection"
+	Defuzzification string `json:"defuzzification,omitempty" yaml:"defuzzification,omitempty"` // "centroid", "bisector", ...
+	Rules           []Rule `json:"rules,omitempty" yaml:"rules,omitempty"`
+	FAMs            []FAM  `json:"fams,omitempty" yaml:"fams,omitempty"`
+}
+
+// Rule describes a rule: if <premise> then <consequences> [with <weight>]
+// The weight is optional (1 by default) and shall be in [0, 1]
+type Rule struct {
+	If     Premise  `json:"if" yaml:"if"`
+	Then   []Term   `json:"then" yaml:"then"`
+	Weight *float64 `json:"weight,omitempty" yaml:"weight,omitempty"`
+}
+
+// Term designates a fuzzy set of a fuzzy value
+type Term struct {
+	Value string `json:"value" yaml:"value"`
+	Set   string `json:"set" yaml:"set"`
+}
+
+// Premise describes an expression, only one item shall be defined
+//   - a term: {"value": "a", "set": "a1"}
+//   - a list of connected premises: {"and": [...]}, {"or": [...]}, {"xor": [...]}
+//   - a complemented premise: {"not": {...}}
+//
+// An optional hedge ("very", "somewhat", "extremely") modifies the item: {"hedge": "very", "value": "a", "set": "a1"}
+type Premise struct {
+	Hedge string    `json:"hedge,omitempty" yaml:"hedge,omitempty"`
+	Value string    `json:"value,omitempty" yaml:"value,omitempty"`
+	Set   string    `json:"set,omitempty" yaml:"set,omitempty"`
+	And   []Premise `json:"and,omitempty" yaml:"and,omitempty"`
+	Or    []Premise `json:"or,omitempty" yaml:"or,omitempty"`
+	XOr   []Premise `json:"xor,omitempty" yaml:"xor,omitempty"`
+	Not   *Premise  `json:"not,omitempty" yaml:"not,omitempty"`
+}
+
+// FAM describes a fuzzy associative matrix (see. builder.FuzzyAssoMatrix)
+// if <if> and <and> then <then>
+type FAM struct {
+	If      string              `json:"if" yaml:"if"`
+	And     string              `json:"and" yaml:"and"`
+	Then    string              `json:"then" yaml:"then"`
+	Columns []string            `json:"columns" yaml:"columns"` // sets of the <if> value
+	Rows    map[string][]string `json:"rows" yaml:"rows"`       // sets of the <and> value => sets of the <then> value
+}
+
+// ReadJSON decodes a JSON model (unknown fields are rejected)
+func ReadJSON(r io.Reader) (Model, error) {
+	var m Model
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&m); err != nil {
+		return Model{}, fmt.Errorf("model: %w", err)
+	}
+	return m, nil
+}
+
+// ReadYAML decodes a YAML model (unknown fields are rejected)
+func ReadYAML(r io.Reader) (Model, error) {
+	var m Model
+	dec := yaml.NewDecoder(r)
+	dec.KnownFields(true)
+	if err := dec.Decode(&m); err != nil {
+		return Model{}, fmt.Errorf("model: %w", err)
+	}
+	return m, nil
+}
+
+// WriteJSON encodes the model using JSON
+func (m Model) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(m)
+}
+
+// WriteYAML encodes the model using YAML
+func (m Model) WriteYAML(w io.Writer) error {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(m); err != nil {
+		return err
+	}
+	if err := enc.Close(); err != nil {
+		return err
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
@@ -0,0 +1,184 @@
+package model
+
+import (
+	"fmt"
+
+	"github.com/sbiemont/fugologic/fuzzy"
+)
+
+// FromEngines creates a model describing the engines
+// Fuzzy values shall be created with set builders (see. fuzzy.NewIDValBuilders) and
+// each engine shall use predefined and unique operator and implication
+func FromEngines(engines ...fuzzy.Engine) (Model, error) {
+	m := Model{Version: Version}
+	found := make(map[*fuzzy.IDVal]struct{})
+	addValue := func(idVal *fuzzy.IDVal) error {
+		if _, exists := found[idVal]; exists {
+			return nil
+		}
+		found[idVal] = struct{}{}
+		value, err := newValue(idVal)
+		if err != nil {
+			return err
+		}
+		m.Values = append(m.Values, value)
+		return nil
+	}
+
+	for i, eng := range engines {
+		// Values, in order of appearance
+		inputs, outputs := eng.IO()
+		for _, idSet := range append(inputs, outputs...) {
+			if err := addValue(idSet.Parent()); err != nil {
+				return Model{}, fmt.Errorf("model: engines[%d]: %w", i, err)
+			}
+		}
+
+		engine, err := newEngine(eng)
+		if err != nil {
+			return Model{}, fmt.Errorf("model: engines[%d]%w", i, err)
+		}
+		m.Engines = append(m.Engines, engine)
+	}
+	return m, nil
+}
+
+// newValue describes a fuzzy value
+func newValue(idVal *fuzzy.IDVal) (Value, error) {
+	if idVal == nil {
+		return Value{}, fmt.Errorf("fuzzy value expected")
+	}
+
+	u := idVal.U()
+	value := Value{
+		ID:       string(idVal.ID()),
+		Universe: Universe{XMin: u.XMin(), XMax: u.XMax(), Dx: u.Step()},
+	}
+	for _, idSet := range idVal.Terms() {
+		name, params, err := fuzzy.SetBuilderParams(idSet.Builder())
+		if err != nil {
+			return Value{}, fmt.Errorf("value `%s`, set `%s`: %w", idVal.ID(), idSet.ID(), err)
+		}
+		value.Sets = append(value.Sets, Set{ID: string(idSet.ID()), Type: name, Params: params})
+	}
+	return value, nil
+}
+
+// newEngine describes an engine
+// Returned errors start with the path of the wrong item
+func newEngine(eng fuzzy.Engine) (Engine, error) {
+	if eng.Members() != nil {
+		return Engine{}, fmt.Errorf(": ensemble not supported")
+	}
+	engine := Engine{
+		ID:              string(eng.ID()),
+		Description:     eng.Description(),
+		Aggregation:     eng.Aggregation().Name(),
+		Defuzzification: eng.Defuzzification().Name(),
+	}
+	if engine.Aggregation == "" {
+		return Engine{}, fmt.Errorf(": custom aggregation not supported")
+	}
+	if engine.Defuzzification == "" {
+		return Engine{}, fmt.Errorf(": custom defuzzification not supported")
+	}
+
+	for i, rule := range eng.Rules() {
+		// Implication shared by all rules
+		impl := rule.Implication().Name()
+		if impl == "" {
+			return Engine{}, fmt.Errorf(".rules[%d]: custom implication not supported", i)
+		}
+		if engine.Implication != "" && engine.Implication != impl {
+			return Engine{}, fmt.Errorf(".rules[%d]: implication `%s` differs from `%s`", i, impl, engine.Implication)
+		}
+		engine.Implication = impl
+
+		premise, err := newPremise(rule.Premise(), &engine.Operator)
+		if err != nil {
+			return Engine{}, fmt.Errorf(".rules[%d].if%w", i, err)
+		}
+
+		var then []Term
+		for _, idSet := range rule.Outputs() {
+			then = append(then, newTerm(idSet))
+		}
+		result := Rule{If: premise, Then: then}
+		if weight := rule.Weight(); weight != 1 {
+			result.Weight = &weight
+		}
+		engine.Rules = append(engine.Rules, result)
+	}
+	return engine, nil
+}
+
+// newTerm describes a fuzzy set
+func newTerm(idSet fuzzy.IDSet) Term {
+	return Term{Value: string(idSet.Parent().ID()), Set: string(idSet.ID())}
+}
+
+// newPremise describes a premise
+// The operator shared by all expressions of the engine is updated
+// Returned errors start with the path of the wrong item
+func newPremise(premise fuzzy.Premise, optr *string) (Premise, error) {
+	switch p := premise.(type) {
+	case fuzzy.IDSet:
+		return Premise{Value: string(p.Parent().ID()), Set: string(p.ID())}, nil
+
+	case fuzzy.Expression:
+		var result Premise
+		premises := p.Premises()
+		if len(premises) == 1 && p.Connective() == "" {
+			// Single premise, no connector required
+			var err error
+			result, err = newPremise(premises[0], optr)
+			if err != nil {
+				return Premise{}, err
+			}
+		} else {
+			name := fuzzy.OperatorName(p.Operator())
+			if name == "" || p.Connective() == "" {
+				return Premise{}, fmt.Errorf(": custom connector not supported")
+			}
+			if *optr != "" && *optr != name {
+				return Premise{}, fmt.Errorf(": operator `%s` differs from `%s`", name, *optr)
+			}
+			*optr = name
+
+			subs := make([]Premise, len(premises))
+			for i, sub := range premises {
+				var err error
+				subs[i], err = newPremise(sub, optr)
+				if err != nil {
+					return Premise{}, fmt.Errorf(".%s[%d]%w", p.Connective(), i, err)
+				}
+			}
+			switch p.Connective() {
+			case fuzzy.ConnectiveAnd:
+				result.And = subs
+			case fuzzy.ConnectiveOr:
+				result.Or = subs
+			default: // fuzzy.ConnectiveXOr
+				result.XOr = subs
+			}
+		}
+
+		if p.Hedge() != nil {
+			name := p.Hedge().Name()
+			switch {
+			case name == "":
+				return Premise{}, fmt.Errorf(": custom hedge not supported")
+			case result.Hedge != "":
+				return Premise{}, fmt.Errorf(": hedge of a hedged premise not supported")
+			}
+			result.Hedge = name
+		}
+		if p.Complement() {
+			return Premise{Not: &result}, nil
+		}
+		return result, nil
+
+	default:
+		return Premise{}, fmt.Errorf(": premise %T not supported", premise)
+	}
+}
@@ -0,0 +1,148 @@
+package model
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/sbiemont/fugologic/crisp"
+	"github.com/sbiemont/fugologic/fuzzy"
+	"github.com/sbiemont/fugologic/id"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestFromEngines(t *testing.T) {
+	Convey("from engines", t, func() {
+		Convey("when round trip", func() {
+			m, err := ReadJSON(strings.NewReader(testJSON))
+			So(err, ShouldBeNil)
+			sys, values, err := m.Build()
+			So(err, ShouldBeNil)
+
+			saved, err := FromEngines(sys...)
+			So(err, ShouldBeNil)
+			So(saved.Version, ShouldEqual, Version)
+			So(saved.Values, ShouldHaveLength, 4)
+			So(saved.Engines, ShouldHaveLength, 2)
+			So(saved.Engines[0].Operator, ShouldEqual, "zadeh")
+			So(saved.Engines[0].Implication, ShouldEqual, "min")
+			So(saved.Engines[0].Rules, ShouldHaveLength, 7) // 1 rule + 6 cells
+			So(saved.Engines[0].Rules[0], ShouldResemble, Rule{
+				If: Premise{Or: []Premise{
+					{Value: "a", Set: "a1"},
+					{And: []Premise{{Value: "a", Set: "a2"}, {Value: "b", Set: "b1"}}},
+				}},
+				Then: []Term{{Value: "c", Set: "c1"}},
+			})
+			So(saved.Engines[1].Rules[1], ShouldResemble, Rule{
+				If:   Premise{Not: &Premise{Value: "c", Set: "c1"}},
+				Then: []Term{{Value: "d", Set: "d2"}},
+			})
+
+			// Encode, decode and build again
+			var bufJSON, bufYAML bytes.Buffer
+			So(saved.WriteJSON(&bufJSON), ShouldBeNil)
+			So(saved.WriteYAML(&bufYAML), ShouldBeNil)
+			mJSON, err := ReadJSON(&bufJSON)
+			So(err, ShouldBeNil)
+			So(mJSON, ShouldResemble, saved)
+			mYAML, err := ReadYAML(&bufYAML)
+			So(err, ShouldBeNil)
+			So(mYAML, ShouldResemble, saved)
+
+			sys2, values2, err := mJSON.Build()
+			So(err, ShouldBeNil)
+			for _, in := range [][2]float64{{0, 0}, {0.3, 0.6}, {1, 0.2}} {
+				out1, err := sys.Evaluate(fuzzy.DataInput{values["a"]: in[0], values["b"]: in[1]})
+				So(err, ShouldBeNil)
+				out2, err := sys2.Evaluate(fuzzy.DataInput{values2["a"]: in[0], values2["b"]: in[1]})
+				So(err, ShouldBeNil)
+				So(out2[values2["c"]], ShouldAlmostEqual, out1[values["c"]])
+				So(out2[values2["d"]], ShouldAlmostEqual, out1[values["d"]])
+			}
+		})
+
+		u, err := crisp.NewSet(0, 1, 0.1)
+		So(err, ShouldBeNil)
+		fvA, err := fuzzy.NewIDValBuilders("a", u, map[id.ID]fuzzy.SetBuilder{
+			"a1": fuzzy.StepDown{A: 0, B: 1},
+			"a2": fuzzy.StepUp{A: 0, B: 1},
+		})
+		So(err, ShouldBeNil)
+		fvB, err := fuzzy.NewIDValBuilders("b", u, map[id.ID]fuzzy.SetBuilder{
+			"b1": fuzzy.StepDown{A: 0, B: 1},
+		})
+		So(err, ShouldBeNil)
+		save := func(rules ...fuzzy.Rule) error {
+			eng, err := fuzzy.NewEngine(rules, fuzzy.AggregationUnion, fuzzy.DefuzzificationCentroid)
+			So(err, ShouldBeNil)
+			_, err = FromEngines(eng)
+			return err
+		}
+
+		Convey("when hedges and weight", func() {
+			rule := fuzzy.NewRule(
+				fuzzy.NewOperatorExpression([]fuzzy.Premise{
+					fuzzy.NewExpression([]fuzzy.Premise{fvA.Get("a1")}, nil).WithHedge(fuzzy.HedgeVery).Not(),
+					fvA.Get("a2"),
+				}, fuzzy.OperatorZadeh{}, fuzzy.ConnectiveOr).WithHedge(fuzzy.HedgeSomewhat),
+				fuzzy.ImplicationMin,
+				[]fuzzy.IDSet{fvB.Get("b1")},
+			).WithWeight(0.5)
+			eng, err := fuzzy.NewEngine([]fuzzy.Rule{rule}, fuzzy.AggregationUnion, fuzzy.DefuzzificationCentroid)
+			So(err, ShouldBeNil)
+			m, err := FromEngines(eng)
+			So(err, ShouldBeNil)
+
+			weight := 0.5
+			So(m.Engines[0].Rules[0], ShouldResemble, Rule{
+				If: Premise{Hedge: "somewhat", Or: []Premise{
+					{Not: &Premise{Hedge: "very", Value: "a", Set: "a1"}},
+					{Value: "a", Set: "a2"},
+				}},
+				Then:   []Term{{Value: "b", Set: "b1"}},
+				Weight: &weight,
+			})
+
+			// Same evaluation once built
+			sys, values, err := m.Build()
+			So(err, ShouldBeNil)
+			for _, x := range []float64{0, 0.3, 0.8} {
+				expected, err := eng.Evaluate(fuzzy.DataInput{fvA: x})
+				So(err, ShouldBeNil)
+				result, err := sys.Evaluate(fuzzy.DataInput{values["a"]: x})
+				So(err, ShouldBeNil)
+				So(result[values["b"]], ShouldAlmostEqual, expected[fvB])
+			}
+		})
+
+		Convey("when custom set", func() {
+			fvC, err := fuzzy.NewIDVal("c", u, map[id.ID]fuzzy.Set{"c1": func(x float64) float64 { return x }})
+			So(err, ShouldBeNil)
+			So(save(fuzzy.NewRule(fvA.Get("a1"), fuzzy.ImplicationMin, []fuzzy.IDSet{fvC.Get("c1")})), ShouldBeError,
+				"model: engines[0]: value `c`, set `c1`: set builder expected")
+		})
+
+		Convey("when custom connector", func() {
+			custom := fuzzy.NewExpression([]fuzzy.Premise{fvA.Get("a1"), fvA.Get("a2")}, math.Max)
+			So(save(fuzzy.NewRule(custom, fuzzy.ImplicationMin, []fuzzy.IDSet{fvB.Get("b1")})), ShouldBeError,
+				"model: engines[0].rules[0].if: custom connector not supported")
+		})
+
+		Convey("when different operators", func() {
+			zadeh := fuzzy.NewOperatorExpression([]fuzzy.Premise{fvA.Get("a1"), fvA.Get("a2")}, fuzzy.OperatorZadeh{}, fuzzy.ConnectiveOr)
+			hyperbolic := fuzzy.NewOperatorExpression([]fuzzy.Premise{fvA.Get("a1"), zadeh}, fuzzy.OperatorHyperbolic{}, fuzzy.ConnectiveAnd)
+			So(save(fuzzy.NewRule(hyperbolic, fuzzy.ImplicationMin, []fuzzy.IDSet{fvB.Get("b1")})), ShouldBeError,
+				"model: engines[0].rules[0].if.and[1]: operator `zadeh` differs from `hyperbolic`")
+		})
+
+		Convey("when different implications", func() {
+			So(save(
+				fuzzy.NewRule(fvA.Get("a1"), fuzzy.ImplicationMin, []fuzzy.IDSet{fvB.Get("b1")}),
+				fuzzy.NewRule(fvA.Get("a2"), fuzzy.ImplicationProd, []fuzzy.IDSet{fvB.Get("b1")}),
+			), ShouldBeError, "model: engines[0].rules[1]: implication `prod` differs from `min`")
+		})
+	})
+}
@@ -0,0 +1,209 @@
+// Package service serves fuzzy systems over HTTP
+//
+// Routes:
+//
+//	GET  /models                  lists the names of the models
+//	GET  /models/{name}           describes the inputs and outputs of a model (universes and terms)
+//	POST /models/{name}/evaluate  evaluates a model, the body contains the inputs by id (eg.: {"HP": 75, "FP": 30})
+//
+// Add the query parameter "trace=true" to the evaluation to get the aggregated output sets.
+// Errors are returned as JSON objects (eg.: {"error": "model `x` not found"}).
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+
+	"github.com/sbiemont/fugologic/fuzzy"
+	"github.com/sbiemont/fugologic/id"
+)
+
+// maxBodySize is the maximum size of an evaluation request (in bytes)
+const maxBodySize = 1 << 20
+
+// Handler serves several fuzzy systems by name
+type Handler struct {
+	mux    *http.ServeMux
+	models map[string]served
+}
+
+// served is a system and its values
+type served struct {
+	sys     fuzzy.System
+	values  map[id.ID]*fuzzy.IDVal
+	inputs  []*fuzzy.IDVal // external inputs, sorted by id
+	outputs []*fuzzy.IDVal // outputs of all engines, sorted by id
+}
+
+// NewHandler creates a new handler serving the systems by name
+func NewHandler(systems map[string]fuzzy.System) (*Handler, error) {
+	hdl := &Handler{
+		mux:    http.NewServeMux(),
+		models: make(map[string]served, len(systems)),
+	}
+	for name, sys := range systems {
+		if name == "" {
+			return nil, errors.New("service: empty model name")
+		}
+		model, err := newServed(sys)
+		if err != nil {
+			return nil, fmt.Errorf("service: model `%s`: %w", name, err)
+		}
+		hdl.models[name] = model
+	}
+
+	hdl.mux.HandleFunc("GET /models", hdl.list)
+	hdl.mux.HandleFunc("GET /models/{name}", hdl.describe)
+	hdl.mux.HandleFunc("POST /models/{name}/evaluate", hdl.evaluate)
+	return hdl, nil
+}
+
+// ServeHTTP dispatches the request to the matching route
+func (hdl *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	hdl.mux.ServeHTTP(w, r)
+}
+
+// newServed finds the external inputs and the outputs of the system
+func newServed(sys fuzzy.System) (served, error) {
+	if len(sys) == 0 {
+		return served{}, errors.New("no engine")
+	}
+
+	result := served{
+		sys:     sys,
+		values:  make(map[id.ID]*fuzzy.IDVal),
+		inputs:  sys.Inputs(),
+		outputs: sys.Produced(),
+	}
+	for _, idVal := range append(append([]*fuzzy.IDVal{}, result.inputs...), result.outputs...) {
+		if other, exists := result.values[idVal.ID()]; exists && other != idVal {
+			return served{}, fmt.Errorf("value id `%s` defined twice", idVal.ID())
+		}
+		result.values[idVal.ID()] = idVal
+	}
+	return result, nil
+}
+
+// model returns the model matching the name of the route, or writes an error
+func (hdl *Handler) model(w http.ResponseWriter, r *http.Request) (served, bool) {
+	name := r.PathValue("name")
+	model, ok := hdl.models[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("model `%s` not found", name))
+	}
+	return model, ok
+}
+
+// list writes the names of all models
+func (hdl *Handler) list(w http.ResponseWriter, _ *http.Request) {
+	names := make([]string, 0, len(hdl.models))
+	for name := range hdl.models {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	writeJSON(w, http.StatusOK, map[string][]string{"models": names})
+}
+
+// describe writes the inputs and outputs of a model
+func (hdl *Handler) describe(w http.ResponseWriter, r *http.Request) {
+	model, ok := hdl.model(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, Description{
+		Name:    r.PathValue("name"),
+		Inputs:  describeValues(model.inputs),
+		Outputs: describeValues(model.outputs),
+	})
+}
+
+// evaluate reads the inputs, evaluates the model and writes the outputs
+func (hdl *Handler) evaluate(w http.ResponseWriter, r *http.Request) {
+	model, ok := hdl.model(w, r)
+	if !ok {
+		return
+	}
+
+	withTrace := false
+	if text := r.URL.Query().Get("trace"); text != "" {
+		var err error
+		if withTrace, err = strconv.ParseBool(text); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("trace: boolean expected (found: `%s`)", text))
+			return
+		}
+	}
+
+	// Read inputs
+	var values map[string]float64
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize))
+	if err := dec.Decode(&values); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("body: %w", err))
+		return
+	}
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		writeError(w, http.StatusBadRequest, errors.New("body: single JSON object expected"))
+		return
+	}
+
+	// Check inputs
+	input := make(fuzzy.DataInput, len(values))
+	for name, value := range values {
+		idVal, ok := model.values[id.ID(name)]
+		if !ok {
+			writeError(w, http.StatusUnprocessableEntity, fmt.Errorf("unknown value `%s`", name))
+			return
+		}
+		if !slices.Contains(model.inputs, idVal) {
+			writeError(w, http.StatusUnprocessableEntity, fmt.Errorf("value `%s` is computed by the model, it cannot be an input", name))
+			return
+		}
+		input[idVal] = value
+	}
+	for _, idVal := range model.inputs {
+		if _, ok := input[idVal]; !ok {
+			writeError(w, http.StatusUnprocessableEntity, fmt.Errorf("missing input `%s`", idVal.ID()))
+			return
+		}
+	}
+
+	// Evaluate
+	trace, err := model.sys.Trace(input)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	result := Result{Outputs: make(map[string]float64, len(trace.Output))}
+	for idVal, value := range trace.Output {
+		result.Outputs[string(idVal.ID())] = value
+	}
+	if withTrace {
+		result.Trace = make(map[string]AggregatedSet, len(trace.Aggregated))
+		for idVal, set := range trace.Aggregated {
+			xs := idVal.U().Values()
+			ys := make([]float64, len(xs))
+			for i, x := range xs {
+				ys[i] = set(x)
+			}
+			result.Trace[string(idVal.ID())] = AggregatedSet{X: xs, Y: ys}
+		}
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// writeJSON writes the status and the JSON encoded body
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// writeError writes the status and the error as a JSON object
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
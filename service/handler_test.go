@@ -0,0 +1,221 @@
+package service
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sbiemont/fugologic/crisp"
+	"github.com/sbiemont/fugologic/fuzzy"
+	"github.com/sbiemont/fugologic/id"
+	"github.com/sbiemont/fugologic/model"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const testModel = `
+version: 1
+values:
+  - id: a
+    universe: {xmin: 0, xmax: 1, dx: 0.5}
+    sets:
+      - {id: a1, type: step-down, params: [0, 1]}
+      - {id: a2, type: step-up, params: [0, 1]}
+  - id: b
+    universe: {xmin: 0, xmax: 1, dx: 0.5}
+    sets:
+      - {id: b1, type: step-down, params: [0, 1]}
+      - {id: b2, type: step-up, params: [0, 1]}
+  - id: c
+    universe: {xmin: 0, xmax: 1, dx: 0.5}
+    sets:
+      - {id: c1, type: step-down, params: [0, 1]}
+      - {id: c2, type: step-up, params: [0, 1]}
+engines:
+  - rules:
+      - if: {value: a, set: a1}
+        then: [{value: b, set: b1}]
+      - if: {value: a, set: a2}
+        then: [{value: b, set: b2}]
+  - rules:
+      - if: {value: b, set: b1}
+        then: [{value: c, set: c2}]
+      - if: {value: b, set: b2}
+        then: [{value: c, set: c1}]
+`
+
+// call sends a request to the handler and returns the status and the decoded body
+func call(hdl http.Handler, method, target, body string) (int, map[string]any) {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	hdl.ServeHTTP(rec, req)
+
+	var result map[string]any
+	if rec.Header().Get("Content-Type") == "application/json" {
+		So(json.Unmarshal(rec.Body.Bytes(), &result), ShouldBeNil)
+	}
+	return rec.Code, result
+}
+
+func TestHandler(t *testing.T) {
+	Convey("handler", t, func() {
+		m, err := model.ReadYAML(strings.NewReader(testModel))
+		So(err, ShouldBeNil)
+		sys, _, err := m.Build()
+		So(err, ShouldBeNil)
+		hdl, err := NewHandler(map[string]fuzzy.System{"chain": sys})
+		So(err, ShouldBeNil)
+
+		Convey("list", func() {
+			status, body := call(hdl, http.MethodGet, "/models", "")
+			So(status, ShouldEqual, http.StatusOK)
+			So(body, ShouldResemble, map[string]any{"models": []any{"chain"}})
+		})
+
+		Convey("describe", func() {
+			Convey("when ok", func() {
+				req := httptest.NewRequest(http.MethodGet, "/models/chain", nil)
+				rec := httptest.NewRecorder()
+				hdl.ServeHTTP(rec, req)
+				So(rec.Code, ShouldEqual, http.StatusOK)
+
+				var desc Description
+				So(json.Unmarshal(rec.Body.Bytes(), &desc), ShouldBeNil)
+				So(desc.Name, ShouldEqual, "chain")
+				So(desc.Inputs, ShouldResemble, []Value{{
+					ID:       "a",
+					Universe: Universe{XMin: 0, XMax: 1, Dx: 0.5},
+					Terms: []Term{
+						{ID: "a1", Type: fuzzy.STEPDOWN, Params: []float64{0, 1}},
+						{ID: "a2", Type: fuzzy.STEPUP, Params: []float64{0, 1}},
+					},
+				}})
+				So(desc.Outputs, ShouldHaveLength, 2)
+				So(desc.Outputs[0].ID, ShouldEqual, "b")
+				So(desc.Outputs[1].ID, ShouldEqual, "c")
+			})
+
+			Convey("when unknown model", func() {
+				status, body := call(hdl, http.MethodGet, "/models/unknown", "")
+				So(status, ShouldEqual, http.StatusNotFound)
+				So(body, ShouldResemble, map[string]any{"error": "model `unknown` not found"})
+			})
+		})
+
+		Convey("evaluate", func() {
+			Convey("when ok", func() {
+				status, body := call(hdl, http.MethodPost, "/models/chain/evaluate", `{"a": 1}`)
+				So(status, ShouldEqual, http.StatusOK)
+				So(body, ShouldResemble, map[string]any{
+					"outputs": map[string]any{"b": 0.8333333333333334, "c": 0.27777777777777773},
+				})
+			})
+
+			Convey("when trace", func() {
+				status, body := call(hdl, http.MethodPost, "/models/chain/evaluate?trace=true", `{"a": 1}`)
+				So(status, ShouldEqual, http.StatusOK)
+				So(body["trace"], ShouldResemble, map[string]any{
+					"b": map[string]any{"x": []any{0.0, 0.5, 1.0}, "y": []any{0.0, 0.5, 1.0}},
+					"c": map[string]any{"x": []any{0.0, 0.5, 1.0}, "y": []any{0.8333333333333334, 0.5, 0.16666666666666663}},
+				})
+			})
+
+			Convey("when unknown model", func() {
+				status, body := call(hdl, http.MethodPost, "/models/unknown/evaluate", `{"a": 1}`)
+				So(status, ShouldEqual, http.StatusNotFound)
+				So(body, ShouldResemble, map[string]any{"error": "model `unknown` not found"})
+			})
+
+			Convey("when invalid body", func() {
+				status, body := call(hdl, http.MethodPost, "/models/chain/evaluate", `{"a": "x"}`)
+				So(status, ShouldEqual, http.StatusBadRequest)
+				So(body["error"], ShouldStartWith, "body: json: ")
+
+				status, body = call(hdl, http.MethodPost, "/models/chain/evaluate", `{"a": 1} {"a": 2}`)
+				So(status, ShouldEqual, http.StatusBadRequest)
+				So(body, ShouldResemble, map[string]any{"error": "body: single JSON object expected"})
+			})
+
+			Convey("when invalid trace", func() {
+				status, body := call(hdl, http.MethodPost, "/models/chain/evaluate?trace=maybe", `{"a": 1}`)
+				So(status, ShouldEqual, http.StatusBadRequest)
+				So(body, ShouldResemble, map[string]any{"error": "trace: boolean expected (found: `maybe`)"})
+			})
+
+			Convey("when unknown input", func() {
+				status, body := call(hdl, http.MethodPost, "/models/chain/evaluate", `{"a": 1, "x": 2}`)
+				So(status, ShouldEqual, http.StatusUnprocessableEntity)
+				So(body, ShouldResemble, map[string]any{"error": "unknown value `x`"})
+			})
+
+			Convey("when computed value as input", func() {
+				status, body := call(hdl, http.MethodPost, "/models/chain/evaluate", `{"a": 1, "b": 0.5}`)
+				So(status, ShouldEqual, http.StatusUnprocessableEntity)
+				So(body, ShouldResemble, map[string]any{"error": "value `b` is computed by the model, it cannot be an input"})
+			})
+
+			Convey("when missing input", func() {
+				status, body := call(hdl, http.MethodPost, "/models/chain/evaluate", `{}`)
+				So(status, ShouldEqual, http.StatusUnprocessableEntity)
+				So(body, ShouldResemble, map[string]any{"error": "missing input `a`"})
+			})
+
+			Convey("when wrong method", func() {
+				status, _ := call(hdl, http.MethodGet, "/models/chain/evaluate", "")
+				So(status, ShouldEqual, http.StatusMethodNotAllowed)
+			})
+		})
+
+		Convey("with a server", func() {
+			srv := httptest.NewServer(hdl)
+			defer srv.Close()
+
+			resp, err := http.Post(srv.URL+"/models/chain/evaluate", "application/json", strings.NewReader(`{"a": 0}`))
+			So(err, ShouldBeNil)
+			defer resp.Body.Close()
+			So(resp.StatusCode, ShouldEqual, http.StatusOK)
+			body, err := io.ReadAll(resp.Body)
+			So(err, ShouldBeNil)
+			So(string(body), ShouldEqual, `{"outputs":{"b":0.16666666666666666,"c":0.7222222222222223}}`+"\n")
+		})
+	})
+}
+
+func TestNewHandler(t *testing.T) {
+	Convey("new handler", t, func() {
+		Convey("when empty name", func() {
+			_, err := NewHandler(map[string]fuzzy.System{"": nil})
+			So(err, ShouldBeError, "service: empty model name")
+		})
+
+		Convey("when no engine", func() {
+			_, err := NewHandler(map[string]fuzzy.System{"empty": nil})
+			So(err, ShouldBeError, "service: model `empty`: no engine")
+		})
+
+		Convey("when same id for different values", func() {
+			u, err := crisp.NewSet(0, 1, 0.5)
+			So(err, ShouldBeNil)
+			newVal := func() *fuzzy.IDVal {
+				idVal, err := fuzzy.NewIDValBuilders("a", u, map[id.ID]fuzzy.SetBuilder{"a1": fuzzy.StepUp{A: 0, B: 1}})
+				So(err, ShouldBeNil)
+				return idVal
+			}
+			fvA1, fvA2 := newVal(), newVal()
+			eng1, err := fuzzy.NewEngine([]fuzzy.Rule{
+				fuzzy.NewRule(fvA1.Get("a1"), fuzzy.ImplicationMin, []fuzzy.IDSet{fvA1.Get("a1")}),
+			}, fuzzy.AggregationUnion, fuzzy.DefuzzificationCentroid)
+			So(err, ShouldBeNil)
+			eng2, err := fuzzy.NewEngine([]fuzzy.Rule{
+				fuzzy.NewRule(fvA2.Get("a1"), fuzzy.ImplicationMin, []fuzzy.IDSet{fvA2.Get("a1")}),
+			}, fuzzy.AggregationUnion, fuzzy.DefuzzificationCentroid)
+			So(err, ShouldBeNil)
+
+			_, err = NewHandler(map[string]fuzzy.System{"dup": {eng1, eng2}})
+			So(err, ShouldBeError, "service: model `dup`: value id `a` defined twice")
+		})
+	})
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"github.com/sbiemont/fugologic/fuzzy"
+)
+
+// Description describes a model: its external inputs and its outputs
+type Description struct {
+	Name    string  `json:"name"`
+	Inputs  []Value `json:"inputs"`
+	Outputs []Value `json:"outputs"`
+}
+
+// Value describes a fuzzy value
+type Value struct {
+	ID       string   `json:"id"`
+	Universe Universe `json:"universe"`
+	Terms    []Term   `json:"terms"`
+}
+
+// Universe describes the crisp set of a fuzzy value
+type Universe struct {
+	XMin float64 `json:"xmin"`
+	XMax float64 `json:"xmax"`
+	Dx   float64 `json:"dx"`
+}
+
+// Term describes a fuzzy set, its type and parameters are only defined for predefined set builders
+type Term struct {
+	ID     string    `json:"id"`
+	Type   string    `json:"type,omitempty"`
+	Params []float64 `json:"params,omitempty"`
+}
+
+// Result is the result of an evaluation
+type Result struct {
+	Outputs map[string]float64       `json:"outputs"`
+	Trace   map[string]AggregatedSet `json:"trace,omitempty"`
+}
+
+// AggregatedSet is the aggregated output set sampled over the universe of the output
+type AggregatedSet struct {
+	X []float64 `json:"x"`
+	Y []float64 `json:"y"`
+}
+
+// describeValues describes each fuzzy value
+func describeValues(idVals []*fuzzy.IDVal) []Value {
+	result := make([]Value, len(idVals))
+	for i, idVal := range idVals {
+		u := idVal.U()
+		value := Value{
+			ID:       string(idVal.ID()),
+			Universe: Universe{XMin: u.XMin(), XMax: u.XMax(), Dx: u.Step()},
+		}
+		for _, idSet := range idVal.Terms() {
+			term := Term{ID: string(idSet.ID())}
+			if name, params, err := fuzzy.SetBuilderParams(idSet.Builder()); err == nil {
+				term.Type = name
+				term.Params = params
+			}
+			value.Terms = append(value.Terms, term)
+		}
+		result[i] = value
+	}
+	return result
+}
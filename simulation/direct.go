@@ -0,0 +1,57 @@
+package simulation
+
+import (
+	"github.com/sbiemont/fugologic/fuzzy"
+)
+
+// Direct is a direct fuzzy controller: the command is the output of an engine (or a system)
+// The inputs are the error (set-point - measurement) and its derivative (optional)
+type Direct struct {
+	ev     fuzzy.Evaluator
+	fvErr  *fuzzy.IDVal
+	fvDErr *fuzzy.IDVal
+	fvOut  *fuzzy.IDVal
+
+	initialized bool
+	prevErr     float64
+	output      float64
+	err         error
+}
+
+// NewDirect creates a direct fuzzy controller, fvDErr can be nil if the derivative of the error is not used
+func NewDirect(ev fuzzy.Evaluator, fvErr, fvDErr, fvOut *fuzzy.IDVal) *Direct {
+	return &Direct{
+		ev:     ev,
+		fvErr:  fvErr,
+		fvDErr: fvDErr,
+		fvOut:  fvOut,
+	}
+}
+
+// Update evaluates the command, the previous one is held on error (see Err)
+func (ctrl *Direct) Update(setpoint, measurement, dt float64) float64 {
+	e := setpoint - measurement
+	input := fuzzy.DataInput{ctrl.fvErr: e}
+	if ctrl.fvDErr != nil {
+		var de float64
+		if ctrl.initialized && dt > 0 {
+			de = (e - ctrl.prevErr) / dt
+		}
+		input[ctrl.fvDErr] = de
+	}
+
+	output, err := ctrl.ev.Evaluate(input)
+	ctrl.err = err
+	if err != nil {
+		return ctrl.output
+	}
+	ctrl.initialized = true
+	ctrl.prevErr = e
+	ctrl.output = output[ctrl.fvOut]
+	return ctrl.output
+}
+
+// Err returns the error of the last update (nil if none)
+func (ctrl *Direct) Err() error {
+	return ctrl.err
+}
@@ -0,0 +1,76 @@
+package simulation
+
+import (
+	"math"
+)
+
+// Metrics are the standard performance indicators of a simulation
+// Times are infinite when the corresponding event never occurs
+type Metrics struct {
+	RiseTime     float64 // time to go from 10% to 90% of the step
+	Overshoot    float64 // maximal overshoot, in percent of the step
+	SettlingTime float64 // time after which the measurement stays within the tolerance band around the final set-point
+	IAE          float64 // integral of the absolute error
+	ISE          float64 // integral of the squared error
+}
+
+// Metrics computes the performance indicators of the simulation
+// The step goes from the initial measurement to the final set-point
+// The tolerance is the half-width of the settling band, relative to the step (eg.: 0.02 for 2%)
+func (res Result) Metrics(tolerance float64) Metrics {
+	n := len(res.Time)
+	if n == 0 {
+		return Metrics{}
+	}
+
+	// Integral criteria (rectangles)
+	var m Metrics
+	for i := 0; i < n-1; i++ {
+		dt := res.Time[i+1] - res.Time[i]
+		m.IAE += math.Abs(res.Error[i]) * dt
+		m.ISE += res.Error[i] * res.Error[i] * dt
+	}
+
+	// Normalized step response
+	y0 := res.Measurement[0]
+	step := res.Setpoint[n-1] - y0
+	if step == 0 {
+		return m
+	}
+	z := make([]float64, n)
+	for i, y := range res.Measurement {
+		z[i] = (y - y0) / step
+	}
+
+	// Rise time
+	first := func(threshold float64) float64 {
+		for i, zi := range z {
+			if zi >= threshold {
+				return res.Time[i]
+			}
+		}
+		return math.Inf(1)
+	}
+	m.RiseTime = first(0.9) - first(0.1)
+	if math.IsNaN(m.RiseTime) {
+		m.RiseTime = math.Inf(1)
+	}
+
+	// Overshoot
+	for _, zi := range z {
+		m.Overshoot = max(m.Overshoot, (zi-1)*100)
+	}
+
+	// Settling time: just after the last sample out of the band
+	for i := n - 1; i >= 0; i-- {
+		if math.Abs(z[i]-1) > tolerance {
+			if i == n-1 {
+				m.SettlingTime = math.Inf(1)
+			} else {
+				m.SettlingTime = res.Time[i+1]
+			}
+			break
+		}
+	}
+	return m
+}
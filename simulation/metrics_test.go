@@ -0,0 +1,70 @@
+package simulation
+
+import (
+	"math"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMetrics(t *testing.T) {
+	Convey("metrics", t, func() {
+		Convey("when step response", func() {
+			res := Result{
+				Time:        []float64{0, 1, 2, 3, 4, 5, 6},
+				Setpoint:    []float64{2, 2, 2, 2, 2, 2, 2},
+				Measurement: []float64{0, 0.4, 1.6, 2.4, 2.1, 2.01, 2},
+			}
+			for i := range res.Time {
+				res.Error = append(res.Error, res.Setpoint[i]-res.Measurement[i])
+			}
+
+			m := res.Metrics(0.02)
+			So(m.RiseTime, ShouldEqual, 2)         // 10% (0.2) at 1s, 90% (1.8) at 3s
+			So(m.Overshoot, ShouldAlmostEqual, 20) // 2.4 instead of 2
+			So(m.SettlingTime, ShouldEqual, 5)     // within [1.96, 2.04] from 5s
+			So(m.IAE, ShouldAlmostEqual, 2+1.6+0.4+0.4+0.1+0.01)
+			So(m.ISE, ShouldAlmostEqual, 4+2.56+0.16+0.16+0.01+0.0001)
+
+			// Larger band
+			So(res.Metrics(0.1).SettlingTime, ShouldEqual, 4)
+		})
+
+		Convey("when never reached", func() {
+			res := Result{
+				Time:        []float64{0, 1, 2},
+				Setpoint:    []float64{1, 1, 1},
+				Measurement: []float64{0, 0.2, 0.5},
+				Error:       []float64{1, 0.8, 0.5},
+			}
+			m := res.Metrics(0.02)
+			So(m.RiseTime, ShouldEqual, math.Inf(1))
+			So(m.SettlingTime, ShouldEqual, math.Inf(1))
+			So(m.Overshoot, ShouldEqual, 0)
+		})
+
+		Convey("when negative step", func() {
+			res := Result{
+				Time:        []float64{0, 1, 2, 3},
+				Setpoint:    []float64{0, 0, 0, 0},
+				Measurement: []float64{1, 0.5, -0.1, 0},
+				Error:       []float64{-1, -0.5, 0.1, 0},
+			}
+			m := res.Metrics(0.02)
+			So(m.RiseTime, ShouldEqual, 1)
+			So(m.Overshoot, ShouldAlmostEqual, 10)
+			So(m.SettlingTime, ShouldEqual, 3)
+		})
+
+		Convey("when no step", func() {
+			res := Result{
+				Time:        []float64{0, 1},
+				Setpoint:    []float64{1, 1},
+				Measurement: []float64{1, 1},
+				Error:       []float64{0, 0},
+			}
+			So(res.Metrics(0.02), ShouldResemble, Metrics{})
+			So(Result{}.Metrics(0.02), ShouldResemble, Metrics{})
+		})
+	})
+}
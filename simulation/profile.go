@@ -0,0 +1,54 @@
+package simulation
+
+import (
+	"sort"
+)
+
+// Profile gives the set-point at a given time
+type Profile func(t float64) float64
+
+// Change is a new set-point value from a given time
+type Change struct {
+	At    float64 // time of the change
+	Value float64 // new set-point value
+}
+
+// Constant returns the same set-point at any time
+func Constant(value float64) Profile {
+	return func(float64) float64 {
+		return value
+	}
+}
+
+// Steps returns a piecewise constant set-point
+// Before the first change, the set-point is the value of the first change (0 without any change)
+func Steps(changes ...Change) Profile {
+	sorted := append([]Change{}, changes...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].At < sorted[j].At
+	})
+	return func(t float64) float64 {
+		if len(sorted) == 0 {
+			return 0
+		}
+		// Last change reached
+		i := sort.Search(len(sorted), func(i int) bool {
+			return sorted[i].At > t
+		})
+		return sorted[max(i-1, 0)].Value
+	}
+}
+
+// Ramp returns a set-point linearly moving from a value to another one between two times
+func Ramp(t0, t1, from, to float64) Profile {
+	return func(t float64) float64 {
+		switch {
+		case t <= t0:
+			return from
+		case t >= t1:
+			return to
+		default:
+			return from + (to-from)*(t-t0)/(t1-t0)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+// Package simulation runs closed-loop simulations of controllers against plant models
+package simulation
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/sbiemont/fugologic/sweep"
+)
+
+// Plant is a process to control
+type Plant interface {
+	// Step applies the command u during dt and returns the new measurement
+	Step(u, dt float64) float64
+}
+
+// Controller computes a command from the set-point and the measurement
+// It is implemented by controller.Controller
+type Controller interface {
+	Update(setpoint, measurement, dt float64) float64
+	Err() error // error of the last update (nil if none)
+}
+
+// Simulation describes a closed-loop simulation
+type Simulation struct {
+	Plant      Plant
+	Controller Controller
+	Setpoint   Profile // set-point over time
+	Duration   float64 // duration of the simulation
+	Dt         float64 // time step
+	Initial    float64 // initial measurement of the plant
+}
+
+// Result holds the time series of a simulation, one value per time step
+type Result struct {
+	Time        []float64
+	Setpoint    []float64
+	Measurement []float64
+	Command     []float64
+	Error       []float64 // set-point - measurement
+}
+
+// Run simulates the closed-loop from 0 to the duration of the simulation
+// At each time step, the command computed from the current measurement is applied to the plant
+func (sim Simulation) Run() (Result, error) {
+	if sim.Plant == nil || sim.Controller == nil || sim.Setpoint == nil {
+		return Result{}, errors.New("simulation: plant, controller and set-point expected")
+	}
+	if sim.Dt <= 0 {
+		return Result{}, fmt.Errorf("simulation: positive time step expected (found: %g)", sim.Dt)
+	}
+	if sim.Duration < 0 {
+		return Result{}, fmt.Errorf("simulation: negative duration %g", sim.Duration)
+	}
+
+	n := int(math.Round(sim.Duration / sim.Dt))
+	var res Result
+	y := sim.Initial
+	for i := 0; i <= n; i++ {
+		t := float64(i) * sim.Dt
+		r := sim.Setpoint(t)
+		u := sim.Controller.Update(r, y, sim.Dt)
+		if err := sim.Controller.Err(); err != nil {
+			return Result{}, fmt.Errorf("simulation: t=%g: %w", t, err)
+		}
+
+		res.Time = append(res.Time, t)
+		res.Setpoint = append(res.Setpoint, r)
+		res.Measurement = append(res.Measurement, y)
+		res.Command = append(res.Command, u)
+		res.Error = append(res.Error, r-y)
+
+		if i < n {
+			y = sim.Plant.Step(u, sim.Dt)
+		}
+	}
+	return res, nil
+}
+
+// Table converts the result into a table with the columns: time, setpoint, measurement, command, error
+func (res Result) Table() sweep.Table {
+	tbl := sweep.Table{
+		Columns: []string{"time", "setpoint", "measurement", "command", "error"},
+		Inputs:  1,
+		Rows:    make([][]float64, len(res.Time)),
+	}
+	for i := range res.Time {
+		tbl.Rows[i] = []float64{res.Time[i], res.Setpoint[i], res.Measurement[i], res.Command[i], res.Error[i]}
+	}
+	return tbl
+}
+
+// WriteCSV writes the time series as CSV, the first row contains the columns names (see Table)
+func (res Result) WriteCSV(w io.Writer) error {
+	return res.Table().WriteCSV(w)
+}
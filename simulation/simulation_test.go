@@ -0,0 +1,170 @@
+package simulation
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/sbiemont/fugologic/builder"
+	"github.com/sbiemont/fugologic/controller"
+	"github.com/sbiemont/fugologic/fuzzy"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// firstOrder is a plant with a time constant of 1s
+type firstOrder struct {
+	y float64
+}
+
+func (p *firstOrder) Step(u, dt float64) float64 {
+	p.y += (u - p.y) * dt
+	return p.y
+}
+
+// constant always returns the same command
+type constant struct {
+	u   float64
+	err error
+}
+
+func (c constant) Update(float64, float64, float64) float64 { return c.u }
+func (c constant) Err() error                               { return c.err }
+
+func TestProfile(t *testing.T) {
+	Convey("profile", t, func() {
+		Convey("constant", func() {
+			So(Constant(2)(0), ShouldEqual, 2)
+			So(Constant(2)(10), ShouldEqual, 2)
+		})
+
+		Convey("steps", func() {
+			p := Steps(Change{At: 5, Value: 2}, Change{At: 1, Value: 1}, Change{At: 10, Value: 3})
+			So(p(0), ShouldEqual, 1)
+			So(p(1), ShouldEqual, 1)
+			So(p(4.9), ShouldEqual, 1)
+			So(p(5), ShouldEqual, 2)
+			So(p(100), ShouldEqual, 3)
+			So(Steps()(1), ShouldEqual, 0)
+		})
+
+		Convey("ramp", func() {
+			p := Ramp(1, 3, 10, 20)
+			So(p(0), ShouldEqual, 10)
+			So(p(2), ShouldEqual, 15)
+			So(p(4), ShouldEqual, 20)
+		})
+	})
+}
+
+func TestRun(t *testing.T) {
+	Convey("run", t, func() {
+		Convey("when open loop", func() {
+			sim := Simulation{
+				Plant:      &firstOrder{},
+				Controller: constant{u: 1},
+				Setpoint:   Constant(1),
+				Duration:   0.3,
+				Dt:         0.1,
+			}
+			res, err := sim.Run()
+			So(err, ShouldBeNil)
+			So(res.Time, ShouldHaveLength, 4)
+			So(res.Time[3], ShouldAlmostEqual, 0.3)
+			So(res.Setpoint, ShouldResemble, []float64{1, 1, 1, 1})
+			So(res.Command, ShouldResemble, []float64{1, 1, 1, 1})
+			So(res.Measurement[0], ShouldEqual, 0)
+			So(res.Measurement[1], ShouldAlmostEqual, 0.1)
+			So(res.Measurement[2], ShouldAlmostEqual, 0.19)
+			So(res.Error[2], ShouldAlmostEqual, 0.81)
+
+			var sb strings.Builder
+			So(res.WriteCSV(&sb), ShouldBeNil)
+			lines := strings.Split(sb.String(), "\n")
+			So(lines[0], ShouldEqual, "time,setpoint,measurement,command,error")
+			So(lines[1], ShouldEqual, "0,1,0,1,1")
+			So(lines, ShouldHaveLength, 6) // header + 4 rows + last empty line
+		})
+
+		Convey("when fuzzy PI controller", func() {
+			fvErr, _ := controller.NewValue("e", 101)
+			fvDErr, _ := controller.NewValue("de", 101)
+			fvU, _ := controller.NewValue("u", 101)
+			mx := builder.Mamdani().FuzzyAssoMatrix()
+			So(controller.StandardRuleBase(&mx, fvErr, fvDErr, fvU), ShouldBeNil)
+			eng, err := mx.Engine()
+			So(err, ShouldBeNil)
+			ctrl, err := controller.NewPI(eng, fvErr, fvDErr, fvU, controller.Gains{Ke: 1, Kd: 0.1, Ki: 2})
+			So(err, ShouldBeNil)
+
+			res, err := Simulation{
+				Plant:      &firstOrder{},
+				Controller: ctrl,
+				Setpoint:   Steps(Change{At: 0, Value: 0.5}),
+				Duration:   30,
+				Dt:         0.1,
+			}.Run()
+			So(err, ShouldBeNil)
+			So(res.Measurement[len(res.Measurement)-1], ShouldAlmostEqual, 0.5, 0.01)
+
+			m := res.Metrics(0.02)
+			So(m.RiseTime, ShouldBeGreaterThan, 0)
+			So(m.SettlingTime, ShouldBeGreaterThan, m.RiseTime)
+			So(m.SettlingTime, ShouldBeLessThan, 30)
+			So(m.IAE, ShouldBeGreaterThan, 0)
+		})
+
+		Convey("when direct fuzzy controller", func() {
+			fvErr, _ := controller.NewValue("e", 101)
+			fvU, _ := controller.NewValue("u", 101)
+			fl := builder.Mamdani().FuzzyLogic()
+			for _, term := range controller.Terms() {
+				fl.If(fvErr.Get(term)).Then(fvU.Get(term))
+			}
+			eng, err := fl.Engine()
+			So(err, ShouldBeNil)
+			sys, err := fuzzy.NewSystem([]fuzzy.Engine{eng})
+			So(err, ShouldBeNil)
+
+			res, err := Simulation{
+				Plant:      &firstOrder{},
+				Controller: NewDirect(sys, fvErr, nil, fvU),
+				Setpoint:   Constant(0.5),
+				Duration:   10,
+				Dt:         0.1,
+			}.Run()
+			So(err, ShouldBeNil)
+			So(res.Command[0], ShouldBeGreaterThan, 0)
+			So(res.Measurement[len(res.Measurement)-1], ShouldBeBetween, 0.1, 0.5)
+		})
+
+		Convey("when errors", func() {
+			_, err := Simulation{}.Run()
+			So(err, ShouldBeError, "simulation: plant, controller and set-point expected")
+
+			sim := Simulation{Plant: &firstOrder{}, Controller: constant{}, Setpoint: Constant(1)}
+			_, err = sim.Run()
+			So(err, ShouldBeError, "simulation: positive time step expected (found: 0)")
+
+			sim.Dt, sim.Duration = 0.1, -1
+			_, err = sim.Run()
+			So(err, ShouldBeError, "simulation: negative duration -1")
+
+			sim.Duration, sim.Controller = 1, constant{err: errors.New("ko")}
+			_, err = sim.Run()
+			So(err, ShouldBeError, "simulation: t=0: ko")
+
+			// Missing input of the engine
+			fvErr, _ := controller.NewValue("e", 11)
+			fvDErr, _ := controller.NewValue("de", 11)
+			fvU, _ := controller.NewValue("u", 11)
+			mx := builder.Mamdani().FuzzyAssoMatrix()
+			So(controller.StandardRuleBase(&mx, fvErr, fvDErr, fvU), ShouldBeNil)
+			eng, _ := mx.Engine()
+			sim.Controller = NewDirect(eng, fvErr, nil, fvU)
+			_, err = sim.Run()
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldStartWith, "simulation: t=0: input: cannot find data for id val `de`")
+		})
+	})
+}
@@ -0,0 +1,276 @@
+// Package svg renders fuzzy values as SVG images
+//
+// A fuzzy value is drawn with all its fuzzy sets over its crisp universe;
+// an evaluated output is drawn with its aggregated set and its defuzzified value.
+package svg
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"strconv"
+
+	"github.com/sbiemont/fugologic/crisp"
+	"github.com/sbiemont/fugologic/fuzzy"
+)
+
+// Margins around the drawing area
+const (
+	marginLeft   = 50
+	marginRight  = 20
+	marginTop    = 40
+	marginBottom = 40
+)
+
+// colors used for the fuzzy sets, in order
+var colors = []string{
+	"#1f77b4", "#ff7f0e", "#2ca02c", "#d62728", "#9467bd",
+	"#8c564b", "#e377c2", "#7f7f7f", "#bcbd22", "#17becf",
+}
+
+// Plot defines the size of the image (in pixels)
+type Plot struct {
+	Width  float64
+	Height float64
+}
+
+// DefaultPlot is the plot used by the package functions
+var DefaultPlot = Plot{Width: 640, Height: 320}
+
+// WriteIDVal draws all fuzzy sets of a fuzzy value, using the default plot
+func WriteIDVal(w io.Writer, idVal *fuzzy.IDVal) error {
+	return DefaultPlot.WriteIDVal(w, idVal)
+}
+
+// WriteOutput draws the aggregated set of an evaluated output and its defuzzified value, using the default plot
+func WriteOutput(w io.Writer, idVal *fuzzy.IDVal, trace fuzzy.Trace) error {
+	return DefaultPlot.WriteOutput(w, idVal, trace)
+}
+
+// WriteIDVal draws all fuzzy sets of a fuzzy value, with their labels
+func (p Plot) WriteIDVal(w io.Writer, idVal *fuzzy.IDVal) error {
+	if err := p.check(idVal); err != nil {
+		return err
+	}
+
+	cvs := newCanvas(w, p, idVal.U())
+	cvs.begin(string(idVal.ID()))
+	for i, idSet := range idVal.Terms() {
+		color := colors[i%len(colors)]
+		cvs.curve(idSet.Set(), color, false)
+		cvs.label(idSet.Set(), string(idSet.ID()), color)
+	}
+	return cvs.end()
+}
+
+// WriteOutput draws the aggregated set of an evaluated output over its fuzzy sets
+// The defuzzified value is marked by a vertical line
+func (p Plot) WriteOutput(w io.Writer, idVal *fuzzy.IDVal, trace fuzzy.Trace) error {
+	if err := p.check(idVal); err != nil {
+		return err
+	}
+	aggregated, ok := trace.Aggregated[idVal]
+	if !ok {
+		return fmt.Errorf("svg: no aggregated set for `%s`", idVal.ID())
+	}
+	value, ok := trace.Output[idVal]
+	if !ok {
+		return fmt.Errorf("svg: no output value for `%s`", idVal.ID())
+	}
+
+	cvs := newCanvas(w, p, idVal.U())
+	cvs.begin(fmt.Sprintf("%s = %s", idVal.ID(), formatValue(value, 0.001)))
+	for _, idSet := range idVal.Terms() {
+		cvs.curve(idSet.Set(), "#999999", true)
+		cvs.label(idSet.Set(), string(idSet.ID()), "#999999")
+	}
+	cvs.area(aggregated, colors[0])
+	cvs.marker(value, colors[3])
+	return cvs.end()
+}
+
+// check the plot and the fuzzy value
+func (p Plot) check(idVal *fuzzy.IDVal) error {
+	if idVal == nil {
+		return fmt.Errorf("svg: nil value")
+	}
+	if p.Width <= marginLeft+marginRight || p.Height <= marginTop+marginBottom {
+		return fmt.Errorf("svg: plot size %gx%g too small", p.Width, p.Height)
+	}
+	return nil
+}
+
+// canvas writes the SVG elements of one image
+type canvas struct {
+	w    *bufio.Writer
+	plot Plot
+	u    crisp.Set
+}
+
+// newCanvas creates a new canvas for the universe
+func newCanvas(w io.Writer, p Plot, u crisp.Set) canvas {
+	return canvas{
+		w:    bufio.NewWriter(w),
+		plot: p,
+		u:    u,
+	}
+}
+
+// x converts a crisp value into a horizontal position
+func (cvs canvas) x(value float64) float64 {
+	width := cvs.plot.Width - marginLeft - marginRight
+	if cvs.u.XMax() == cvs.u.XMin() {
+		return marginLeft + width/2
+	}
+	return marginLeft + (value-cvs.u.XMin())/(cvs.u.XMax()-cvs.u.XMin())*width
+}
+
+// y converts a membership degree into a vertical position
+func (cvs canvas) y(degree float64) float64 {
+	height := cvs.plot.Height - marginTop - marginBottom
+	return cvs.plot.Height - marginBottom - degree*height
+}
+
+// begin writes the header, the title and the axes
+func (cvs canvas) begin(title string) {
+	fmt.Fprintf(cvs.w, `<svg xmlns="http://www.w3.org/2000/svg" width="%s" height="%s" viewBox="0 0 %s %s" font-family="sans-serif" font-size="12">`+"\n",
+		coord(cvs.plot.Width), coord(cvs.plot.Height), coord(cvs.plot.Width), coord(cvs.plot.Height))
+	fmt.Fprintf(cvs.w, `<rect width="100%%" height="100%%" fill="white"/>`+"\n")
+	fmt.Fprintf(cvs.w, `<text x="%s" y="20" text-anchor="middle" font-size="14">%s</text>`+"\n",
+		coord(cvs.plot.Width/2), html.EscapeString(title))
+
+	// Axes
+	x0, x1 := cvs.x(cvs.u.XMin()), cvs.x(cvs.u.XMax())
+	y0, y1 := cvs.y(0), cvs.y(1)
+	fmt.Fprintf(cvs.w, `<g stroke="black" fill="none">`+"\n")
+	fmt.Fprintf(cvs.w, `<line x1="%s" y1="%s" x2="%s" y2="%s"/>`+"\n", coord(x0), coord(y0), coord(x1), coord(y0))
+	fmt.Fprintf(cvs.w, `<line x1="%s" y1="%s" x2="%s" y2="%s"/>`+"\n", coord(x0), coord(y0), coord(x0), coord(y1))
+	fmt.Fprintf(cvs.w, "</g>\n")
+
+	// Ticks
+	fmt.Fprintf(cvs.w, `<g stroke="black">`+"\n")
+	xTicks, xStep := ticks(cvs.u.XMin(), cvs.u.XMax(), 8)
+	for _, tick := range xTicks {
+		x := cvs.x(tick)
+		fmt.Fprintf(cvs.w, `<line x1="%s" y1="%s" x2="%s" y2="%s"/>`+"\n", coord(x), coord(y0), coord(x), coord(y0+5))
+	}
+	yTicks, yStep := ticks(0, 1, 4)
+	for _, tick := range yTicks {
+		y := cvs.y(tick)
+		fmt.Fprintf(cvs.w, `<line x1="%s" y1="%s" x2="%s" y2="%s"/>`+"\n", coord(x0-5), coord(y), coord(x0), coord(y))
+	}
+	fmt.Fprintf(cvs.w, "</g>\n")
+
+	// Ticks labels
+	fmt.Fprintf(cvs.w, `<g fill="black">`+"\n")
+	for _, tick := range xTicks {
+		fmt.Fprintf(cvs.w, `<text x="%s" y="%s" text-anchor="middle">%s</text>`+"\n",
+			coord(cvs.x(tick)), coord(y0+18), formatValue(tick, xStep))
+	}
+	for _, tick := range yTicks {
+		fmt.Fprintf(cvs.w, `<text x="%s" y="%s" text-anchor="end">%s</text>`+"\n",
+			coord(x0-8), coord(cvs.y(tick)+4), formatValue(tick, yStep))
+	}
+	fmt.Fprintf(cvs.w, "</g>\n")
+}
+
+// end closes the image and flushes the writer
+func (cvs canvas) end() error {
+	fmt.Fprintf(cvs.w, "</svg>\n")
+	return cvs.w.Flush()
+}
+
+// points of the membership function over the universe
+func (cvs canvas) points(set fuzzy.Set) string {
+	var result []byte
+	for i, value := range cvs.u.Values() {
+		if i > 0 {
+			result = append(result, ' ')
+		}
+		result = append(result, coord(cvs.x(value))...)
+		result = append(result, ',')
+		result = append(result, coord(cvs.y(set(value)))...)
+	}
+	return string(result)
+}
+
+// curve draws the membership function
+func (cvs canvas) curve(set fuzzy.Set, color string, dashed bool) {
+	dash := ""
+	if dashed {
+		dash = ` stroke-dasharray="4 3"`
+	}
+	fmt.Fprintf(cvs.w, `<polyline fill="none" stroke="%s" stroke-width="2"%s points="%s"/>`+"\n",
+		color, dash, cvs.points(set))
+}
+
+// area draws the membership function as a filled area
+func (cvs canvas) area(set fuzzy.Set, color string) {
+	x0, x1, y0 := cvs.x(cvs.u.XMin()), cvs.x(cvs.u.XMax()), cvs.y(0)
+	fmt.Fprintf(cvs.w, `<polygon fill="%s" fill-opacity="0.4" stroke="%s" stroke-width="2" points="%s,%s %s %s,%s"/>`+"\n",
+		color, color, coord(x0), coord(y0), cvs.points(set), coord(x1), coord(y0))
+}
+
+// label writes the name of the set above its first maximum
+func (cvs canvas) label(set fuzzy.Set, name string, color string) {
+	xMax, yMax := cvs.u.XMin(), math.Inf(-1)
+	for _, value := range cvs.u.Values() {
+		if y := set(value); y > yMax {
+			xMax, yMax = value, y
+		}
+	}
+	fmt.Fprintf(cvs.w, `<text x="%s" y="%s" text-anchor="middle" fill="%s">%s</text>`+"\n",
+		coord(cvs.x(xMax)), coord(cvs.y(yMax)-6), color, html.EscapeString(name))
+}
+
+// marker draws a vertical line at the value
+func (cvs canvas) marker(value float64, color string) {
+	x := cvs.x(value)
+	fmt.Fprintf(cvs.w, `<line x1="%s" y1="%s" x2="%s" y2="%s" stroke="%s" stroke-width="2"/>`+"\n",
+		coord(x), coord(cvs.y(0)), coord(x), coord(cvs.y(1)), color)
+}
+
+// ticks returns rounded values (1, 2 or 5 times a power of 10) covering [xmin ; xmax], and the step
+func ticks(xmin, xmax float64, n int) ([]float64, float64) {
+	if xmax <= xmin || n <= 0 {
+		return []float64{xmin}, 1
+	}
+
+	// Choose the step
+	raw := (xmax - xmin) / float64(n)
+	magnitude := math.Pow(10, math.Floor(math.Log10(raw)))
+	step := magnitude
+	for _, k := range []float64{2, 5, 10} {
+		if step >= raw {
+			break
+		}
+		step = k * magnitude
+	}
+
+	// Generate values
+	var result []float64
+	for i := math.Ceil(xmin / step); i*step <= xmax+step*1e-9; i++ {
+		result = append(result, i*step)
+	}
+	return result, step
+}
+
+// formatValue writes a value with the precision of the step
+func formatValue(value, step float64) string {
+	decimals := 0
+	if step < 1 {
+		decimals = int(math.Ceil(-math.Log10(step) - 1e-9))
+	}
+	result := strconv.FormatFloat(value, 'f', decimals, 64)
+	if result == "-"+strconv.FormatFloat(0, 'f', decimals, 64) {
+		return result[1:]
+	}
+	return result
+}
+
+// coord formats a position in pixels
+func coord(value float64) string {
+	return strconv.FormatFloat(value, 'f', 1, 64)
+}
@@ -0,0 +1,113 @@
+package svg
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/sbiemont/fugologic/crisp"
+	"github.com/sbiemont/fugologic/fuzzy"
+	"github.com/sbiemont/fugologic/id"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// elements counts the SVG elements by name, and checks the document is well-formed
+func elements(text string) map[string]int {
+	result := make(map[string]int)
+	dec := xml.NewDecoder(strings.NewReader(text))
+	for {
+		token, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			return result
+		}
+		So(err, ShouldBeNil)
+		if start, ok := token.(xml.StartElement); ok {
+			result[start.Name.Local]++
+		}
+	}
+}
+
+func TestWrite(t *testing.T) {
+	Convey("write", t, func() {
+		u, err := crisp.NewSet(0, 30, 0.5)
+		So(err, ShouldBeNil)
+		fvTip, err := fuzzy.NewIDValBuilders("tip", u, map[id.ID]fuzzy.SetBuilder{
+			"cheap":     fuzzy.Triangular{A: 0, B: 5, C: 10},
+			"average":   fuzzy.Triangular{A: 10, B: 15, C: 20},
+			"<generous": fuzzy.Triangular{A: 20, B: 25, C: 30},
+		})
+		So(err, ShouldBeNil)
+
+		Convey("when value", func() {
+			var sb strings.Builder
+			So(WriteIDVal(&sb, fvTip), ShouldBeNil)
+			text := sb.String()
+			So(text, ShouldStartWith, `<svg xmlns="http://www.w3.org/2000/svg" width="640.0" height="320.0"`)
+			So(text, ShouldContainSubstring, `>tip</text>`)
+			So(text, ShouldContainSubstring, `>cheap</text>`)
+			So(text, ShouldContainSubstring, `>&lt;generous</text>`)
+			So(text, ShouldContainSubstring, `>25</text>`) // x tick
+
+			elts := elements(text)
+			So(elts["polyline"], ShouldEqual, 3)
+			So(elts["polygon"], ShouldEqual, 0)
+		})
+
+		Convey("when output", func() {
+			trace := fuzzy.Trace{
+				Output:     fuzzy.DataOutput{fvTip: 15},
+				Aggregated: map[*fuzzy.IDVal]fuzzy.Set{fvTip: fvTip.Get("average").Set().Min(0.5)},
+			}
+			var sb strings.Builder
+			So(Plot{Width: 400, Height: 200}.WriteOutput(&sb, fvTip, trace), ShouldBeNil)
+			text := sb.String()
+			So(text, ShouldStartWith, `<svg xmlns="http://www.w3.org/2000/svg" width="400.0" height="200.0"`)
+			So(text, ShouldContainSubstring, `>tip = 15.000</text>`)
+			So(text, ShouldContainSubstring, `<line x1="215.0" y1="160.0" x2="215.0" y2="40.0" stroke="#d62728"`) // marker
+
+			elts := elements(text)
+			So(elts["polyline"], ShouldEqual, 3)
+			So(elts["polygon"], ShouldEqual, 1)
+		})
+
+		Convey("when output not evaluated", func() {
+			err := WriteOutput(io.Discard, fvTip, fuzzy.Trace{})
+			So(err, ShouldBeError, "svg: no aggregated set for `tip`")
+		})
+
+		Convey("when plot too small", func() {
+			err := Plot{Width: 10, Height: 10}.WriteIDVal(io.Discard, fvTip)
+			So(err, ShouldBeError, "svg: plot size 10x10 too small")
+		})
+
+		Convey("when nil value", func() {
+			So(WriteIDVal(io.Discard, nil), ShouldBeError, "svg: nil value")
+		})
+	})
+}
+
+func TestTicks(t *testing.T) {
+	Convey("ticks", t, func() {
+		Convey("when integers", func() {
+			values, step := ticks(0, 30, 8)
+			So(step, ShouldEqual, 5)
+			So(values, ShouldResemble, []float64{0, 5, 10, 15, 20, 25, 30})
+		})
+
+		Convey("when decimals", func() {
+			values, step := ticks(-0.2, 0.2, 8)
+			So(step, ShouldEqual, 0.05)
+			So(values, ShouldHaveLength, 9)
+			So(formatValue(values[0], step), ShouldEqual, "-0.20")
+			So(formatValue(values[4], step), ShouldEqual, "0.00")
+		})
+
+		Convey("when empty interval", func() {
+			values, _ := ticks(1, 1, 8)
+			So(values, ShouldResemble, []float64{1})
+		})
+	})
+}
@@ -0,0 +1,96 @@
+// Package sweep evaluates an engine or a system over the universes of one or two inputs
+// The result describes a response curve (1 input) or a control surface (2 inputs)
+package sweep
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/sbiemont/fugologic/fuzzy"
+)
+
+// Table holds one row per evaluation
+// Each row contains the swept inputs values followed by the outputs values
+type Table struct {
+	Columns []string    // ids of the swept inputs then ids of the outputs
+	Inputs  int         // number of swept inputs (first columns)
+	Rows    [][]float64 // one row per evaluation
+}
+
+// Sweep evaluates all combinations of values of the swept inputs (1 or 2) over their universes
+// The other inputs are held fixed; the outputs are sorted by id
+// For 2 inputs, the second one varies first
+func Sweep(ev fuzzy.Evaluator, fixed fuzzy.DataInput, inputs ...*fuzzy.IDVal) (Table, error) {
+	if len(inputs) < 1 || len(inputs) > 2 {
+		return Table{}, fmt.Errorf("sweep: 1 or 2 inputs expected (found: %d)", len(inputs))
+	}
+	if len(inputs) == 2 && inputs[0] == inputs[1] {
+		return Table{}, fmt.Errorf("sweep: input `%s` swept twice", inputs[0].ID())
+	}
+
+	// Values of each swept input
+	points := [][]float64{nil}
+	for _, input := range inputs {
+		if input == nil {
+			return Table{}, errors.New("sweep: nil input")
+		}
+		var next [][]float64
+		for _, point := range points {
+			for _, x := range input.U().Values() {
+				next = append(next, append(append([]float64{}, point...), x))
+			}
+		}
+		points = next
+	}
+
+	// Evaluate each point
+	var outputs []*fuzzy.IDVal
+	rows := make([][]float64, len(points))
+	for i, point := range points {
+		data := make(fuzzy.DataInput, len(fixed)+len(inputs))
+		for idVal, value := range fixed {
+			data[idVal] = value
+		}
+		for j, input := range inputs {
+			data[input] = point[j]
+		}
+
+		result, err := ev.Evaluate(data)
+		if err != nil {
+			return Table{}, fmt.Errorf("sweep: %w", err)
+		}
+		if outputs == nil {
+			outputs = sortedIDVals(result)
+		}
+
+		rows[i] = point
+		for _, output := range outputs {
+			rows[i] = append(rows[i], result[output])
+		}
+	}
+
+	// Header
+	columns := make([]string, 0, len(inputs)+len(outputs))
+	for _, idVal := range append(append([]*fuzzy.IDVal{}, inputs...), outputs...) {
+		columns = append(columns, string(idVal.ID()))
+	}
+
+	return Table{
+		Columns: columns,
+		Inputs:  len(inputs),
+		Rows:    rows,
+	}, nil
+}
+
+// sortedIDVals returns the values of the output, sorted by id
+func sortedIDVals(output fuzzy.DataOutput) []*fuzzy.IDVal {
+	result := make([]*fuzzy.IDVal, 0, len(output))
+	for idVal := range output {
+		result = append(result, idVal)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID() < result[j].ID()
+	})
+	return result
+}
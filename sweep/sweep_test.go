@@ -0,0 +1,133 @@
+package sweep
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/sbiemont/fugologic/crisp"
+	"github.com/sbiemont/fugologic/fuzzy"
+	"github.com/sbiemont/fugologic/id"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// evaluator is a test evaluator based on a function
+type evaluator func(input fuzzy.DataInput) (fuzzy.DataOutput, error)
+
+func (ev evaluator) Evaluate(input fuzzy.DataInput) (fuzzy.DataOutput, error) {
+	return ev(input)
+}
+
+func newTestVal(name id.ID, xmin, xmax, dx float64) *fuzzy.IDVal {
+	u, err := crisp.NewSet(xmin, xmax, dx)
+	So(err, ShouldBeNil)
+	idVal, err := fuzzy.NewIDValBuilders(name, u, map[id.ID]fuzzy.SetBuilder{
+		id.ID(name) + "1": fuzzy.StepUp{A: xmin, B: xmax},
+	})
+	So(err, ShouldBeNil)
+	return idVal
+}
+
+func TestSweep(t *testing.T) {
+	Convey("sweep", t, func() {
+		fvA := newTestVal("a", 0, 2, 1)
+		fvB := newTestVal("b", 0, 1, 1)
+		fvC := newTestVal("c", 0, 1, 1)
+		fvY := newTestVal("y", 0, 100, 1)
+		fvZ := newTestVal("z", 0, 100, 1)
+
+		// y = a + 10.b + 100.c; z = -y
+		ev := evaluator(func(input fuzzy.DataInput) (fuzzy.DataOutput, error) {
+			for _, idVal := range []*fuzzy.IDVal{fvA, fvB, fvC} {
+				if _, ok := input[idVal]; !ok {
+					return nil, errors.New("missing input")
+				}
+			}
+			y := input[fvA] + 10*input[fvB] + 100*input[fvC]
+			return fuzzy.DataOutput{fvZ: -y, fvY: y}, nil
+		})
+
+		Convey("when 1 input", func() {
+			tbl, err := Sweep(ev, fuzzy.DataInput{fvB: 1, fvC: 0}, fvA)
+			So(err, ShouldBeNil)
+			So(tbl, ShouldResemble, Table{
+				Columns: []string{"a", "y", "z"},
+				Inputs:  1,
+				Rows: [][]float64{
+					{0, 10, -10},
+					{1, 11, -11},
+					{2, 12, -12},
+				},
+			})
+		})
+
+		Convey("when 2 inputs", func() {
+			tbl, err := Sweep(ev, fuzzy.DataInput{fvC: 1}, fvA, fvB)
+			So(err, ShouldBeNil)
+			So(tbl, ShouldResemble, Table{
+				Columns: []string{"a", "b", "y", "z"},
+				Inputs:  2,
+				Rows: [][]float64{
+					{0, 0, 100, -100},
+					{0, 1, 110, -110},
+					{1, 0, 101, -101},
+					{1, 1, 111, -111},
+					{2, 0, 102, -102},
+					{2, 1, 112, -112},
+				},
+			})
+
+			Convey("then write csv", func() {
+				var sb strings.Builder
+				So(tbl.WriteCSV(&sb), ShouldBeNil)
+				So(sb.String(), ShouldEqual, "a,b,y,z\n"+
+					"0,0,100,-100\n"+
+					"0,1,110,-110\n"+
+					"1,0,101,-101\n"+
+					"1,1,111,-111\n"+
+					"2,0,102,-102\n"+
+					"2,1,112,-112\n")
+			})
+
+			Convey("then write gnuplot", func() {
+				var sb strings.Builder
+				So(tbl.WriteGnuplot(&sb, "surface"), ShouldBeNil)
+				So(sb.String(), ShouldEqual, "$surface << EOD\n"+
+					"# a b y z\n"+
+					"0 0 100 -100\n"+
+					"0 1 110 -110\n"+
+					"\n"+
+					"1 0 101 -101\n"+
+					"1 1 111 -111\n"+
+					"\n"+
+					"2 0 102 -102\n"+
+					"2 1 112 -112\n"+
+					"EOD\n")
+			})
+		})
+
+		Convey("when swept input also fixed", func() {
+			tbl, err := Sweep(ev, fuzzy.DataInput{fvA: 5, fvB: 0, fvC: 0}, fvA)
+			So(err, ShouldBeNil)
+			So(tbl.Rows[2], ShouldResemble, []float64{2, 2, -2})
+		})
+
+		Convey("when wrong number of inputs", func() {
+			_, err := Sweep(ev, nil)
+			So(err, ShouldBeError, "sweep: 1 or 2 inputs expected (found: 0)")
+			_, err = Sweep(ev, nil, fvA, fvB, fvC)
+			So(err, ShouldBeError, "sweep: 1 or 2 inputs expected (found: 3)")
+		})
+
+		Convey("when same input twice", func() {
+			_, err := Sweep(ev, nil, fvA, fvA)
+			So(err, ShouldBeError, "sweep: input `a` swept twice")
+		})
+
+		Convey("when evaluation error", func() {
+			_, err := Sweep(ev, nil, fvA)
+			So(err, ShouldBeError, "sweep: missing input")
+		})
+	})
+}
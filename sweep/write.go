@@ -0,0 +1,50 @@
+package sweep
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// WriteCSV writes the table as CSV, the first row contains the columns ids
+func (tbl Table) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(tbl.Columns); err != nil {
+		return err
+	}
+	for _, row := range tbl.Rows {
+		if err := cw.Write(format(row)); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteGnuplot writes the table as a named gnuplot data block (eg.: `$name << EOD`)
+// For 2 swept inputs, scans are separated by a blank line to be used with `splot`
+func (tbl Table) WriteGnuplot(w io.Writer, name string) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "$%s << EOD\n", name)
+	fmt.Fprintf(bw, "# %s\n", strings.Join(tbl.Columns, " "))
+	for i, row := range tbl.Rows {
+		if tbl.Inputs == 2 && i > 0 && row[0] != tbl.Rows[i-1][0] {
+			fmt.Fprintln(bw)
+		}
+		fmt.Fprintln(bw, strings.Join(format(row), " "))
+	}
+	fmt.Fprintln(bw, "EOD")
+	return bw.Flush()
+}
+
+// format converts a row of floats into strings
+func format(row []float64) []string {
+	result := make([]string, len(row))
+	for i, value := range row {
+		result[i] = strconv.FormatFloat(value, 'g', -1, 64)
+	}
+	return result
+}